@@ -0,0 +1,109 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// FromSub selects from the derived table "(sub) as alias" instead of a
+// table. The subquery shares the outer placeholder numbering; GetTable
+// reports its table.
+func (b *QueryBuilder) FromSub(sub *QueryBuilder, alias string) *QueryBuilder {
+	b.fromSub = sub
+	b.table = sub.table
+	b.tableAlias = alias
+	return b
+}
+
+// ValidateAliases makes TryBuild check every qualified column reference -
+// in the select list, where, group by, order by and join conditions -
+// against the declared relations, reporting ErrUnknownAlias for a
+// qualifier that is neither. A table aliased with As, JoinAs or FromSub is
+// known only by its alias, as in Postgres.
+func (b *QueryBuilder) ValidateAliases() *QueryBuilder {
+	b.checkAliases = true
+	return b
+}
+
+// relationNames returns the names columns can be qualified with: each
+// relation's alias, or its table when it has none
+func (b *QueryBuilder) relationNames() []string {
+	var names []string
+	add := func(table, alias string) {
+		if fields := strings.Fields(table); len(fields) == 2 {
+			table, alias = fields[0], fields[1]
+		}
+		if alias != "" {
+			names = append(names, aliasName(alias))
+			return
+		}
+		names = append(names, table)
+		// A schema-qualified table is also known by its bare name
+		if i := strings.LastIndex(table, "."); i >= 0 {
+			names = append(names, table[i+1:])
+		}
+	}
+	add(b.table, b.tableAlias)
+	for _, join := range b.joinClauses {
+		add(join.Table, join.Alias)
+	}
+	if b.updateFrom != nil {
+		names = append(names, unnestAlias)
+	}
+	return names
+}
+
+// hasRelation reports whether columns can be qualified with name
+func (b *QueryBuilder) hasRelation(name string) bool {
+	return slices.Contains(b.relationNames(), name)
+}
+
+var qualifiedPattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*)\.(?:[A-Za-z_][A-Za-z0-9_]*|\*)`)
+
+func (b *QueryBuilder) validateAliases() error {
+	if !b.checkAliases {
+		return nil
+	}
+
+	var columns []string
+	if b.queryType == SelectQuery {
+		for _, column := range b.columns {
+			columns = append(columns, columnName(column))
+		}
+	}
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		if where.Column != "" {
+			columns = append(columns, where.Column)
+		}
+	})
+	columns = append(columns, b.groupBy...)
+	if b.order != "" {
+		for _, term := range strings.Split(b.order, ",") {
+			if fields := strings.Fields(term); len(fields) > 0 {
+				columns = append(columns, fields[0])
+			}
+		}
+	}
+
+	var qualifiers []string
+	for _, column := range columns {
+		if i := strings.LastIndex(column, "."); i > 0 && isIdentifier(column) {
+			qualifiers = append(qualifiers, column[:i])
+		}
+	}
+	for _, join := range b.joinClauses {
+		for _, match := range qualifiedPattern.FindAllStringSubmatch(join.Condition, -1) {
+			qualifiers = append(qualifiers, match[1])
+		}
+	}
+
+	names := b.relationNames()
+	for _, qualifier := range qualifiers {
+		if !slices.Contains(names, qualifier) {
+			return fmt.Errorf("%w: %q is not one of %s", ErrUnknownAlias, qualifier, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateAliases(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("users").
+		As("usr").
+		Select("u.name").
+		ValidateAliases().
+		TryBuild()
+	if !errors.Is(err, ErrUnknownAlias) {
+		t.Errorf("Expected ErrUnknownAlias, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("users").
+		As("u").
+		LeftJoinAs("orders", "o", "o.user_id = u.id").
+		Join("public.teams", "teams.id = u.team_id").
+		Select("u.name", "o.total as total", "teams.*").
+		Where("o.status", "=", "paid").
+		OrderBy("u.name desc").
+		ValidateAliases().
+		TryBuild()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidateAliasesJoinCondition(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("users").
+		As("u").
+		JoinAs("orders", "o", "o.user_id = users.id").
+		ValidateAliases().
+		TryBuild()
+	if !errors.Is(err, ErrUnknownAlias) {
+		t.Errorf("Expected ErrUnknownAlias, got: %v", err)
+	}
+}
+
+func TestFromSub(t *testing.T) {
+	recent := NewQueryBuilder().
+		Table("orders").
+		Select("user_id", "total").
+		Where("created_at", ">", "2024-01-01")
+
+	query := NewQueryBuilder().
+		FromSub(recent, "r").
+		Select("r.user_id").
+		Where("r.total", ">", 100).
+		ValidateAliases().
+		Build()
+
+	expectedSQL := "select r.user_id from (select user_id, total from orders where created_at > $1) as r where r.total > $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestGetAliases(t *testing.T) {
+	qb := NewQueryBuilder().Table("users").As("u").LeftJoinAs("orders", "o", "o.user_id = u.id").Join("teams", "teams.id = u.team_id")
+	if aliases := qb.GetAliases(); !reflect.DeepEqual(aliases, []string{"u", "o"}) {
+		t.Errorf("Unexpected aliases: %v", aliases)
+	}
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotAllowed is returned by a Runner with RequireAllowed for statements
+// whose fingerprint was never registered with Allow
+var ErrNotAllowed = errors.New("query not in allow-list")
+
+var (
+	allowedMu sync.RWMutex
+	allowed   = map[string]bool{}
+)
+
+// Allow registers query fingerprints (Query.Fingerprint) as safe to run
+// on Runners created with RequireAllowed or ReportUnallowed. It is meant to
+// be called at startup, for example from a generated list of every query
+// the application builds.
+func Allow(fingerprints ...string) {
+	allowedMu.Lock()
+	defer allowedMu.Unlock()
+	for _, fingerprint := range fingerprints {
+		allowed[fingerprint] = true
+	}
+}
+
+// AllowQuery builds each builder and registers its fingerprint with Allow
+func AllowQuery(builders ...Builder) error {
+	for _, qb := range builders {
+		q, err := qb.TryBuild()
+		if err != nil {
+			return err
+		}
+		Allow(q.Fingerprint())
+	}
+	return nil
+}
+
+// IsAllowed reports whether q's fingerprint was registered with Allow
+func IsAllowed(q Query) bool {
+	fingerprint := q.Fingerprint()
+	allowedMu.RLock()
+	defer allowedMu.RUnlock()
+	return allowed[fingerprint]
+}
+
+// RequireAllowed makes the Runner reject, with ErrNotAllowed, any statement
+// whose fingerprint was not registered with Allow. Parameter values do not
+// affect the fingerprint, but the shape of the SQL does, including the
+// number of values in a WhereIn.
+func RequireAllowed() RunnerOption {
+	return func(r *Runner) {
+		r.allowList = true
+	}
+}
+
+// ReportUnallowed logs statements whose fingerprint was not registered with
+// Allow but still runs them, for rolling out an allow-list
+func ReportUnallowed(logger Logger) RunnerOption {
+	return func(r *Runner) {
+		r.allowListLogger = logger
+	}
+}
+
+func (r *Runner) checkAllowed(q Query) error {
+	if !r.allowList && r.allowListLogger == nil {
+		return nil
+	}
+	if IsAllowed(q) {
+		return nil
+	}
+	normalized := q.Normalize()
+	if r.allowListLogger != nil {
+		r.allowListLogger.Printf("query: statement %s not in allow-list: %s", normalized.Hash, normalized.SQL)
+	}
+	if r.allowList {
+		return fmt.Errorf("%w: %s", ErrNotAllowed, normalized.Hash)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRequireAllowed(t *testing.T) {
+	db, fake := newFakeDB(t)
+	runner := NewRunner(db, RequireAllowed())
+	ctx := context.Background()
+
+	byID := func(id int) *QueryBuilder {
+		return NewQueryBuilder().Table("allow_accounts").Where("id", "=", id)
+	}
+	if err := AllowQuery(byID(0)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := runner.Exec(ctx, byID(42)); err != nil {
+		t.Errorf("Expected registered query to run with other params, got: %v", err)
+	}
+
+	other := NewQueryBuilder().Table("allow_accounts").Where("email", "=", "a@example.com")
+	if _, err := runner.Exec(ctx, other); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Expected ErrNotAllowed, got: %v", err)
+	}
+
+	if calls := fake.Calls(); len(calls) != 1 {
+		t.Errorf("Expected only the allowed statement to run, got: %+v", calls)
+	}
+}
+
+func TestReportUnallowed(t *testing.T) {
+	db, fake := newFakeDB(t)
+	logger := &recordingLogger{}
+	runner := NewRunner(db, ReportUnallowed(logger))
+
+	qb := NewQueryBuilder().Table("allow_reports").Where("id", "=", 1)
+	if _, err := runner.Exec(context.Background(), qb); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(fake.Calls()) != 1 {
+		t.Errorf("Expected the statement to run, got: %+v", fake.Calls())
+	}
+	if len(logger.messages) != 1 {
+		t.Errorf("Expected one allow-list warning, got: %v", logger.messages)
+	}
+
+	Allow(qb.Build().Fingerprint())
+	if !IsAllowed(qb.Build()) {
+		t.Errorf("Expected query to be allowed after Allow")
+	}
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Hex returns b in the bytea hex text format, "\x" followed by two lowercase
+// hex digits per byte, for drivers and tools that only pass text but feed
+// a bytea column. Bound []byte params never need it.
+func Hex(b []byte) string {
+	return `\x` + hex.EncodeToString(b)
+}
+
+// DecodeBytea parses bytea text output: the hex format ("\xdead") Postgres
+// uses by default, or the older escape format, where a backslash is
+// followed by three octal digits or another backslash. Use it when bytea
+// columns are read as text, e.g. from COPY or a text-only proxy.
+func DecodeBytea(s string) ([]byte, error) {
+	if strings.HasPrefix(s, `\x`) {
+		b, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return nil, fmt.Errorf("query: invalid hex bytea: %w", err)
+		}
+		return b, nil
+	}
+
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b = append(b, s[i])
+			continue
+		}
+		switch {
+		case i+1 < len(s) && s[i+1] == '\\':
+			b = append(b, '\\')
+			i++
+		case i+3 < len(s) && isOctal(s[i+1]) && isOctal(s[i+2]) && isOctal(s[i+3]) && s[i+1] <= '3':
+			b = append(b, (s[i+1]-'0')<<6|(s[i+2]-'0')<<3|(s[i+3]-'0'))
+			i += 3
+		default:
+			return nil, fmt.Errorf("query: invalid bytea escape at offset %d", i)
+		}
+	}
+	return b, nil
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
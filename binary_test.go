@@ -0,0 +1,66 @@
+package query
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHex(t *testing.T) {
+	if got := Hex([]byte{0xde, 0xad, 0x00, 0x5c}); got != `\xdead005c` {
+		t.Errorf("Expected hex bytea, got: %s", got)
+	}
+	if got := Hex(nil); got != `\x` {
+		t.Errorf("Expected empty hex bytea, got: %s", got)
+	}
+}
+
+func TestDecodeBytea(t *testing.T) {
+	tests := map[string][]byte{
+		`\xdead005c`:   {0xde, 0xad, 0x00, 0x5c},
+		`\x`:           {},
+		`ab\000\\\377`: {'a', 'b', 0x00, '\\', 0xff},
+		`plain`:        []byte("plain"),
+	}
+	for input, expected := range tests {
+		got, err := DecodeBytea(input)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", input, err)
+			continue
+		}
+		if !bytes.Equal(got, expected) {
+			t.Errorf("Expected %v for %s, got: %v", expected, input, got)
+		}
+	}
+
+	for _, input := range []string{`\xzz`, `a\9`, `a\`, `\400`} {
+		if _, err := DecodeBytea(input); err == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+}
+
+func TestBytesNeverEscapedAsText(t *testing.T) {
+	data := []byte("O'Brien")
+	query := NewQueryBuilder().Table("files").Where("data", "=", data).Build()
+	if _, ok := query.Params[0].([]byte); !ok {
+		t.Errorf("Expected bytes to be bound as bytes, got: %#v", query.Params[0])
+	}
+
+	sql, err := NewQueryBuilder().Table("files").Where("data", "=", data).BuildInterpolated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := `select * from files where data = '\x4f27427269656e'::bytea`
+	if sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+
+	sql, err = NewQueryBuilder().Table("files").Update(map[string]interface{}{"data": []byte(nil)}).BuildInterpolated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL = "update files set data = null"
+	if sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+}
@@ -0,0 +1,117 @@
+package query
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BuildHook adjusts a builder right before it is built, from values stored
+// in ctx - a tenant scope, a locale, an audit Comment with the user id. ctx
+// is the one given to WithBuildContext, or the Runner's when it runs a
+// builder without one, and context.Background otherwise. Hooks work on a
+// copy, so the builder itself is not changed and building it twice gives
+// the same query; a hook's error is returned by TryBuild.
+type BuildHook func(ctx context.Context, q *QueryBuilder) error
+
+type registeredHook struct {
+	id   int
+	hook BuildHook
+}
+
+var (
+	buildHooksMu sync.RWMutex
+	buildHooks   []registeredHook
+	buildHookID  int
+)
+
+// OnBuild registers hook for every builder, run in registration order and
+// ahead of the builder's own hooks (see Factory WithBuildHook). It applies
+// to TryBuild and the branches of unions and feeds, not to subqueries.
+// Calling remove unregisters it.
+func OnBuild(hook BuildHook) (remove func()) {
+	buildHooksMu.Lock()
+	defer buildHooksMu.Unlock()
+	buildHookID++
+	id := buildHookID
+	buildHooks = append(buildHooks, registeredHook{id: id, hook: hook})
+	return func() {
+		buildHooksMu.Lock()
+		defer buildHooksMu.Unlock()
+		buildHooks = slices.DeleteFunc(buildHooks, func(h registeredHook) bool { return h.id == id })
+	}
+}
+
+// WithBuildContext sets the context the build hooks of qb receive and
+// returns qb
+func WithBuildContext(ctx context.Context, qb *QueryBuilder) *QueryBuilder {
+	qb.ctx = ctx
+	return qb
+}
+
+// Comment appends "/* text */" to the statement, e.g. an audit trail of
+// the user or request behind it. "*/" in text is escaped. Normalize and
+// fingerprints ignore comments.
+func (b *QueryBuilder) Comment(text string) *QueryBuilder {
+	b.comments = append(b.comments, strings.ReplaceAll(text, "*/", "* /"))
+	return b
+}
+
+func (b *QueryBuilder) writeComments(w *sqlWriter) {
+	for _, comment := range b.comments {
+		w.raw(" /* ")
+		w.raw(comment)
+		w.raw(" */")
+	}
+}
+
+// hasBuildHooks reports whether any hook would run when b is built
+func (b *QueryBuilder) hasBuildHooks() bool {
+	if b.hooked {
+		return false
+	}
+	buildHooksMu.RLock()
+	defer buildHooksMu.RUnlock()
+	return len(buildHooks) > 0 || len(b.buildHooks) > 0
+}
+
+// withBuildHooks returns a copy of b with the build hooks applied, or b
+// itself when there are none
+func (b *QueryBuilder) withBuildHooks() (*QueryBuilder, error) {
+	if !b.hasBuildHooks() {
+		return b, nil
+	}
+	buildHooksMu.RLock()
+	hooks := make([]BuildHook, 0, len(buildHooks)+len(b.buildHooks))
+	for _, h := range buildHooks {
+		hooks = append(hooks, h.hook)
+	}
+	buildHooksMu.RUnlock()
+	hooks = append(hooks, b.buildHooks...)
+
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c := b.Clone()
+	c.hooked = true
+	for _, hook := range hooks {
+		if err := hook(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// hookBranches applies the build hooks to each branch of a union or feed
+func hookBranches(branches []*QueryBuilder) ([]*QueryBuilder, error) {
+	hooked := make([]*QueryBuilder, len(branches))
+	for i, branch := range branches {
+		var err error
+		if hooked[i], err = branch.withBuildHooks(); err != nil {
+			return nil, err
+		}
+	}
+	return hooked, nil
+}
@@ -0,0 +1,105 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func tenantScope(ctx context.Context, q *QueryBuilder) error {
+	tenant, ok := ctx.Value(tenantKey{}).(int)
+	if !ok {
+		return errors.New("no tenant in context")
+	}
+	q.Where("tenant_id", "=", tenant).Comment("tenant=" + strconv.Itoa(tenant))
+	return nil
+}
+
+func TestWithBuildContext(t *testing.T) {
+	factory := NewFactory(WithBuildHook(tenantScope))
+	ctx := context.WithValue(context.Background(), tenantKey{}, 7)
+
+	qb := WithBuildContext(ctx, factory.Table("invoices").Where("paid", "=", false))
+	for i := 0; i < 2; i++ {
+		query := qb.Build()
+		expectedSQL := "select * from invoices where paid = $1 and tenant_id = $2 /* tenant=7 */"
+		if query.SQL != expectedSQL {
+			t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+		}
+		if len(query.Params) != 2 || query.Params[1] != 7 {
+			t.Errorf("Expected params: [false 7], got: %v", query.Params)
+		}
+	}
+	if len(qb.whereClauses) != 1 {
+		t.Errorf("Expected hooks to leave the builder unchanged, got %d conditions", len(qb.whereClauses))
+	}
+
+	if _, err := factory.Table("invoices").TryBuild(); err == nil {
+		t.Error("Expected the hook's error without a tenant in context")
+	}
+}
+
+func TestOnBuildRunnerContext(t *testing.T) {
+	var seen []string
+	remove := OnBuild(func(ctx context.Context, q *QueryBuilder) error {
+		seen = append(seen, "global")
+		return nil
+	})
+	defer remove()
+
+	db, fake := newFakeDB(t)
+	fake.On("select * from invoices where tenant_id = $1 /* tenant=3 */", []string{"id"}, []driver.Value{int64(1)})
+
+	factory := NewFactory(WithBuildHook(func(ctx context.Context, q *QueryBuilder) error {
+		seen = append(seen, "factory")
+		return tenantScope(ctx, q)
+	}))
+	ctx := context.WithValue(context.Background(), tenantKey{}, 3)
+	rows, err := NewRunner(db).Query(ctx, factory.Table("invoices"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows.Close()
+	if len(seen) != 2 || seen[0] != "global" || seen[1] != "factory" {
+		t.Errorf("Expected global then factory hooks, got: %v", seen)
+	}
+
+	remove()
+	seen = nil
+	NewQueryBuilder().Table("invoices").Build()
+	if len(seen) != 0 {
+		t.Errorf("Expected no hooks after remove, got: %v", seen)
+	}
+}
+
+func TestBuildHooksOnUnionBranches(t *testing.T) {
+	factory := NewFactory(WithBuildHook(tenantScope))
+	ctx := context.WithValue(context.Background(), tenantKey{}, 1)
+	query := Union(
+		WithBuildContext(ctx, factory.Table("invoices").Select("id")),
+		WithBuildContext(ctx, factory.Table("credits").Select("id")),
+	).Build()
+	expectedSQL := "select id from invoices where tenant_id = $1 union select id from credits where tenant_id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestCommentNormalize(t *testing.T) {
+	a := NewQueryBuilder().Table("users").Where("id", "=", 1).Comment("user=1").Build()
+	b := NewQueryBuilder().Table("users").Where("id", "=", 2).Comment("user=2 */ drop").Build()
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("Expected comments not to change the fingerprint: %s vs %s", a.Normalize().SQL, b.Normalize().SQL)
+	}
+	expectedSQL := "select * from users where id = $1 /* user=2 * / drop */"
+	if b.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, b.SQL)
+	}
+	if !readOnlySQL("select * from users /* delete requested */") {
+		t.Error("Expected words in comments to be ignored by the read-only check")
+	}
+}
@@ -0,0 +1,140 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrColumnCount is returned by TryBuild when an insert row does not have
+// one value per column
+var ErrColumnCount = errors.New("insert row does not match columns")
+
+// AddRow appends a row to a multi-row insert, in InsertColumns order:
+// "insert into t (a, b) values ($1, $2), ($3, $4)". Rows set with Values
+// come first.
+func (b *QueryBuilder) AddRow(values ...interface{}) *QueryBuilder {
+	b.queryType = InsertQuery
+	b.insertRows = append(b.insertRows, values)
+	return b
+}
+
+// rows returns every insert row
+func (b *QueryBuilder) rows() [][]interface{} {
+	if b.insertValues == nil {
+		return b.insertRows
+	}
+	return append([][]interface{}{b.insertValues}, b.insertRows...)
+}
+
+func (b *QueryBuilder) validateRows() error {
+	if b.queryType != InsertQuery || len(b.insertRows) == 0 {
+		return nil
+	}
+	for i, row := range b.rows() {
+		if len(row) != len(b.insertColumns) {
+			return fmt.Errorf("%w: row %d has %d values for %d columns", ErrColumnCount, i+1, len(row), len(b.insertColumns))
+		}
+	}
+	return nil
+}
+
+// maxParams is the most bind parameters one statement may carry
+func (d Dialect) maxParams() int {
+	switch d {
+	case Postgres, MySQL:
+		return 65535
+	default:
+		return 0
+	}
+}
+
+// MaxParams overrides the bind parameter limit the Runner splits inserts
+// at, for drivers or databases with lower limits (SQL Server allows about
+// 2100). A limit of 0 disables splitting.
+func MaxParams(n int) RunnerOption {
+	return func(r *Runner) {
+		r.maxParams = n
+		r.maxParamsSet = true
+	}
+}
+
+// insertChunks splits a multi-row insert into builders of at most limit
+// params each. It returns nil when no split is needed.
+func (b *QueryBuilder) insertChunks(limit int) []*QueryBuilder {
+	columns := len(b.insertColumns)
+	rows := b.rows()
+	if limit <= 0 || columns == 0 || len(rows)*columns <= limit {
+		return nil
+	}
+
+	perChunk := max(limit/columns, 1)
+	var chunks []*QueryBuilder
+	for start := 0; start < len(rows); start += perChunk {
+		chunk := b.Clone()
+		chunk.insertValues = nil
+		chunk.insertRows = rows[start:min(start+perChunk, len(rows))]
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// execChunks runs the chunks of a split insert in one transaction, when
+// the Runner's DB can begin one; a *sql.Tx is used as is
+func (r *Runner) execChunks(ctx context.Context, chunks []*QueryBuilder) (sql.Result, error) {
+	var result chunkedResult
+	run := func(tx *Runner) error {
+		for _, chunk := range chunks {
+			q, err := tx.prepare(ctx, chunk)
+			if err != nil {
+				return err
+			}
+			done := tx.track(ctx, chunk, q)
+			res, err := tx.db.ExecContext(ctx, q.SQL, q.Params...)
+			done()
+			if err != nil {
+				return err
+			}
+			if err := result.add(res); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	r = r.routed(chunks[0])
+	var err error
+	if _, ok := r.db.(interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	}); ok {
+		err = r.RunInTx(ctx, TxOptions{}, run)
+	} else {
+		err = run(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// chunkedResult sums the rows affected by every chunk of a split insert
+// and reports the last insert id of the final chunk
+type chunkedResult struct {
+	lastInsertID int64
+	lastIDErr    error
+	rowsAffected int64
+}
+
+func (r *chunkedResult) add(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	r.rowsAffected += n
+	r.lastInsertID, r.lastIDErr = res.LastInsertId()
+	return nil
+}
+
+func (r chunkedResult) LastInsertId() (int64, error) { return r.lastInsertID, r.lastIDErr }
+func (r chunkedResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
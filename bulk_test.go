@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAddRow(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("tags").
+		InsertColumns("name", "color").
+		Values("go", "blue").
+		AddRow("sql", "green").
+		AddRow("db", "red").
+		Build()
+
+	expectedSQL := "insert into tags (name, color) values ($1, $2), ($3, $4), ($5, $6)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 6 || query.Params[4] != "db" {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestAddRowColumnCount(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("tags").
+		InsertColumns("name", "color").
+		AddRow("go").
+		TryBuild()
+	if !errors.Is(err, ErrColumnCount) {
+		t.Errorf("Expected ErrColumnCount, got: %v", err)
+	}
+}
+
+func TestRunnerSplitsLargeInserts(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.OnExec("insert into tags (name, color) values ($1, $2), ($3, $4)", 2)
+
+	qb := NewQueryBuilder().Table("tags").InsertColumns("name", "color")
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		qb.AddRow(name, "blue")
+	}
+
+	result, err := NewRunner(db, MaxParams(4)).Exec(context.Background(), qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 statements, got: %+v", calls)
+	}
+	if calls[2].SQL != "insert into tags (name, color) values ($1, $2)" || calls[2].Args[0] != "e" {
+		t.Errorf("Unexpected last chunk: %+v", calls[2])
+	}
+	if n, _ := result.RowsAffected(); n != 5 {
+		t.Errorf("Expected 5 rows affected, got: %d", n)
+	}
+}
+
+func TestInsertChunksWithinLimit(t *testing.T) {
+	qb := NewQueryBuilder().Table("tags").InsertColumns("name").AddRow("a").AddRow("b")
+	if chunks := qb.insertChunks(65535); chunks != nil {
+		t.Errorf("Expected no split, got %d chunks", len(chunks))
+	}
+}
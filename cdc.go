@@ -0,0 +1,216 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrCaptureOutsideTx is returned by Runner.Exec when a CaptureChanges
+// statement has to select its rows first but is not run in a transaction,
+// where the rows could change between the select and the statement
+var ErrCaptureOutsideTx = errors.New("captured change outside a transaction")
+
+// RowChange describes the rows touched by an update or delete built with
+// CaptureChanges. Before holds the rows as they were and After, for
+// updates, the rows as they are now. Updates, and deletes on MySQL, which
+// has no RETURNING, select and lock the affected rows first, so they must
+// run in a transaction (see RunInTx). On Postgres and DuckDB the statement
+// gets "returning *"; MySQL updates select After again by key.
+type RowChange struct {
+	Table  string
+	Type   QueryType
+	Before []map[string]interface{}
+	After  []map[string]interface{}
+}
+
+// ChangeHook receives the RowChange of each captured statement, e.g. to
+// write outbox events or publish them. Run the statement in a transaction
+// (see RunInTx) so an error from the hook can roll the change back.
+type ChangeHook func(ctx context.Context, change RowChange) error
+
+// CaptureChanges makes an update or delete report the rows it changes to
+// the Runner's OnChange hook, see RowChange. key names the column
+// identifying a row, "id" by default; MySQL updates use it to select the
+// updated rows, so it must not be one of the updated columns.
+func (b *QueryBuilder) CaptureChanges(key ...string) *QueryBuilder {
+	b.captureChanges = true
+	b.captureKey = "id"
+	if len(key) > 0 {
+		b.captureKey = key[0]
+	}
+	return b
+}
+
+// OnChange sets the hook the Runner calls, after the statement succeeds,
+// with the RowChange of every builder marked CaptureChanges. Exec returns
+// the hook's error.
+func OnChange(hook ChangeHook) RunnerOption {
+	return func(r *Runner) {
+		r.changeHook = hook
+	}
+}
+
+func (b *QueryBuilder) validateCapture() error {
+	if !b.captureChanges {
+		return nil
+	}
+	if b.queryType != UpdateQuery && b.queryType != DeleteQuery {
+		return fmt.Errorf("%w: CaptureChanges on %s statement", ErrUnsupportedFeature, queryTypeName(b.queryType))
+	}
+	if b.target() == CQL {
+		return fmt.Errorf("%w: CaptureChanges on %s", ErrUnsupportedFeature, b.target())
+	}
+	return nil
+}
+
+// returnsChanges reports whether the statement ends in "returning *"
+func (b *QueryBuilder) returnsChanges() bool {
+	return b.captureChanges && (b.target() == Postgres || b.target() == DuckDB)
+}
+
+func (b *QueryBuilder) writeReturningChanges(w *sqlWriter) {
+	if b.returnsChanges() {
+		w.write(" returning *")
+	}
+}
+
+// execCapture runs a CaptureChanges statement and passes its RowChange
+// to the hook
+func (r *Runner) execCapture(ctx context.Context, b *QueryBuilder) (sql.Result, error) {
+	change := RowChange{Table: b.table, Type: b.queryType}
+	db := r.route(b)
+
+	if b.queryType == UpdateQuery || !b.returnsChanges() {
+		if _, ok := db.(*sql.Tx); !ok {
+			return nil, fmt.Errorf("%w: %s on %s", ErrCaptureOutsideTx, queryTypeName(b.queryType), b.table)
+		}
+		before, err := r.selectAffected(ctx, db, b)
+		if err != nil {
+			return nil, err
+		}
+		change.Before = before
+	}
+
+	q, err := r.prepare(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if !b.returnsChanges() {
+		done := r.track(ctx, b, q)
+		result, err := db.ExecContext(ctx, q.SQL, q.Params...)
+		done()
+		if err != nil {
+			return nil, err
+		}
+		if b.queryType == UpdateQuery {
+			if change.After, err = r.selectUpdated(ctx, db, b, change.Before); err != nil {
+				return nil, err
+			}
+		}
+		return result, r.changeHook(ctx, change)
+	}
+
+	done := r.track(ctx, b, q)
+	rows, err := db.QueryContext(ctx, q.SQL, q.Params...)
+	done()
+	if err != nil {
+		return nil, err
+	}
+	changed, err := readRowMaps(rows)
+	if err != nil {
+		return nil, err
+	}
+	if b.queryType == DeleteQuery {
+		change.Before = changed
+	} else {
+		change.After = changed
+	}
+	return capturedResult(len(changed)), r.changeHook(ctx, change)
+}
+
+// selectAffected selects and locks the rows an update or delete is about
+// to change
+func (r *Runner) selectAffected(ctx context.Context, db DB, b *QueryBuilder) ([]map[string]interface{}, error) {
+	sel := captureSelect(b)
+	sel.ForUpdate()
+	return r.selectRowMaps(ctx, db, sel)
+}
+
+// selectUpdated selects the rows of before again by the capture key, after
+// the update has run
+func (r *Runner) selectUpdated(ctx context.Context, db DB, b *QueryBuilder, before []map[string]interface{}) ([]map[string]interface{}, error) {
+	if len(before) == 0 {
+		return nil, nil
+	}
+	keys := make([]interface{}, len(before))
+	for i, row := range before {
+		key, ok := row[b.captureKey]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no key column %s", ErrUnsupportedFeature, b.table, b.captureKey)
+		}
+		keys[i] = key
+	}
+	sel := captureSelect(b)
+	sel.whereClauses = nil
+	sel.order, sel.orderExprs = "", nil
+	sel.limit, sel.offset = 0, 0
+	sel.WhereIn(b.captureKey, keys...)
+	return r.selectRowMaps(ctx, db, sel)
+}
+
+// captureSelect turns a captured update or delete into a select of its rows
+func captureSelect(b *QueryBuilder) *QueryBuilder {
+	sel := b.Clone()
+	sel.queryType = SelectQuery
+	sel.columns = []string{"*"}
+	sel.selectExprs = nil
+	sel.captureChanges = false
+	return sel
+}
+
+func (r *Runner) selectRowMaps(ctx context.Context, db DB, sel *QueryBuilder) ([]map[string]interface{}, error) {
+	q, err := sel.TryBuild()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, q.SQL, q.Params...)
+	if err != nil {
+		return nil, err
+	}
+	return readRowMaps(rows)
+}
+
+// readRowMaps reads and closes rows, one map per row keyed by column
+func readRowMaps(rows *sql.Rows) ([]map[string]interface{}, error) {
+	defer rows.Close()
+	result, err := readResult(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	maps := make([]map[string]interface{}, len(result.Rows))
+	for i, row := range result.Rows {
+		maps[i] = make(map[string]interface{}, len(row))
+		for j, column := range result.Columns {
+			maps[i][column] = row[j]
+		}
+	}
+	return maps, nil
+}
+
+// capturedResult is the sql.Result of a statement run for its returned
+// rows
+type capturedResult int64
+
+func (r capturedResult) LastInsertId() (int64, error) {
+	return 0, errors.New("query: no insert id for a captured statement")
+}
+
+func (r capturedResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
@@ -0,0 +1,176 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestCaptureChangesSQL(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("orders").
+		Set("status", "shipped").
+		Where("id", "=", 1).
+		CaptureChanges().
+		Build()
+
+	expectedSQL := "update orders set status = $1 where id = $2 returning *"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().Dialect(MySQL).Table("orders").Delete().Where("id", "=", 1).CaptureChanges().Build()
+	expectedSQL = "delete from orders where id = ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	_, err := NewQueryBuilder().Table("orders").CaptureChanges().TryBuild()
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+	}
+}
+
+func TestCaptureChangesReturning(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select * from orders where id = $1 for update",
+		[]string{"id", "status"}, []driver.Value{int64(1), "paid"})
+	fake.On("update orders set status = $1 where id = $2 returning *",
+		[]string{"id", "status"}, []driver.Value{int64(1), "shipped"})
+	fake.On("delete from orders where id = $1 returning *",
+		[]string{"id", "status"}, []driver.Value{int64(2), "cancelled"})
+
+	var changes []RowChange
+	runner := NewRunner(db, OnChange(func(ctx context.Context, change RowChange) error {
+		changes = append(changes, change)
+		return nil
+	}))
+
+	err := runner.RunInTx(context.Background(), TxOptions{}, func(tx *Runner) error {
+		result, err := tx.Exec(context.Background(), NewQueryBuilder().
+			Table("orders").
+			Set("status", "shipped").
+			Where("id", "=", 1).
+			CaptureChanges())
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n != 1 {
+			t.Errorf("Expected 1 row affected, got %d", n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Table != "orders" || changes[0].Type != UpdateQuery {
+		t.Fatalf("Unexpected changes: %+v", changes)
+	}
+	if len(changes[0].Before) != 1 || changes[0].Before[0]["status"] != "paid" {
+		t.Errorf("Expected the old row as Before, got: %+v", changes[0])
+	}
+	if len(changes[0].After) != 1 || changes[0].After[0]["status"] != "shipped" {
+		t.Errorf("Expected the updated row as After, got: %+v", changes[0])
+	}
+
+	// Deletes return their rows, so they need no transaction
+	_, err = runner.Exec(context.Background(), NewQueryBuilder().Table("orders").Delete().Where("id", "=", 2).CaptureChanges())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 2 || len(changes[1].Before) != 1 || changes[1].Before[0]["status"] != "cancelled" || changes[1].After != nil {
+		t.Errorf("Expected the deleted row as Before, got: %+v", changes)
+	}
+}
+
+func TestCaptureChangesOutsideTx(t *testing.T) {
+	db, fake := newFakeDB(t)
+	runner := NewRunner(db, OnChange(func(ctx context.Context, change RowChange) error {
+		return nil
+	}))
+
+	_, err := runner.Exec(context.Background(), NewQueryBuilder().
+		Table("orders").
+		Set("status", "shipped").
+		Where("id", "=", 1).
+		CaptureChanges())
+	if !errors.Is(err, ErrCaptureOutsideTx) {
+		t.Errorf("Expected ErrCaptureOutsideTx, got: %v", err)
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("Expected no statements, got: %v", calls)
+	}
+}
+
+func TestCaptureChangesMySQLUpdate(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select * from orders where status = ? for update",
+		[]string{"order_id", "status"}, []driver.Value{int64(1), "paid"}, []driver.Value{int64(2), "paid"})
+	fake.OnExec("update orders set status = ? where status = ?", 2)
+	fake.On("select * from orders where order_id in (?, ?)",
+		[]string{"order_id", "status"}, []driver.Value{int64(1), "shipped"}, []driver.Value{int64(2), "shipped"})
+
+	var change RowChange
+	runner := NewRunner(db, OnChange(func(ctx context.Context, c RowChange) error {
+		change = c
+		return nil
+	}))
+
+	err := runner.RunInTx(context.Background(), TxOptions{}, func(tx *Runner) error {
+		_, err := tx.Exec(context.Background(), NewQueryBuilder().
+			Dialect(MySQL).
+			Table("orders").
+			Set("status", "shipped").
+			Where("status", "=", "paid").
+			CaptureChanges("order_id"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(change.Before) != 2 || change.Before[1]["status"] != "paid" {
+		t.Errorf("Expected the old rows as Before, got: %+v", change)
+	}
+	if len(change.After) != 2 || change.After[1]["status"] != "shipped" {
+		t.Errorf("Expected the updated rows as After, got: %+v", change)
+	}
+}
+
+func TestCaptureChangesPreSelect(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select * from orders where id = ? for update",
+		[]string{"id", "status"}, []driver.Value{int64(1), "cancelled"})
+	fake.OnExec("delete from orders where id = ?", 1)
+
+	hookErr := errors.New("publish failed")
+	var change RowChange
+	runner := NewRunner(db, OnChange(func(ctx context.Context, c RowChange) error {
+		change = c
+		return hookErr
+	}))
+
+	err := runner.RunInTx(context.Background(), TxOptions{}, func(tx *Runner) error {
+		_, err := tx.Exec(context.Background(), NewQueryBuilder().
+			Dialect(MySQL).
+			Table("orders").
+			Delete().
+			Where("id", "=", 1).
+			CaptureChanges())
+		return err
+	})
+	if !errors.Is(err, hookErr) {
+		t.Errorf("Expected the hook error, got: %v", err)
+	}
+	if len(change.Before) != 1 || change.Before[0]["status"] != "cancelled" || change.Type != DeleteQuery {
+		t.Errorf("Expected the deleted row as Before, got: %+v", change)
+	}
+	if txs := fake.Txs(); len(txs) != 1 || !txs[0].RolledBack {
+		t.Errorf("Expected the transaction rolled back, got: %+v", txs)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[1].SQL != "delete from orders where id = ?" {
+		t.Errorf("Expected the select then the delete, got: %v", calls)
+	}
+}
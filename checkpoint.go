@@ -0,0 +1,36 @@
+package query
+
+import "errors"
+
+// ErrInvalidCheckpoint is returned by TryBuild after RollbackTo was given
+// a checkpoint of another builder, or the zero Checkpoint
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+
+// Checkpoint is a saved state of one builder, see QueryBuilder.Checkpoint
+type Checkpoint struct {
+	owner *QueryBuilder
+	state *QueryBuilder
+}
+
+// Checkpoint saves the current state of b, so that layered changes, such
+// as the filters of a report builder, can be undone with RollbackTo:
+//
+//	base := qb.Checkpoint()
+//	qb.Where("region", "=", region).OrderBy("total desc")
+//	qb.RollbackTo(base) // back to the query before the filter
+//
+// Later changes to b never affect the checkpoint.
+func (b *QueryBuilder) Checkpoint() Checkpoint {
+	return Checkpoint{owner: b, state: b.Clone()}
+}
+
+// RollbackTo returns b to the state saved by cp, dropping every change made
+// since, errors included. A checkpoint can be rolled back to any number of
+// times.
+func (b *QueryBuilder) RollbackTo(cp Checkpoint) *QueryBuilder {
+	if cp.owner != b || cp.state == nil {
+		return b.fail(ErrInvalidCheckpoint)
+	}
+	*b = *cp.state.Clone()
+	return b
+}
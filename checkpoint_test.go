@@ -0,0 +1,51 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	qb := NewQueryBuilder().Table("orders").Where("status", "=", "paid")
+	base := qb.Checkpoint()
+
+	qb.Where("region", "=", "eu").OrderBy("total desc").Limit(10)
+	filtered := qb.Checkpoint()
+	qb.WhereGroup(func(q *QueryBuilder) {
+		q.Where("total", ">", 100).OrWhere("vip", "=", true)
+	})
+
+	query := qb.RollbackTo(filtered).Build()
+	expectedSQL := "select * from orders where status = $1 and region = $2 order by total desc limit 10"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	// rolling back twice to the same checkpoint starts from the same state
+	for range 2 {
+		query = qb.RollbackTo(base).Where("id", ">", 5).Build()
+		expectedSQL = "select * from orders where status = $1 and id > $2"
+		if query.SQL != expectedSQL {
+			t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+		}
+	}
+}
+
+func TestCheckpointInvalid(t *testing.T) {
+	other := NewQueryBuilder().Table("users").Checkpoint()
+	tests := []Checkpoint{other, {}}
+	for _, cp := range tests {
+		_, err := NewQueryBuilder().Table("orders").RollbackTo(cp).TryBuild()
+		if !errors.Is(err, ErrInvalidCheckpoint) {
+			t.Errorf("Expected ErrInvalidCheckpoint, got: %v", err)
+		}
+	}
+
+	// the error is itself undone by a valid rollback
+	qb := NewQueryBuilder().Table("orders")
+	base := qb.Checkpoint()
+	qb.RollbackTo(other).RollbackTo(base)
+	if _, err := qb.TryBuild(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package query
+
+import "slices"
+
+// Clone returns an independent copy of b. Changes to the copy never affect
+// b, so a shared base query can be cloned, and built, concurrently.
+func (b *QueryBuilder) Clone() *QueryBuilder {
+	c := *b
+	c.columns = slices.Clone(b.columns)
+	c.partitions = slices.Clone(b.partitions)
+	c.lockOf = slices.Clone(b.lockOf)
+	c.selectExprs = slices.Clone(b.selectExprs)
+	c.selectSubs = make([]selectSubquery, len(b.selectSubs))
+	for i, sel := range b.selectSubs {
+		c.selectSubs[i] = selectSubquery{sub: sel.sub.Clone(), alias: sel.alias}
+	}
+	c.orderExprs = slices.Clone(b.orderExprs)
+	c.columnRewrites = slices.Clone(b.columnRewrites)
+	c.outerRefs = slices.Clone(b.outerRefs)
+	c.insertColumns = slices.Clone(b.insertColumns)
+	c.insertValues = slices.Clone(b.insertValues)
+	c.insertRows = slices.Clone(b.insertRows)
+	c.upsertKey = slices.Clone(b.upsertKey)
+	c.updateColumns = slices.Clone(b.updateColumns)
+	c.updateValues = slices.Clone(b.updateValues)
+	c.buildHooks = slices.Clone(b.buildHooks)
+	c.comments = slices.Clone(b.comments)
+	if b.fromSub != nil {
+		c.fromSub = b.fromSub.Clone()
+	}
+	if b.insertSelect != nil {
+		c.insertSelect = b.insertSelect.Clone()
+	}
+
+	c.whereClauses = cloneWheres(b.whereClauses)
+	c.groupBy = slices.Clone(b.groupBy)
+	c.having = cloneWheres(b.having)
+	c.qualify = cloneWheres(b.qualify)
+	c.starExclude = slices.Clone(b.starExclude)
+	c.starReplace = slices.Clone(b.starReplace)
+
+	c.joinClauses = make([]*JoinClause, len(b.joinClauses))
+	for i, join := range b.joinClauses {
+		j := *join
+		c.joinClauses[i] = &j
+	}
+
+	return &c
+}
+
+func cloneWheres(clauses []*WhereClause) []*WhereClause {
+	if clauses == nil {
+		return nil
+	}
+	cloned := make([]*WhereClause, len(clauses))
+	for i, where := range clauses {
+		w := *where
+		w.Group = cloneWheres(where.Group)
+		if where.Subquery != nil {
+			w.Subquery = where.Subquery.Clone()
+		}
+		cloned[i] = &w
+	}
+	return cloned
+}
@@ -0,0 +1,50 @@
+package query
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCloneIsIndependent(t *testing.T) {
+	base := NewQueryBuilder().
+		Table("users").
+		Select("id", "name").
+		Where("active", "=", true)
+
+	admins := base.Clone().Where("role", "=", "admin").OrderBy("name")
+	recent := base.Clone().Where("created_at", ">", "2024-01-01").Limit(5)
+
+	expected := map[*QueryBuilder]string{
+		base:   "select id, name from users where active = $1",
+		admins: "select id, name from users where active = $1 and role = $2 order by name",
+		recent: "select id, name from users where active = $1 and created_at > $2 limit 5",
+	}
+	for qb, expectedSQL := range expected {
+		if sql := qb.Build().SQL; sql != expectedSQL {
+			t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+		}
+	}
+}
+
+func TestCloneConcurrently(t *testing.T) {
+	base := NewQueryBuilder().
+		Table("orders").
+		LeftJoin("customers", "customers.id = orders.customer_id").
+		Where("status", "=", "open")
+	base.Build()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			query := base.Clone().Where("region", "=", i).Build()
+			expectedSQL := "select * from orders LEFT JOIN customers on customers.id = orders.customer_id where status = $1 and region = $2"
+			if query.SQL != expectedSQL || query.Params[1] != i {
+				t.Errorf("Unexpected query: %s %v", query.SQL, fmt.Sprint(query.Params))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,181 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ClosureBuilder builds a recursive CTE finding the ancestors or the
+// descendants of one row of an adjacency-list table. The walk collects
+// ids with UNION, which drops an id already reached, so cycles and rows
+// reachable along several paths end the walk instead of repeating it.
+// Table and column names are quoted for the dialect.
+type ClosureBuilder struct {
+	table      string
+	parentCol  string
+	idCol      string
+	maxDepth   int
+	limited    bool
+	ancestors  bool
+	id         interface{}
+	set        bool
+	paramStyle ParameterStyle
+	dialect    Dialect
+}
+
+// Closure starts an ancestor or descendant query over table, linked by
+// parent_id and id unless ConnectBy says otherwise
+func Closure(table string) *ClosureBuilder {
+	style, dialect := defaults()
+	return &ClosureBuilder{
+		table:      table,
+		parentCol:  "parent_id",
+		idCol:      "id",
+		paramStyle: style,
+		dialect:    dialect,
+	}
+}
+
+func (c *ClosureBuilder) ParameterPlaceholder(style ParameterStyle) *ClosureBuilder {
+	c.paramStyle = style
+	return c
+}
+
+// Dialect targets a specific database and switches to its usual
+// placeholder style, as QueryBuilder.Dialect does
+func (c *ClosureBuilder) Dialect(dialect Dialect) *ClosureBuilder {
+	c.dialect = dialect
+	switch dialect {
+	case Postgres:
+		c.paramStyle = DollarNumber
+	case MySQL, DuckDB, CQL:
+		c.paramStyle = QuestionMark
+	}
+	return c
+}
+
+// ConnectBy links each row's parentColumn to its parent's idColumn
+func (c *ClosureBuilder) ConnectBy(parentColumn, idColumn string) *ClosureBuilder {
+	c.parentCol = parentColumn
+	c.idCol = idColumn
+	return c
+}
+
+// MaxDepth limits how many levels the walk follows; by default it follows
+// every level. The limit is tracked in a depth column, so UNION no longer
+// drops ids reached again at another depth: a cycle is walked around until
+// the limit.
+func (c *ClosureBuilder) MaxDepth(depth int) *ClosureBuilder {
+	c.maxDepth, c.limited = depth, true
+	return c
+}
+
+// Ancestors selects every row above id: its parent, the parent's parent,
+// and so on, excluding the row itself unless it is part of a cycle
+func (c *ClosureBuilder) Ancestors(id interface{}) *ClosureBuilder {
+	c.ancestors, c.id, c.set = true, id, true
+	return c
+}
+
+// Descendants selects every row below id, excluding the row itself unless
+// it is part of a cycle
+func (c *ClosureBuilder) Descendants(id interface{}) *ClosureBuilder {
+	c.ancestors, c.id, c.set = false, id, true
+	return c
+}
+
+func (c *ClosureBuilder) Build() Query {
+	q, err := c.TryBuild()
+	if err != nil {
+		return Query{}
+	}
+	return q
+}
+
+// TryBuild generates the SQL and parameters. It fails without Ancestors or
+// Descendants, with ErrUnsafeIdentifier for a name that cannot be quoted,
+// and with ErrUnsupportedFeature on databases without recursive CTEs.
+func (c *ClosureBuilder) TryBuild() (Query, error) {
+	if !c.set {
+		return Query{}, errors.New("query: closure needs Ancestors or Descendants")
+	}
+	if c.limited && c.maxDepth < 1 {
+		return Query{}, fmt.Errorf("query: closure depth must be positive, got %d", c.maxDepth)
+	}
+	dialect := resolveDialect(c.dialect, c.paramStyle)
+	if !dialect.Supports(RecursiveCTE) {
+		return Query{}, &UnsupportedFeatureError{Feature: RecursiveCTE, Dialect: dialect}
+	}
+
+	t, err := dialect.QuoteIdentifier(c.table)
+	if err != nil {
+		return Query{}, err
+	}
+	parentCol, err := dialect.QuoteIdentifier(c.parentCol)
+	if err != nil {
+		return Query{}, err
+	}
+	idCol, err := dialect.QuoteIdentifier(c.idCol)
+	if err != nil {
+		return Query{}, err
+	}
+
+	// from is the column walked from and to the column reached
+	from, to := parentCol, idCol
+	if c.ancestors {
+		from, to = idCol, parentCol
+	}
+
+	w := &sqlWriter{style: c.paramStyle, dialect: dialect}
+
+	// closure holds the ids reached, and with MaxDepth the depth they were
+	// reached at; the row itself is not in it
+	w.write("with recursive closure (id")
+	if c.limited {
+		w.write(", depth")
+	}
+	w.write(") as (select ")
+	w.write(to)
+	if c.limited {
+		w.write(", 1")
+	}
+	w.write(" from ")
+	w.write(t)
+	w.write(" where ")
+	w.write(from)
+	w.write(" = ")
+	w.bind(c.id)
+	if c.ancestors {
+		w.write(" and " + to + " is not null")
+	}
+
+	w.write(" union select ")
+	w.write(t + "." + to)
+	if c.limited {
+		w.write(", closure.depth + 1")
+	}
+	w.write(" from ")
+	w.write(t)
+	w.write(" join closure on ")
+	w.write(t + "." + from)
+	w.write(" = closure.id")
+	switch {
+	case c.limited && c.ancestors:
+		w.write(" where closure.depth < ")
+		w.writeInt(c.maxDepth)
+		w.write(" and " + t + "." + to + " is not null")
+	case c.limited:
+		w.write(" where closure.depth < ")
+		w.writeInt(c.maxDepth)
+	case c.ancestors:
+		w.write(" where " + t + "." + to + " is not null")
+	}
+
+	w.write(") select * from ")
+	w.write(t)
+	w.write(" where ")
+	w.write(idCol)
+	w.write(" in (select id from closure)")
+
+	return paramSettings{style: c.paramStyle}.finishParams(w.finish())
+}
@@ -0,0 +1,69 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClosureDescendants(t *testing.T) {
+	query := Closure("employee_hierarchy").Descendants(7).Build()
+
+	expectedSQL := `with recursive closure (id) as (select "id" from "employee_hierarchy" where "parent_id" = $1` +
+		` union select "employee_hierarchy"."id" from "employee_hierarchy"` +
+		` join closure on "employee_hierarchy"."parent_id" = closure.id)` +
+		` select * from "employee_hierarchy" where "id" in (select id from closure)`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 1 || query.Params[0] != 7 {
+		t.Errorf("Expected params: [7], got: %v", query.Params)
+	}
+}
+
+func TestClosureAncestors(t *testing.T) {
+	query := Closure("employees").
+		Dialect(MySQL).
+		ConnectBy("manager_id", "emp_id").
+		Ancestors(42).
+		Build()
+
+	expectedSQL := "with recursive closure (id) as (select `manager_id` from `employees` where `emp_id` = ? and `manager_id` is not null" +
+		" union select `employees`.`manager_id` from `employees`" +
+		" join closure on `employees`.`emp_id` = closure.id where `employees`.`manager_id` is not null)" +
+		" select * from `employees` where `emp_id` in (select id from closure)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestClosureMaxDepth(t *testing.T) {
+	query := Closure("employees").
+		ParameterPlaceholder(QuestionMark).
+		ConnectBy("manager_id", "emp_id").
+		MaxDepth(10).
+		Descendants(42).
+		Build()
+
+	expectedSQL := "with recursive closure (id, depth) as (select `emp_id`, 1 from `employees` where `manager_id` = ?" +
+		" union select `employees`.`emp_id`, closure.depth + 1 from `employees`" +
+		" join closure on `employees`.`manager_id` = closure.id where closure.depth < 10)" +
+		" select * from `employees` where `emp_id` in (select id from closure)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestClosureErrors(t *testing.T) {
+	if _, err := Closure("employees").TryBuild(); err == nil {
+		t.Error("Expected an error without a direction")
+	}
+	if _, err := Closure("employees").MaxDepth(0).Descendants(1).TryBuild(); err == nil {
+		t.Error("Expected an error for a zero depth")
+	}
+	if _, err := Closure("employees").ConnectBy("boss_id) or (1=1", "id").Descendants(1).TryBuild(); !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+	if _, err := Closure("employees").Dialect(CQL).Descendants(1).TryBuild(); !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package query
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// ColumnType is a database column type, written as a Postgres cast
+type ColumnType string
+
+const (
+	Timestamp ColumnType = "timestamptz"
+	JSON      ColumnType = "jsonb"
+	UUID      ColumnType = "uuid"
+	Citext    ColumnType = "citext"
+)
+
+var (
+	columnTypesMu sync.RWMutex
+	columnTypes   = map[string]map[string]ColumnType{}
+)
+
+// RegisterColumnTypes records the types of some columns of table. Values
+// compared with, inserted into or assigned to a registered column are
+// then cast on Postgres and DuckDB ("id = $1::uuid"), JSON values that
+// are not already strings or bytes are encoded with encoding/json, and
+// ExportJSON embeds JSON columns as JSON instead of strings. Other types
+// can be registered as ColumnType("inet"). Registering a table again
+// replaces its types, and registering none removes them.
+func RegisterColumnTypes(table string, types map[string]ColumnType) {
+	if len(types) == 0 {
+		columnTypesMu.Lock()
+		defer columnTypesMu.Unlock()
+		delete(columnTypes, table)
+		return
+	}
+	registered := make(map[string]ColumnType, len(types))
+	for column, columnType := range types {
+		registered[column] = columnType
+	}
+	columnTypesMu.Lock()
+	defer columnTypesMu.Unlock()
+	columnTypes[table] = registered
+}
+
+// LookupColumnType returns the registered type of a column
+func LookupColumnType(table, column string) (ColumnType, bool) {
+	columnTypesMu.RLock()
+	defer columnTypesMu.RUnlock()
+	columnType, ok := columnTypes[table][column]
+	return columnType, ok
+}
+
+// columnTypes maps every way the query can name a registered column - bare
+// for its own table, qualified by table or alias - to the column's type
+func (b *QueryBuilder) columnTypes() map[string]ColumnType {
+	columnTypesMu.RLock()
+	defer columnTypesMu.RUnlock()
+	if len(columnTypes) == 0 {
+		return nil
+	}
+
+	var types map[string]ColumnType
+	if b.fromExpr == nil && b.fromSub == nil {
+		types = addColumnTypes(types, b.table, b.tableAlias, true)
+	}
+	for _, join := range b.joinClauses {
+		types = addColumnTypes(types, join.Table, join.Alias, false)
+	}
+	return types
+}
+
+// addColumnTypes adds the registered columns of table to types, creating
+// the map only once a column is found
+func addColumnTypes(types map[string]ColumnType, table, alias string, bare bool) map[string]ColumnType {
+	if strings.Contains(table, " ") {
+		if fields := strings.Fields(table); len(fields) == 2 {
+			table, alias = fields[0], fields[1]
+		}
+	}
+	for column, columnType := range columnTypes[table] {
+		if types == nil {
+			types = map[string]ColumnType{}
+		}
+		types[table+"."+column] = columnType
+		if alias != "" {
+			types[alias+"."+column] = columnType
+		}
+		if bare {
+			types[column] = columnType
+		}
+	}
+	return types
+}
+
+// castsParams reports whether the dialect takes "::type" casts
+func (b *QueryBuilder) castsParams() bool {
+	switch b.target() {
+	case Postgres, DuckDB:
+		return true
+	default:
+		return false
+	}
+}
+
+// typed writes value for column, encoding JSON and adding a cast when the
+// column's type is registered
+func (w *sqlWriter) typed(column string, value interface{}) {
+	columnType, ok := w.types[column]
+	if _, isExpr := value.(Expr); !ok || isExpr {
+		w.value(value)
+		return
+	}
+	if columnType == JSON {
+		value = jsonParam(value)
+	}
+	w.bind(value)
+	if w.casts && value != nil {
+		w.raw("::" + string(columnType))
+	}
+}
+
+// jsonParam encodes value as JSON unless the driver can already bind it
+func jsonParam(value interface{}) interface{} {
+	switch value.(type) {
+	case nil, string, []byte, json.RawMessage, driver.Valuer:
+		return value
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		// Left as is, so TryBuild reports it as an unsupported param
+		return value
+	}
+	return string(data)
+}
@@ -0,0 +1,108 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func registerDocTypes(t *testing.T) {
+	t.Helper()
+	RegisterColumnTypes("docs", map[string]ColumnType{
+		"id":         UUID,
+		"meta":       JSON,
+		"created_at": Timestamp,
+		"email":      Citext,
+	})
+	t.Cleanup(func() {
+		columnTypesMu.Lock()
+		delete(columnTypes, "docs")
+		columnTypesMu.Unlock()
+	})
+}
+
+func TestColumnTypeCasts(t *testing.T) {
+	registerDocTypes(t)
+
+	query := NewQueryBuilder().
+		Table("docs").
+		As("d").
+		Where("d.id", "=", "0b0e6a1c-35f4-4c5e-9a0c-1f1e2d3c4b5a").
+		Where("email", "=", "Ann@Example.com").
+		WhereIn("id", "a", "b").
+		Where("title", "=", "x").
+		Build()
+
+	expectedSQL := "select * from docs as d where d.id = $1::uuid and email = $2::citext and id in ($3::uuid, $4::uuid) and title = $5"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestColumnTypeJSONParams(t *testing.T) {
+	registerDocTypes(t)
+
+	query := NewQueryBuilder().
+		Table("docs").
+		Set("meta", map[string]interface{}{"tags": []string{"a"}}).
+		Where("id", "=", "x").
+		Build()
+
+	expectedSQL := "update docs set meta = $1::jsonb where id = $2::uuid"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[0] != `{"tags":["a"]}` {
+		t.Errorf("Expected JSON-encoded param, got: %v", query.Params[0])
+	}
+
+	query = NewQueryBuilder().
+		Dialect(MySQL).
+		Table("docs").
+		InsertColumns("id", "meta").
+		Values("x", []int{1, 2}).
+		Build()
+
+	expectedSQL = "insert into docs (id, meta) values (?, ?)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL without casts on MySQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[1] != "[1,2]" {
+		t.Errorf("Expected JSON-encoded param, got: %v", query.Params[1])
+	}
+}
+
+func TestColumnTypeExportJSON(t *testing.T) {
+	registerDocTypes(t)
+	db, fake := newFakeDB(t)
+	fake.On("select id, meta from docs", []string{"id", "meta"},
+		[]driver.Value{[]byte("a"), []byte(`{"k":1}`)})
+
+	var out bytes.Buffer
+	qb := NewQueryBuilder().Table("docs").Select("id", "meta")
+	err := NewRunner(db).ExportJSON(context.Background(), qb, &out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `[{"id":"a","meta":{"k":1}}]`
+	if out.String() != expected {
+		t.Errorf("Expected: %s, got: %s", expected, out.String())
+	}
+}
+
+func TestColumnTypeCastsInUnion(t *testing.T) {
+	registerDocTypes(t)
+
+	query, err := Union(
+		NewQueryBuilder().Table("docs").Select("id").Where("id", "=", "0b0e6a1c-35f4-4c5e-9a0c-1f1e2d3c4b5a"),
+		NewQueryBuilder().Table("archive").Select("id").Where("id", "=", 7),
+	).TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := "select id from docs where id = $1::uuid union select id from archive where id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
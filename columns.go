@@ -0,0 +1,52 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrUnknownTable is returned by TryBuild when a feature needs the columns
+// of a table that was never registered
+var ErrUnknownTable = errors.New("unknown table")
+
+var (
+	columnsMu    sync.RWMutex
+	tableColumns = map[string][]string{}
+)
+
+// RegisterColumns records the columns of table, in order, typically from
+// generated code or a schema cache. Registering a table again replaces its
+// columns.
+func RegisterColumns(table string, columns ...string) {
+	columnsMu.Lock()
+	defer columnsMu.Unlock()
+	tableColumns[table] = slices.Clone(columns)
+}
+
+// LookupColumns returns the registered columns of table
+func LookupColumns(table string) ([]string, bool) {
+	columnsMu.RLock()
+	defer columnsMu.RUnlock()
+	columns, ok := tableColumns[table]
+	return slices.Clone(columns), ok
+}
+
+// SelectAllExcept selects every registered column of the builder's table
+// except the given ones, as an explicit list rather than *, so new
+// sensitive columns are never selected by accident. Call it after Table;
+// an unregistered table is reported by TryBuild as ErrUnknownTable.
+func (b *QueryBuilder) SelectAllExcept(excluded ...string) *QueryBuilder {
+	columns, ok := LookupColumns(b.table)
+	if !ok {
+		return b.fail(fmt.Errorf("%w: %s has no registered columns", ErrUnknownTable, b.table))
+	}
+	columns = slices.DeleteFunc(columns, func(column string) bool {
+		return slices.Contains(excluded, column)
+	})
+	if len(columns) == 0 {
+		return b.fail(fmt.Errorf("%w: from %s", ErrNoColumns, b.table))
+	}
+	return b.Select(columns...)
+}
@@ -0,0 +1,33 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectAllExcept(t *testing.T) {
+	RegisterColumns("except_users", "id", "email", "password", "secret", "created_at")
+
+	query := NewQueryBuilder().
+		Table("except_users").
+		SelectAllExcept("password", "secret").
+		Where("id", "=", 1).
+		Build()
+
+	expectedSQL := "select id, email, created_at from except_users where id = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	columns, _ := LookupColumns("except_users")
+	if len(columns) != 5 {
+		t.Errorf("Expected registry to be unchanged, got: %v", columns)
+	}
+}
+
+func TestSelectAllExceptUnknownTable(t *testing.T) {
+	_, err := NewQueryBuilder().Table("except_missing").SelectAllExcept("password").TryBuild()
+	if !errors.Is(err, ErrUnknownTable) {
+		t.Errorf("Expected ErrUnknownTable, got: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTooComplex is returned by the Runner when a query's Complexity is
+// above the budget set with MaxComplexity
+var ErrTooComplex = errors.New("query too complex")
+
+// Complexity weights, see Complexity
+const (
+	statementCost   = 1
+	conditionCost   = 1
+	joinCost        = 3
+	subqueryCost    = 5
+	unionBranchCost = 5
+	unboundedCost   = 10
+)
+
+// Complexity estimates how expensive the query is to run, for budgets on
+// queries composed from user input: 1 for the statement and each where or
+// having condition, 3 per join, 5 per subquery plus its own complexity,
+// and 10 for a select without a limit. The score is relative; it never
+// looks at table sizes or indexes.
+func (b *QueryBuilder) Complexity() int {
+	score := statementCost + joinCost*len(b.joinClauses)
+	if b.queryType == SelectQuery && b.limit <= 0 && !b.exists {
+		score += unboundedCost
+	}
+	if b.fromSub != nil {
+		score += subqueryCost + b.fromSub.Complexity()
+	}
+	for _, sel := range b.selectSubs {
+		score += subqueryCost + sel.sub.Complexity()
+	}
+	if b.insertSelect != nil {
+		score += subqueryCost + b.insertSelect.Complexity()
+	}
+	return score + conditionComplexity(b.whereClauses) + conditionComplexity(b.having)
+}
+
+func conditionComplexity(clauses []*WhereClause) int {
+	score := 0
+	for _, where := range clauses {
+		if where.Group != nil {
+			score += conditionComplexity(where.Group)
+			continue
+		}
+		score += conditionCost
+		if where.Subquery != nil {
+			score += subqueryCost + where.Subquery.Complexity()
+		}
+	}
+	return score
+}
+
+// Complexity is the sum of the branches' Complexity plus 5 for each
+// branch after the first
+func (c *Compound) Complexity() int {
+	score := 0
+	for i, branch := range c.branches {
+		if i > 0 {
+			score += unionBranchCost
+		}
+		score += branch.Complexity()
+	}
+	return score
+}
+
+// MaxComplexity makes the Runner reject builders whose Complexity is above
+// budget with ErrTooComplex, before they reach the database. It guards
+// endpoints that compose joins and filters from user input. Selects are
+// scored after MaxRows adds its limit; builders without a Complexity
+// method are not checked.
+func MaxComplexity(budget int) RunnerOption {
+	return func(r *Runner) {
+		r.maxComplexity = budget
+	}
+}
+
+func (r *Runner) checkComplexity(qb Builder) error {
+	scored, ok := qb.(interface{ Complexity() int })
+	if r.maxComplexity <= 0 || !ok {
+		return nil
+	}
+	if score := scored.Complexity(); score > r.maxComplexity {
+		return fmt.Errorf("%w: complexity %d exceeds budget %d", ErrTooComplex, score, r.maxComplexity)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestComplexity(t *testing.T) {
+	simple := NewQueryBuilder().Table("users").Where("id", "=", 1).Limit(1)
+	if got := simple.Complexity(); got != 2 {
+		t.Errorf("Expected complexity 2, got: %d", got)
+	}
+
+	orders := NewQueryBuilder().Table("orders").WhereRaw("orders.user_id = users.id").Where("total", ">", 100)
+	complex := NewQueryBuilder().Table("users").
+		Join("teams", "teams.id = users.team_id").
+		LeftJoin("roles", "roles.id = users.role_id").
+		WhereGroup(func(q *QueryBuilder) {
+			q.Where("name", "like", "a%").OrWhere("email", "like", "a%")
+		}).
+		WhereExists(orders)
+	// statement 1, unbounded 10, joins 6, grouped conditions 2, exists 1 + 5 + (1 + 10 + 2)
+	if got := complex.Complexity(); got != 38 {
+		t.Errorf("Expected complexity 38, got: %d", got)
+	}
+
+	union := Union(simple, NewQueryBuilder().Table("admins").Limit(5))
+	if got := union.Complexity(); got != 2+5+1 {
+		t.Errorf("Expected complexity 8, got: %d", got)
+	}
+}
+
+func TestMaxComplexity(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select * from users limit 50", []string{"id"})
+
+	runner := NewRunner(db, MaxComplexity(3))
+	qb := NewQueryBuilder().Table("users").Join("teams", "teams.id = users.team_id").Limit(50)
+	if _, err := runner.Query(context.Background(), qb); !errors.Is(err, ErrTooComplex) {
+		t.Errorf("Expected ErrTooComplex, got: %v", err)
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("Expected no query to reach the database, got: %v", calls)
+	}
+
+	rows, err := runner.Query(context.Background(), NewQueryBuilder().Table("users").Limit(50))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows.Close()
+
+	capped := NewRunner(db, MaxRows(50), MaxComplexity(3))
+	rows, err = capped.Query(context.Background(), NewQueryBuilder().Table("users"))
+	if err != nil {
+		t.Fatalf("Expected MaxRows to bound the select before scoring, got: %v", err)
+	}
+	rows.Close()
+}
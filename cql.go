@@ -0,0 +1,98 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AllowFiltering appends ALLOW FILTERING to a CQL select, letting it filter
+// on columns outside the primary key at the cost of a scan. CQL only.
+func (b *QueryBuilder) AllowFiltering() *QueryBuilder {
+	b.allowFiltering = true
+	return b
+}
+
+// TTL expires the written values after seconds: "using ttl 86400" on a CQL
+// insert or update. CQL only.
+func (b *QueryBuilder) TTL(seconds int) *QueryBuilder {
+	b.ttl = seconds
+	return b
+}
+
+// WriteTimestamp sets the write time, in microseconds since the epoch, of a
+// CQL insert, update or delete: "using timestamp 1700000000000000". CQL only.
+func (b *QueryBuilder) WriteTimestamp(micros int64) *QueryBuilder {
+	b.writeTime = micros
+	return b
+}
+
+func (b *QueryBuilder) writeUsing(w *sqlWriter) {
+	if b.ttl == 0 && b.writeTime == 0 {
+		return
+	}
+	w.write(" using ")
+	if b.ttl != 0 {
+		w.write("ttl ")
+		w.writeInt(b.ttl)
+	}
+	if b.writeTime != 0 {
+		if b.ttl != 0 {
+			w.write(" and ")
+		}
+		w.write("timestamp ")
+		w.write(strconv.FormatInt(b.writeTime, 10))
+	}
+}
+
+// validateCQL keeps CQL builders to the subset Cassandra understands.
+// Whether the where clause restricts the partition key is left to the
+// server, which rejects such queries unless AllowFiltering is set.
+func (b *QueryBuilder) validateCQL() error {
+	if b.target() != CQL {
+		switch {
+		case b.allowFiltering:
+			return fmt.Errorf("%w: allow filtering on %s", ErrUnsupportedFeature, b.target())
+		case b.ttl != 0 || b.writeTime != 0:
+			return fmt.Errorf("%w: using ttl/timestamp on %s", ErrUnsupportedFeature, b.target())
+		}
+		return nil
+	}
+
+	unsupported := func(feature string) error {
+		return fmt.Errorf("%w: %s in cql", ErrUnsupportedFeature, feature)
+	}
+	switch {
+	case len(b.joinClauses) > 0:
+		return b.require(Joins)
+	case b.offset > 0:
+		return b.require(Offset)
+	case len(b.having) > 0:
+		return b.require(Having)
+	case b.exists:
+		return unsupported("exists")
+	case b.randomOrder || b.sampling():
+		return unsupported("random ordering and sampling")
+	case b.queryType != SelectQuery && (b.order != "" || len(b.orderExprs) > 0 || b.limit > 0):
+		return unsupported("order by and limit on writes")
+	case b.queryType == DeleteQuery && b.ttl != 0:
+		return unsupported("ttl on delete")
+	case b.queryType == SelectQuery && (b.ttl != 0 || b.writeTime != 0):
+		return unsupported("using ttl/timestamp on select")
+	case b.queryType != SelectQuery && b.allowFiltering:
+		return unsupported("allow filtering on writes")
+	}
+
+	var err error
+	for i, where := range b.whereClauses {
+		switch {
+		case where.Group != nil:
+			err = unsupported("grouped conditions")
+		case i > 0 && where.JoinType != "and":
+			err = unsupported("or conditions")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCQLSelect(t *testing.T) {
+	query := NewQueryBuilder().
+		Dialect(CQL).
+		Table("events").
+		Select("sensor_id", "ts", "value").
+		Where("sensor_id", "=", "s-1").
+		Where("day", "=", "2024-05-01").
+		Where("value", ">", 40).
+		OrderBy("ts desc").
+		Limit(100).
+		AllowFiltering().
+		Build()
+
+	expectedSQL := "select sensor_id, ts, value from events where sensor_id = ? and day = ? and value > ? order by ts desc limit 100 allow filtering"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestCQLWrites(t *testing.T) {
+	tests := []struct {
+		qb          *QueryBuilder
+		expectedSQL string
+	}{
+		{
+			NewQueryBuilder().Dialect(CQL).Table("sessions").
+				Insert(map[string]interface{}{"id": "abc"}).TTL(86400).WriteTimestamp(1700000000000000),
+			"insert into sessions (id) values (?) using ttl 86400 and timestamp 1700000000000000",
+		},
+		{
+			NewQueryBuilder().Dialect(CQL).Table("sessions").
+				Set("user_id", 7).TTL(3600).Where("id", "=", "abc"),
+			"update sessions using ttl 3600 set user_id = ? where id = ?",
+		},
+		{
+			NewQueryBuilder().Dialect(CQL).Table("sessions").
+				Delete().WriteTimestamp(1700000000000000).Where("id", "=", "abc"),
+			"delete from sessions using timestamp 1700000000000000 where id = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		query, err := tt.qb.TryBuild()
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if query.SQL != tt.expectedSQL {
+			t.Errorf("Expected SQL: %s, got: %s", tt.expectedSQL, query.SQL)
+		}
+	}
+}
+
+func TestCQLRejectsUnsupported(t *testing.T) {
+	builders := map[string]*QueryBuilder{
+		"join":   NewQueryBuilder().Dialect(CQL).Table("a").Join("b", "a.id = b.id"),
+		"or":     NewQueryBuilder().Dialect(CQL).Table("a").Where("x", "=", 1).OrWhere("y", "=", 2),
+		"group":  NewQueryBuilder().Dialect(CQL).Table("a").WhereGroup(func(g *QueryBuilder) { g.Where("x", "=", 1) }),
+		"offset": NewQueryBuilder().Dialect(CQL).Table("a").Offset(10),
+		"ttl":    NewQueryBuilder().Dialect(CQL).Table("a").Delete().TTL(10),
+		"sql":    NewQueryBuilder().Table("a").AllowFiltering(),
+	}
+	for name, qb := range builders {
+		if _, err := qb.TryBuild(); !errors.Is(err, ErrUnsupportedFeature) {
+			t.Errorf("%s: expected ErrUnsupportedFeature, got: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PropagateDeadline turns the time left on the context into a server-side
+// timeout, so the database stops work the client has already given up on.
+// MySQL selects get a "/*+ MAX_EXECUTION_TIME(ms) */" hint, except those
+// starting with a WITH clause. On Postgres, "set local statement_timeout"
+// is issued before each statement in a *sql.Tx. Outside a transaction,
+// Query, QueryRow and Exec run the statement on a connection of its own
+// with statement_timeout set; Exec hands the connection back when done,
+// Query and QueryRow once the context is done, so cancel deadline contexts
+// after reading the rows. Only *QueryBuilder statements are hinted.
+func PropagateDeadline() RunnerOption {
+	return func(r *Runner) {
+		r.deadlineHints = true
+	}
+}
+
+// deadlineMillis returns the milliseconds left before the context's
+// deadline, if it has one
+func deadlineMillis(ctx context.Context) (string, bool, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return "", false, nil
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return "", false, context.DeadlineExceeded
+	}
+	return strconv.FormatInt(max(remaining.Milliseconds(), 1), 10), true, nil
+}
+
+func (r *Runner) applyDeadline(ctx context.Context, db DB, b *QueryBuilder, q *Query) error {
+	ms, ok, err := deadlineMillis(ctx)
+	if !ok {
+		return err
+	}
+
+	switch b.target() {
+	case MySQL:
+		n := len("select ")
+		if b.queryType == SelectQuery && len(q.SQL) > n && strings.EqualFold(q.SQL[:n], "select ") {
+			q.SQL = q.SQL[:n] + "/*+ MAX_EXECUTION_TIME(" + ms + ") */ " + q.SQL[n:]
+		}
+	case Postgres:
+		if tx, ok := db.(*sql.Tx); ok {
+			if _, err := tx.ExecContext(ctx, "set local statement_timeout = "+ms); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deadlineDB returns the DB qb runs on. With PropagateDeadline, a Postgres
+// statement outside a transaction gets a connection of its own with
+// statement_timeout set; release resets it and hands the connection back,
+// and is nil when there is nothing to release.
+func (r *Runner) deadlineDB(ctx context.Context, qb Builder) (DB, func(), error) {
+	db := r.route(qb)
+	b, ok := qb.(*QueryBuilder)
+	if !ok || !r.deadlineHints || b.target() != Postgres {
+		return db, nil, nil
+	}
+	ms, ok, err := deadlineMillis(ctx)
+	if !ok {
+		return db, nil, err
+	}
+
+	var conn *sql.Conn
+	switch pool := db.(type) {
+	case *sql.DB:
+		if conn, err = pool.Conn(ctx); err != nil {
+			return nil, nil, err
+		}
+	case *sql.Conn:
+		conn = pool
+	default:
+		return db, nil, nil
+	}
+	pooled := DB(conn) != db
+	release := func() {
+		_, err := conn.ExecContext(context.WithoutCancel(ctx), "reset statement_timeout")
+		if !pooled {
+			return
+		}
+		if err != nil {
+			// never hand a connection with the timeout back to the pool
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+	if _, err := conn.ExecContext(ctx, "set statement_timeout = "+ms); err != nil {
+		release()
+		return nil, nil, err
+	}
+	return conn, release, nil
+}
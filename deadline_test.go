@@ -0,0 +1,116 @@
+package query
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestPropagateDeadlineMySQL(t *testing.T) {
+	db, fake := newFakeDB(t)
+	runner := NewRunner(db, PropagateDeadline())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	qb := NewQueryBuilder().Dialect(MySQL).Table("reports").Where("id", "=", 1)
+	if _, err := runner.Exec(ctx, qb); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	hinted := regexp.MustCompile(`^select /\*\+ MAX_EXECUTION_TIME\((\d+)\) \*/ \* from reports where id = \?$`)
+	calls := fake.Calls()
+	if len(calls) != 1 || !hinted.MatchString(calls[0].SQL) {
+		t.Fatalf("Expected hinted SQL, got: %+v", calls)
+	}
+}
+
+func TestPropagateDeadlinePostgresTx(t *testing.T) {
+	db, fake := newFakeDB(t)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer tx.Rollback()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := NewRunner(tx, PropagateDeadline()).Exec(ctx, NewQueryBuilder().Table("jobs").Delete().Where("id", "=", 1)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || !regexp.MustCompile(`^set local statement_timeout = \d+$`).MatchString(calls[0].SQL) {
+		t.Fatalf("Expected statement_timeout before the delete, got: %+v", calls)
+	}
+	if calls[1].SQL != "delete from jobs where id = $1" {
+		t.Errorf("Unexpected statement: %s", calls[1].SQL)
+	}
+}
+
+func TestPropagateDeadlineWithoutDeadline(t *testing.T) {
+	db, fake := newFakeDB(t)
+	qb := NewQueryBuilder().Dialect(MySQL).Table("reports")
+	if _, err := NewRunner(db, PropagateDeadline()).Exec(context.Background(), qb); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls := fake.Calls(); calls[0].SQL != "select * from reports" {
+		t.Errorf("Expected no hint, got: %s", calls[0].SQL)
+	}
+}
+
+func TestPropagateDeadlineKeywordCase(t *testing.T) {
+	db, fake := newFakeDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	qb := NewQueryBuilder().Dialect(MySQL).KeywordCase(Upper).Table("reports").Where("id", "=", 1)
+	if _, err := NewRunner(db, PropagateDeadline()).Exec(ctx, qb); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	hinted := regexp.MustCompile(`^SELECT /\*\+ MAX_EXECUTION_TIME\(\d+\) \*/ \* FROM reports WHERE id = \?$`)
+	if calls := fake.Calls(); len(calls) != 1 || !hinted.MatchString(calls[0].SQL) {
+		t.Fatalf("Expected hinted SQL, got: %+v", calls)
+	}
+}
+
+func TestPropagateDeadlinePostgres(t *testing.T) {
+	db, fake := newFakeDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := NewRunner(db, PropagateDeadline()).Exec(ctx, NewQueryBuilder().Table("jobs").Delete().Where("id", "=", 1)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 3 || !regexp.MustCompile(`^set statement_timeout = \d+$`).MatchString(calls[0].SQL) {
+		t.Fatalf("Expected statement_timeout before the delete, got: %+v", calls)
+	}
+	if calls[1].SQL != "delete from jobs where id = $1" || calls[2].SQL != "reset statement_timeout" {
+		t.Errorf("Expected the delete and a reset, got: %+v", calls[1:])
+	}
+}
+
+func TestPropagateDeadlineChunks(t *testing.T) {
+	db, fake := newFakeDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	qb := NewQueryBuilder().Table("parents").InsertColumns("name").AddRow("a").AddRow("b")
+	if _, err := NewRunner(db, PropagateDeadline(), MaxParams(1)).Exec(ctx, qb); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	setLocal := regexp.MustCompile(`^set local statement_timeout = \d+$`)
+	calls := fake.Calls()
+	if len(calls) != 4 || !setLocal.MatchString(calls[0].SQL) || !setLocal.MatchString(calls[2].SQL) {
+		t.Fatalf("Expected statement_timeout before each chunk, got: %+v", calls)
+	}
+	if txs := fake.Txs(); len(txs) != 1 || !txs[0].Committed {
+		t.Errorf("Expected one committed transaction, got: %+v", txs)
+	}
+}
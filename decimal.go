@@ -0,0 +1,119 @@
+package query
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+)
+
+// convertBigNumbers replaces *big.Int and *big.Rat params with their exact
+// decimal text so drivers never round them through float64. Decimal types
+// such as shopspring/decimal implement driver.Valuer and are bound as-is.
+func convertBigNumbers(params []interface{}) error {
+	for i, param := range params {
+		switch v := param.(type) {
+		case *big.Int:
+			if v != nil {
+				params[i] = v.String()
+			}
+		case *big.Rat:
+			if v == nil {
+				continue
+			}
+			text, ok := exactDecimal(v)
+			if !ok {
+				return fmt.Errorf("%w: param %d (%s) has no exact decimal representation", ErrUnsupportedParam, i+1, v.RatString())
+			}
+			params[i] = text
+		}
+	}
+	return nil
+}
+
+// exactDecimal renders r as a terminating decimal, which exists only when
+// the denominator has no prime factors other than 2 and 5
+func exactDecimal(r *big.Rat) (string, bool) {
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+
+	denom := new(big.Int).Set(r.Denom())
+	two, five := big.NewInt(2), big.NewInt(5)
+	mod := new(big.Int)
+	twos, fives := 0, 0
+	for mod.Mod(denom, two).Sign() == 0 {
+		denom.Quo(denom, two)
+		twos++
+	}
+	for mod.Mod(denom, five).Sign() == 0 {
+		denom.Quo(denom, five)
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return "", false
+	}
+
+	return r.FloatString(max(twos, fives)), true
+}
+
+// ScanBigInt returns a sql.Scanner that parses a numeric column into dest
+// without going through float64
+func ScanBigInt(dest *big.Int) sql.Scanner {
+	return bigIntScanner{dest}
+}
+
+// ScanBigRat returns a sql.Scanner that parses a numeric or decimal column
+// into dest without going through float64
+func ScanBigRat(dest *big.Rat) sql.Scanner {
+	return bigRatScanner{dest}
+}
+
+type bigIntScanner struct {
+	dest *big.Int
+}
+
+func (s bigIntScanner) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		s.dest.SetInt64(v)
+	case []byte:
+		return s.setString(string(v))
+	case string:
+		return s.setString(v)
+	default:
+		return fmt.Errorf("query: cannot scan %T into *big.Int", src)
+	}
+	return nil
+}
+
+func (s bigIntScanner) setString(text string) error {
+	if _, ok := s.dest.SetString(text, 10); !ok {
+		return fmt.Errorf("query: cannot parse %q as *big.Int", text)
+	}
+	return nil
+}
+
+type bigRatScanner struct {
+	dest *big.Rat
+}
+
+func (s bigRatScanner) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		s.dest.SetInt64(v)
+	case []byte:
+		return s.setString(string(v))
+	case string:
+		return s.setString(v)
+	default:
+		return fmt.Errorf("query: cannot scan %T into *big.Rat", src)
+	}
+	return nil
+}
+
+func (s bigRatScanner) setString(text string) error {
+	if _, ok := s.dest.SetString(text); !ok {
+		return fmt.Errorf("query: cannot parse %q as *big.Rat", text)
+	}
+	return nil
+}
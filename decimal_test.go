@@ -0,0 +1,59 @@
+package query
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestBigNumberParamsBindAsDecimalText(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	query, err := NewQueryBuilder().
+		Table("payments").
+		InsertColumns("id", "amount", "rate").
+		Values(huge, big.NewRat(1999, 100), big.NewRat(1, 8)).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []interface{}{"123456789012345678901234567890", "19.99", "0.125"}
+	for i, param := range query.Params {
+		if param != expected[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expected[i], param)
+		}
+	}
+}
+
+func TestBigRatWithoutExactDecimalIsRejected(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("payments").
+		Where("amount", "=", big.NewRat(1, 3)).
+		TryBuild()
+	if !errors.Is(err, ErrUnsupportedParam) {
+		t.Errorf("Expected ErrUnsupportedParam, got: %v", err)
+	}
+}
+
+func TestScanBigNumbers(t *testing.T) {
+	var i big.Int
+	if err := ScanBigInt(&i).Scan([]byte("98765432109876543210")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if i.String() != "98765432109876543210" {
+		t.Errorf("Expected 98765432109876543210, got: %s", i.String())
+	}
+
+	var r big.Rat
+	if err := ScanBigRat(&r).Scan("1234.5678"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.FloatString(4) != "1234.5678" {
+		t.Errorf("Expected 1234.5678, got: %s", r.FloatString(4))
+	}
+
+	if err := ScanBigInt(&i).Scan(1.5); err == nil {
+		t.Errorf("Expected error scanning float64 into *big.Int")
+	}
+}
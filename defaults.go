@@ -0,0 +1,41 @@
+package query
+
+import "sync"
+
+var (
+	defaultsMu     sync.RWMutex
+	defaultStyle   = DollarNumber
+	defaultDialect = DefaultDialect
+)
+
+// SetDefaultParameterStyle sets the placeholder style of builders created
+// afterwards by NewQueryBuilder, AcquireBuilder, Tree and Closure, so a
+// MySQL application need not call ParameterPlaceholder on each. Call it
+// during initialization; existing builders are unchanged.
+func SetDefaultParameterStyle(style ParameterStyle) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultStyle = style
+}
+
+// SetDefaultDialect sets the dialect of builders created afterwards, along
+// with its placeholder style as the Dialect method does. A later
+// SetDefaultParameterStyle overrides the style.
+func SetDefaultDialect(dialect Dialect) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultDialect = dialect
+	switch dialect {
+	case Postgres:
+		defaultStyle = DollarNumber
+	case MySQL, DuckDB, CQL:
+		defaultStyle = QuestionMark
+	}
+}
+
+// defaults returns the package-level placeholder style and dialect
+func defaults() (ParameterStyle, Dialect) {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return defaultStyle, defaultDialect
+}
@@ -0,0 +1,49 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetDefaultParameterStyle(t *testing.T) {
+	SetDefaultParameterStyle(QuestionMark)
+	defer SetDefaultParameterStyle(DollarNumber)
+
+	query := NewQueryBuilder().Table("users").Where("id", "=", 1).Build()
+	expectedSQL := "select * from users where id = ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	b := AcquireBuilder()
+	defer ReleaseBuilder(b)
+	query = b.Reset().Table("users").Where("id", "=", 1).Build()
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = Closure("nodes").Descendants(1).Build()
+	if !strings.Contains(query.SQL, "where `parent_id` = ?") {
+		t.Errorf("Expected ? placeholders, got: %s", query.SQL)
+	}
+}
+
+func TestSetDefaultDialect(t *testing.T) {
+	SetDefaultDialect(MySQL)
+	defer func() {
+		SetDefaultDialect(DefaultDialect)
+		SetDefaultParameterStyle(DollarNumber)
+	}()
+
+	query := NewQueryBuilder().Table("users").Where("id", "=", 1).OrderByRandom().Build()
+	expectedSQL := "select * from users where id = ? order by rand()"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().ParameterPlaceholder(DollarNumber).Table("users").Where("id", "=", 1).Build()
+	expectedSQL = "select * from users where id = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
@@ -0,0 +1,59 @@
+package query
+
+// Dialect selects SQL syntax that differs between databases
+type Dialect int
+
+const (
+	// DefaultDialect infers the database from the placeholder style:
+	// Postgres for DollarNumber, MySQL for QuestionMark
+	DefaultDialect Dialect = iota
+	Postgres
+	MySQL
+	DuckDB
+	CQL // Cassandra and ScyllaDB
+)
+
+// Dialect targets a specific database and switches to its usual
+// placeholder style: DollarNumber for Postgres, QuestionMark for MySQL,
+// DuckDB and CQL. ParameterPlaceholder may be called afterwards to override it.
+func (b *QueryBuilder) Dialect(dialect Dialect) *QueryBuilder {
+	b.dialect = dialect
+	switch dialect {
+	case Postgres:
+		b.paramStyle = DollarNumber
+	case MySQL, DuckDB, CQL:
+		b.paramStyle = QuestionMark
+	}
+	return b
+}
+
+// target returns the dialect the query is rendered for
+func (b *QueryBuilder) target() Dialect {
+	return resolveDialect(b.dialect, b.paramStyle)
+}
+
+// resolveDialect infers DefaultDialect from the placeholder style
+func resolveDialect(dialect Dialect, style ParameterStyle) Dialect {
+	if dialect != DefaultDialect {
+		return dialect
+	}
+	if style == QuestionMark {
+		return MySQL
+	}
+	return Postgres
+}
+
+func (d Dialect) String() string {
+	switch d {
+	case Postgres:
+		return "postgres"
+	case MySQL:
+		return "mysql"
+	case DuckDB:
+		return "duckdb"
+	case CQL:
+		return "cql"
+	default:
+		return "default"
+	}
+}
@@ -0,0 +1,303 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Change describes one structural difference between two builders
+type Change struct {
+	Kind   string // type, table, columns, join, where, group by, having, order, limit, offset, lock, insert, row, set, union, branch N ...
+	Before string
+	After  string
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Before == "":
+		return fmt.Sprintf("%s added: %s", c.Kind, c.After)
+	case c.After == "":
+		return fmt.Sprintf("%s removed: %s", c.Kind, c.Before)
+	default:
+		return fmt.Sprintf("%s changed: %s -> %s", c.Kind, c.Before, c.After)
+	}
+}
+
+// Diff reports the structural differences between two builders: tables
+// or from subqueries, selected columns, joins, filters, grouping, ordering,
+// locking, and inserted or updated columns and values. Filters are
+// compared in order by column, operator and value, ignoring placeholder
+// numbering; subqueries are compared by their built SQL and params.
+func Diff(a, b *QueryBuilder) []Change {
+	d := &differ{}
+	d.value("type", queryTypeName(a.queryType), queryTypeName(b.queryType))
+	d.value("table", describeFrom(a), describeFrom(b))
+	d.value("columns", describeColumns(a), describeColumns(b))
+	d.list("join", describeJoins(a.joinClauses), describeJoins(b.joinClauses))
+	d.list("where", describeWheres(a.whereClauses), describeWheres(b.whereClauses))
+	d.value("group by", strings.Join(a.groupBy, ", "), strings.Join(b.groupBy, ", "))
+	d.list("having", describeWheres(a.having), describeWheres(b.having))
+	d.list("qualify", describeWheres(a.qualify), describeWheres(b.qualify))
+	d.value("order", describeOrder(a), describeOrder(b))
+	d.value("limit", describeInt(a.limit), describeInt(b.limit))
+	d.value("offset", describeInt(a.offset), describeInt(b.offset))
+	d.value("lock", describeLock(a), describeLock(b))
+	d.value("insert", describeInsert(a), describeInsert(b))
+	d.list("row", describeRows(a), describeRows(b))
+	d.list("set", describeSets(a), describeSets(b))
+	return d.changes
+}
+
+// DiffCompound reports the differences between two unions: the operator,
+// added or removed branches, the Diff of each shared branch with its kinds
+// prefixed by "branch N", and the union's own ordering and pagination
+func DiffCompound(a, b *Compound) []Change {
+	d := &differ{}
+	d.value("union", strings.TrimSpace(a.operator), strings.TrimSpace(b.operator))
+	for i := 0; i < len(a.branches) || i < len(b.branches); i++ {
+		kind := fmt.Sprintf("branch %d", i+1)
+		switch {
+		case i >= len(b.branches):
+			d.value(kind, describeSubquery(a.branches[i]), "")
+		case i >= len(a.branches):
+			d.value(kind, "", describeSubquery(b.branches[i]))
+		default:
+			for _, change := range Diff(a.branches[i], b.branches[i]) {
+				change.Kind = kind + " " + change.Kind
+				d.changes = append(d.changes, change)
+			}
+		}
+	}
+	d.value("order", a.order, b.order)
+	d.value("limit", describeInt(a.limit), describeInt(b.limit))
+	d.value("offset", describeInt(a.offset), describeInt(b.offset))
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) value(kind, before, after string) {
+	if before != after {
+		d.changes = append(d.changes, Change{Kind: kind, Before: before, After: after})
+	}
+}
+
+// list reports the items removed from and added to before, keeping their
+// longest common subsequence, so reordered items count as changes
+func (d *differ) list(kind string, before, after []string) {
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			i++
+			j++
+		case j == len(after) || i < len(before) && common[i+1][j] >= common[i][j+1]:
+			d.changes = append(d.changes, Change{Kind: kind, Before: before[i]})
+			i++
+		default:
+			d.changes = append(d.changes, Change{Kind: kind, After: after[j]})
+			j++
+		}
+	}
+}
+
+func describeFrom(b *QueryBuilder) string {
+	switch {
+	case b.fromSub != nil:
+		return describeTable("("+describeSubquery(b.fromSub)+")", b.tableAlias)
+	case b.fromExpr != nil:
+		return describeTable(describeValue(*b.fromExpr), b.tableAlias)
+	default:
+		return describeTable(b.table, b.tableAlias)
+	}
+}
+
+func describeTable(table, alias string) string {
+	if alias == "" {
+		return table
+	}
+	return table + " as " + alias
+}
+
+func describeJoins(joins []*JoinClause) []string {
+	described := make([]string, len(joins))
+	for i, join := range joins {
+		described[i] = fmt.Sprintf("%s %s on %s", join.Type, describeTable(join.Table, join.Alias), join.Condition)
+	}
+	return described
+}
+
+func describeWheres(wheres []*WhereClause) []string {
+	described := make([]string, len(wheres))
+	for i, where := range wheres {
+		switch {
+		case where.Expr != nil:
+			described[i] = fmt.Sprintf("%s %s", where.JoinType, describeValue(*where.Expr))
+		case where.Group != nil:
+			described[i] = fmt.Sprintf("%s (%s)", where.JoinType, strings.Join(describeWheres(where.Group), " "))
+		case where.Subquery != nil:
+			terms := []string{where.JoinType, where.Column, where.Operator, "(" + describeSubquery(where.Subquery) + ")"}
+			described[i] = strings.Join(slices.DeleteFunc(terms, func(term string) bool { return term == "" }), " ")
+		default:
+			described[i] = fmt.Sprintf("%s %s %s %s", where.JoinType, where.Column, where.Operator, describeValue(where.Value))
+		}
+	}
+	return described
+}
+
+// describeValue prints a bound value, an expression with its args, or a
+// subquery as its SQL
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case Expr:
+		if len(v.Args) == 0 {
+			return v.SQL
+		}
+		return fmt.Sprintf("%s %v", v.SQL, v.Args)
+	case *QueryBuilder:
+		return "(" + describeSubquery(v) + ")"
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// describeSubquery prints the SQL and params sub builds to, or its error
+func describeSubquery(sub *QueryBuilder) string {
+	query, err := sub.TryBuild()
+	if err != nil {
+		return "invalid: " + err.Error()
+	}
+	if len(query.Params) == 0 {
+		return query.SQL
+	}
+	return fmt.Sprintf("%s %v", query.SQL, query.Params)
+}
+
+func describeLock(b *QueryBuilder) string {
+	if b.lockStrength == "" {
+		return ""
+	}
+	lock := b.lockStrength
+	if len(b.lockOf) > 0 {
+		lock += " of " + strings.Join(b.lockOf, ", ")
+	}
+	if b.lockWait != "" {
+		lock += " " + b.lockWait
+	}
+	return lock
+}
+
+// describeInsert prints the inserted columns, sorted since Insert takes
+// them from a map, and the source select if any
+func describeInsert(b *QueryBuilder) string {
+	if b.queryType != InsertQuery {
+		return ""
+	}
+	columns := make([]string, len(b.insertColumns))
+	for i, j := range insertOrder(b) {
+		columns[i] = b.insertColumns[j]
+	}
+	insert := "(" + strings.Join(columns, ", ") + ")"
+	if b.insertSelect != nil {
+		insert += " " + describeSubquery(b.insertSelect)
+	}
+	return insert
+}
+
+// describeRows prints each inserted row of values in describeInsert's
+// column order
+func describeRows(b *QueryBuilder) []string {
+	if b.queryType != InsertQuery || b.insertSelect != nil {
+		return nil
+	}
+	rows := make([][]interface{}, 0, 1+len(b.insertRows))
+	if len(b.insertValues) > 0 {
+		rows = append(rows, b.insertValues)
+	}
+	rows = append(rows, b.insertRows...)
+
+	order := insertOrder(b)
+	described := make([]string, len(rows))
+	for i, row := range rows {
+		values := make([]string, 0, len(row))
+		for _, j := range order {
+			if j < len(row) {
+				values = append(values, describeValue(row[j]))
+			}
+		}
+		described[i] = "(" + strings.Join(values, ", ") + ")"
+	}
+	return described
+}
+
+// insertOrder returns the indexes of the insert columns sorted by name
+func insertOrder(b *QueryBuilder) []int {
+	order := make([]int, len(b.insertColumns))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return strings.Compare(b.insertColumns[i], b.insertColumns[j])
+	})
+	return order
+}
+
+// describeSets prints each "column = value" of an update, sorted by column
+// since the order of assignments does not matter
+func describeSets(b *QueryBuilder) []string {
+	described := make([]string, len(b.updateColumns))
+	for i, column := range b.updateColumns {
+		described[i] = column + " = " + describeValue(b.updateValues[i])
+	}
+	slices.Sort(described)
+	return described
+}
+
+func describeInt(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func describeColumns(b *QueryBuilder) string {
+	columns := append([]string{}, b.columns...)
+	for _, expr := range b.selectExprs {
+		columns = append(columns, describeValue(expr))
+	}
+	for _, sub := range b.selectSubs {
+		columns = append(columns, "("+describeSubquery(sub.sub)+") as "+sub.alias)
+	}
+	return strings.Join(columns, ", ")
+}
+
+func describeOrder(b *QueryBuilder) string {
+	var terms []string
+	if b.order != "" {
+		terms = append(terms, b.order)
+	}
+	for _, expr := range b.orderExprs {
+		terms = append(terms, describeValue(expr))
+	}
+	if b.randomOrder {
+		terms = append(terms, "random")
+	}
+	return strings.Join(terms, ", ")
+}
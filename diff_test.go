@@ -0,0 +1,165 @@
+package query
+
+import "testing"
+
+func TestDiffIdentical(t *testing.T) {
+	build := func() *QueryBuilder {
+		return NewQueryBuilder().
+			Table("users").
+			Select("id", "name").
+			LeftJoin("accounts", "accounts.id = users.account_id").
+			Where("active", "=", true).
+			OrderBy("name")
+	}
+
+	if changes := Diff(build(), build()); len(changes) != 0 {
+		t.Errorf("Expected no changes, got: %v", changes)
+	}
+}
+
+func TestDiffReportsChanges(t *testing.T) {
+	a := NewQueryBuilder().
+		Table("users").
+		Select("id", "name").
+		Where("active", "=", true).
+		Where("age", ">", 18).
+		OrderBy("name")
+	b := NewQueryBuilder().
+		Table("users").
+		Select("id", "name").
+		Join("accounts", "accounts.id = users.account_id").
+		Where("active", "=", true).
+		Where("age", ">", 21).
+		OrderBy("name desc").
+		Limit(10)
+
+	changes := Diff(a, b)
+	expected := []string{
+		"join added: JOIN accounts on accounts.id = users.account_id",
+		"where removed: and age > 18",
+		"where added: and age > 21",
+		"order changed: name -> name desc",
+		"limit added: 10",
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got: %v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Errorf("Expected change %d: %s, got: %s", i, expected[i], change.String())
+		}
+	}
+}
+
+func assertChanges(t *testing.T, changes []Change, expected ...string) {
+	t.Helper()
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got: %v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Errorf("Expected change %d: %s, got: %s", i, expected[i], change.String())
+		}
+	}
+}
+
+func TestDiffWhereOrder(t *testing.T) {
+	a := NewQueryBuilder().Table("users").Where("active", "=", true).Where("age", ">", 18)
+	b := NewQueryBuilder().Table("users").Where("age", ">", 18).Where("active", "=", true)
+
+	assertChanges(t, Diff(a, b),
+		"where removed: and active = true",
+		"where added: and active = true",
+	)
+}
+
+func TestDiffWhereSubquery(t *testing.T) {
+	a := NewQueryBuilder().Table("users").
+		WhereInQuery("id", NewQueryBuilder().Table("orders").Select("user_id").Where("total", ">", 100))
+	b := NewQueryBuilder().Table("users").
+		WhereInQuery("id", NewQueryBuilder().Table("orders").Select("user_id").Where("total", ">", 200))
+
+	assertChanges(t, Diff(a, b),
+		"where removed: and id in (select user_id from orders where total > $1 [100])",
+		"where added: and id in (select user_id from orders where total > $1 [200])",
+	)
+}
+
+func TestDiffGroupByAndHaving(t *testing.T) {
+	a := NewQueryBuilder().Table("orders").Select("user_id").GroupBy("user_id").Having("count(*)", ">", 1)
+	b := NewQueryBuilder().Table("orders").Select("user_id").GroupBy("user_id", "region").Having("count(*)", ">", 5)
+
+	assertChanges(t, Diff(a, b),
+		"group by changed: user_id -> user_id, region",
+		"having removed: and count(*) > 1",
+		"having added: and count(*) > 5",
+	)
+}
+
+func TestDiffLock(t *testing.T) {
+	a := NewQueryBuilder().Table("jobs").ForUpdate()
+	b := NewQueryBuilder().Table("jobs").ForUpdate().SkipLocked()
+
+	assertChanges(t, Diff(a, b), "lock changed: update -> update skip locked")
+}
+
+func TestDiffFromSub(t *testing.T) {
+	a := NewQueryBuilder().FromSub(NewQueryBuilder().Table("orders").Where("total", ">", 100), "o")
+	b := NewQueryBuilder().FromSub(NewQueryBuilder().Table("orders").Where("total", ">", 200), "o")
+
+	assertChanges(t, Diff(a, b),
+		"table changed: (select * from orders where total > $1 [100]) as o -> (select * from orders where total > $1 [200]) as o",
+	)
+}
+
+func TestDiffInsert(t *testing.T) {
+	a := NewQueryBuilder().Table("users").Insert(map[string]interface{}{"name": "Ann", "age": 30})
+	b := NewQueryBuilder().Table("users").InsertColumns("name", "age").Values("Bob", 30).AddRow("Cy", 40)
+
+	assertChanges(t, Diff(a, b),
+		"row removed: (30, Ann)",
+		"row added: (30, Bob)",
+		"row added: (40, Cy)",
+	)
+
+	c := NewQueryBuilder().Table("users").Insert(map[string]interface{}{"name": "Bob"})
+	if changes := Diff(a, NewQueryBuilder().Table("users").InsertColumns("age", "name").Values(30, "Ann")); len(changes) != 0 {
+		t.Errorf("Expected no changes for reordered columns, got: %v", changes)
+	}
+
+	assertChanges(t, Diff(b, c),
+		"insert changed: (age, name) -> (name)",
+		"row removed: (30, Bob)",
+		"row removed: (40, Cy)",
+		"row added: (Bob)",
+	)
+}
+
+func TestDiffUpdate(t *testing.T) {
+	a := NewQueryBuilder().Table("users").Update(map[string]interface{}{"name": "Ann", "age": 30}).Where("id", "=", 1)
+	b := NewQueryBuilder().Table("users").Update(map[string]interface{}{"name": "Ann", "age": 31}).Where("id", "=", 1)
+
+	assertChanges(t, Diff(a, b),
+		"set removed: age = 30",
+		"set added: age = 31",
+	)
+}
+
+func TestDiffCompound(t *testing.T) {
+	a := Union(
+		NewQueryBuilder().Table("users").Select("id"),
+		NewQueryBuilder().Table("admins").Select("id"),
+	)
+	b := UnionAll(
+		NewQueryBuilder().Table("users").Select("id").Where("active", "=", true),
+		NewQueryBuilder().Table("admins").Select("id"),
+		NewQueryBuilder().Table("guests").Select("id"),
+	).Limit(10)
+
+	assertChanges(t, DiffCompound(a, b),
+		"union changed: union -> union all",
+		"branch 1 where added: and active = true",
+		"branch 3 added: select id from guests",
+		"limit added: 10",
+	)
+}
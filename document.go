@@ -0,0 +1,196 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxDocumentDepth bounds how deeply and/or groups may nest in a filter
+// document
+const maxDocumentDepth = 16
+
+// WhereDocument adds the conditions of a JSON filter document, such as a
+// saved report filter:
+//
+//	{"and": [{"age": {"gt": 18}}, {"or": [{"status": "active"}, {"vip": true}]}]}
+//
+// An object's entries are joined with AND; "and" and "or" take an array of
+// documents and become parenthesized groups. Any other key is a field,
+// looked up in allowed (public name to column) exactly like
+// OrderByAllowed. A field maps to a string, number or boolean for
+// equality, or to an object of operators as in BindFilter: eq, ne, gt,
+// gte, lt, lte, like, ilike, contains and in (with an array). Values are
+// always bound. Malformed documents, nulls and unknown fields or operators
+// are reported by TryBuild as ErrInvalidFilter.
+func (b *QueryBuilder) WhereDocument(doc []byte, allowed map[string]string) *QueryBuilder {
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+	var node interface{}
+	if err := decoder.Decode(&node); err != nil {
+		return b.fail(fmt.Errorf("%w: %v", ErrInvalidFilter, err))
+	}
+	if decoder.More() {
+		return b.fail(fmt.Errorf("%w: trailing data after document", ErrInvalidFilter))
+	}
+
+	clauses, err := documentClauses(node, allowed, 0)
+	if err != nil {
+		return b.fail(err)
+	}
+	if len(clauses) > 0 {
+		b.whereClauses = append(b.whereClauses, documentGroup(clauses, "and"))
+	}
+	return b
+}
+
+func documentClauses(node interface{}, allowed map[string]string, depth int) ([]*WhereClause, error) {
+	if depth > maxDocumentDepth {
+		return nil, fmt.Errorf("%w: document nested deeper than %d", ErrInvalidFilter, maxDocumentDepth)
+	}
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an object, got %s", ErrInvalidFilter, documentType(node))
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var clauses []*WhereClause
+	for _, key := range keys {
+		switch key {
+		case "and", "or":
+			items, ok := object[key].([]interface{})
+			if !ok || len(items) == 0 {
+				return nil, fmt.Errorf("%w: %q needs a non-empty array", ErrInvalidFilter, key)
+			}
+			var group []*WhereClause
+			for _, item := range items {
+				sub, err := documentClauses(item, allowed, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				if len(sub) > 0 {
+					group = append(group, documentGroup(sub, key))
+				}
+			}
+			if len(group) > 0 {
+				clauses = append(clauses, documentGroup(group, "and"))
+			}
+		default:
+			column, ok := allowed[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, key)
+			}
+			fieldClauses, err := documentField(key, column, object[key])
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, fieldClauses...)
+		}
+	}
+	return clauses, nil
+}
+
+// documentField returns the conditions on one field
+func documentField(field, column string, node interface{}) ([]*WhereClause, error) {
+	ops, ok := node.(map[string]interface{})
+	if !ok {
+		value, err := documentValue(field, node)
+		if err != nil {
+			return nil, err
+		}
+		return []*WhereClause{{Column: column, Operator: "=", Value: value, JoinType: "and"}}, nil
+	}
+
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var clauses []*WhereClause
+	for _, name := range names {
+		operator, ok := filterOperators[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q has unknown operator %q", ErrInvalidFilter, field, name)
+		}
+
+		var value interface{}
+		switch name {
+		case "in":
+			items, ok := ops[name].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: field %q uses in without an array", ErrInvalidFilter, field)
+			}
+			values := make([]interface{}, len(items))
+			for i, item := range items {
+				v, err := documentValue(field, item)
+				if err != nil {
+					return nil, err
+				}
+				values[i] = v
+			}
+			value = values
+		default:
+			v, err := documentValue(field, ops[name])
+			if err != nil {
+				return nil, err
+			}
+			if name == "contains" {
+				v = "%" + EscapeLike(fmt.Sprint(v)) + "%"
+			}
+			value = v
+		}
+		clauses = append(clauses, &WhereClause{Column: column, Operator: operator, Value: value, JoinType: "and"})
+	}
+	return clauses, nil
+}
+
+// documentValue converts a JSON scalar into a bindable value
+func documentValue(field string, node interface{}) (interface{}, error) {
+	switch v := node.(type) {
+	case string, bool:
+		return v, nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %q has invalid number %s", ErrInvalidFilter, field, v)
+		}
+		return f, nil
+	default:
+		return nil, fmt.Errorf("%w: field %q has %s value", ErrInvalidFilter, field, documentType(node))
+	}
+}
+
+// documentGroup joins clauses into one condition, parenthesizing when there
+// is more than one
+func documentGroup(clauses []*WhereClause, joinType string) *WhereClause {
+	if len(clauses) == 1 {
+		clause := *clauses[0]
+		clause.JoinType = joinType
+		return &clause
+	}
+	return &WhereClause{Group: clauses, JoinType: joinType}
+}
+
+func documentType(node interface{}) string {
+	switch node.(type) {
+	case nil:
+		return "null"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return strings.TrimPrefix(fmt.Sprintf("%T", node), "json.")
+	}
+}
@@ -0,0 +1,61 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+var documentFields = map[string]string{
+	"age":    "users.age",
+	"status": "users.status",
+	"vip":    "users.vip",
+	"name":   "users.name",
+}
+
+func TestWhereDocument(t *testing.T) {
+	doc := `{"and": [{"age": {"gt": 18}}, {"or": [{"status": "active"}, {"vip": true}]}]}`
+	query := NewQueryBuilder().
+		Table("users").
+		Where("deleted", "=", false).
+		WhereDocument([]byte(doc), documentFields).
+		Build()
+
+	expectedSQL := "select * from users where deleted = $1 and (users.age > $2 and (users.status = $3 or users.vip = $4))"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 4 || query.Params[1] != int64(18) || query.Params[2] != "active" {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestWhereDocumentOperators(t *testing.T) {
+	doc := `{"name": {"contains": "50%"}, "status": {"in": ["active", "trial"]}}`
+	query := NewQueryBuilder().Table("users").WhereDocument([]byte(doc), documentFields).Build()
+
+	expectedSQL := "select * from users where (users.name ilike $1 and users.status in ($2, $3))"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[0] != `%50\%%` {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestWhereDocumentErrors(t *testing.T) {
+	docs := []string{
+		`{"password": "x"}`,
+		`{"age": {"between": [1, 2]}}`,
+		`{"age": null}`,
+		`{"or": []}`,
+		`[{"age": 1}]`,
+		`{"age": 1} {}`,
+		`{"age":`,
+	}
+	for _, doc := range docs {
+		_, err := NewQueryBuilder().Table("users").WhereDocument([]byte(doc), documentFields).TryBuild()
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s: expected ErrInvalidFilter, got: %v", doc, err)
+		}
+	}
+}
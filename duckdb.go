@@ -0,0 +1,115 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+type starReplacement struct {
+	Column string
+	Expr   Expr
+}
+
+// ReadParquet selects from Parquet files with DuckDB's read_parquet table
+// function instead of a table. path is bound as a parameter and may be a
+// glob such as "events/*.parquet". Use As to alias the result.
+func (b *QueryBuilder) ReadParquet(path string) *QueryBuilder {
+	expr := Raw("read_parquet(?)", path)
+	b.fromExpr = &expr
+	b.table = "read_parquet"
+	return b
+}
+
+// Qualify adds a raw QUALIFY condition, which filters on window functions
+// after they are computed; each ? in sql binds the next arg. DuckDB only.
+func (b *QueryBuilder) Qualify(sql string, args ...interface{}) *QueryBuilder {
+	expr := Raw(sql, args...)
+	b.qualify = append(b.qualify, &WhereClause{
+		Expr:     &expr,
+		JoinType: "and",
+	})
+	return b
+}
+
+// Exclude removes columns from a star in the select list:
+// "select * exclude (password, secret)". DuckDB only.
+func (b *QueryBuilder) Exclude(columns ...string) *QueryBuilder {
+	b.starExclude = append(b.starExclude, columns...)
+	return b
+}
+
+// Replace swaps a column in a star for an expression under the same name:
+// "select * replace (lower(email) as email)". DuckDB only.
+func (b *QueryBuilder) Replace(column string, expr Expr) *QueryBuilder {
+	b.starReplace = append(b.starReplace, starReplacement{Column: column, Expr: expr})
+	return b
+}
+
+// writeStarModifiers writes the EXCLUDE and REPLACE lists after the first
+// star column
+func (b *QueryBuilder) writeStarModifiers(w *sqlWriter, column string) {
+	if column != "*" && !hasSuffixStar(column) {
+		return
+	}
+	if len(b.starExclude) > 0 {
+		w.write(" exclude (")
+		for i, excluded := range b.starExclude {
+			if i > 0 {
+				w.write(", ")
+			}
+			w.ident(excluded)
+		}
+		w.write(")")
+	}
+	if len(b.starReplace) > 0 {
+		w.write(" replace (")
+		for i, replace := range b.starReplace {
+			if i > 0 {
+				w.write(", ")
+			}
+			w.expr(replace.Expr)
+			w.write(" as ")
+			w.ident(replace.Column)
+		}
+		w.write(")")
+	}
+}
+
+func hasSuffixStar(column string) bool {
+	return strings.HasSuffix(column, ".*")
+}
+
+// validateDuckDB rejects DuckDB-only clauses on other dialects, and star
+// modifiers without a star to attach to
+func (b *QueryBuilder) validateDuckDB() error {
+	if len(b.qualify) > 0 {
+		if err := b.require(Qualify); err != nil {
+			return err
+		}
+	}
+	var feature string
+	switch {
+	case b.fromExpr != nil && strings.HasPrefix(b.fromExpr.SQL, "read_parquet("):
+		feature = "read_parquet"
+	case len(b.starExclude) > 0 || len(b.starReplace) > 0:
+		feature = "star exclude/replace"
+	default:
+		return nil
+	}
+	if b.target() != DuckDB {
+		return fmt.Errorf("%w: %s on %s", ErrUnsupportedFeature, feature, b.target())
+	}
+
+	if len(b.starExclude) > 0 || len(b.starReplace) > 0 {
+		stars := 0
+		for _, column := range b.columns {
+			if column == "*" || hasSuffixStar(column) {
+				stars++
+			}
+		}
+		if stars != 1 {
+			return fmt.Errorf("%w: exclude and replace need exactly one star column, got %d", ErrUnsupportedFeature, stars)
+		}
+	}
+	return nil
+}
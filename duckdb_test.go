@@ -0,0 +1,91 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDuckDBReadParquet(t *testing.T) {
+	query := NewQueryBuilder().
+		Dialect(DuckDB).
+		ReadParquet("events/*.parquet").
+		As("e").
+		Select("e.user_id").
+		SelectRaw("count(*) as n").
+		Where("e.kind", "=", "click").
+		GroupBy("e.user_id").
+		Build()
+
+	expectedSQL := "select e.user_id, count(*) as n from read_parquet(?) as e where e.kind = ? group by e.user_id"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[0] != "events/*.parquet" || query.Params[1] != "click" {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestDuckDBQualify(t *testing.T) {
+	query := NewQueryBuilder().
+		Dialect(DuckDB).
+		Table("orders").
+		Select("id").
+		SelectRaw("row_number() over (partition by customer_id order by created_at desc) as rn").
+		Qualify("row_number() over (partition by customer_id order by created_at desc) <= ?", 3).
+		Build()
+
+	expectedSQL := "select id, row_number() over (partition by customer_id order by created_at desc) as rn from orders qualify row_number() over (partition by customer_id order by created_at desc) <= ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestDuckDBStarModifiers(t *testing.T) {
+	query := NewQueryBuilder().
+		Dialect(DuckDB).
+		QuoteStyle(DoubleQuote).
+		Table("users").
+		Exclude("password", "secret").
+		Replace("email", Raw("lower(email)")).
+		Build()
+
+	expectedSQL := `select * exclude ("password", "secret") replace (lower(email) as "email") from "users"`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestDuckDBSample(t *testing.T) {
+	query := NewQueryBuilder().Dialect(DuckDB).Table("events").Sample(10).Build()
+
+	expectedSQL := "select * from events tablesample system (10%)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestDuckDBFeaturesRequireDuckDB(t *testing.T) {
+	builders := []*QueryBuilder{
+		NewQueryBuilder().ReadParquet("a.parquet"),
+		NewQueryBuilder().Table("t").Qualify("x = ?", 1),
+		NewQueryBuilder().Table("t").ParameterPlaceholder(QuestionMark).Exclude("a"),
+		NewQueryBuilder().Dialect(DuckDB).Table("t").Select("a", "b").Exclude("a"),
+	}
+	for _, qb := range builders {
+		if _, err := qb.TryBuild(); !errors.Is(err, ErrUnsupportedFeature) {
+			t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+		}
+	}
+}
+
+func TestDialectInference(t *testing.T) {
+	if d := NewQueryBuilder().target(); d != Postgres {
+		t.Errorf("Expected Postgres by default, got: %s", d)
+	}
+	if d := NewQueryBuilder().ParameterPlaceholder(QuestionMark).target(); d != MySQL {
+		t.Errorf("Expected MySQL for QuestionMark, got: %s", d)
+	}
+	if d := NewQueryBuilder().Dialect(DuckDB).ParameterPlaceholder(DollarNumber).target(); d != DuckDB {
+		t.Errorf("Expected explicit dialect to win, got: %s", d)
+	}
+}
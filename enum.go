@@ -0,0 +1,49 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ErrInvalidEnum is returned when an Enum param holds a value outside its
+// allowed set
+var ErrInvalidEnum = errors.New("invalid enum value")
+
+// Enum is implemented by string-based enum types. Params of such types are
+// checked against EnumValues when the query is built or a template bound,
+// so an out-of-range value never reaches the database.
+//
+//	type Status string
+//
+//	func (Status) EnumValues() []string { return []string{"active", "banned"} }
+type Enum interface {
+	EnumValues() []string
+}
+
+// checkEnum reports an Enum param whose value is not one of its values.
+// Params of other types, and nil pointers, pass.
+func checkEnum(param interface{}) error {
+	enum, ok := param.(Enum)
+	if !ok {
+		return nil
+	}
+	v := reflect.ValueOf(param)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	var value string
+	if v.Kind() == reflect.String {
+		value = v.String()
+	} else {
+		value = fmt.Sprint(v.Interface())
+	}
+	if !slices.Contains(enum.EnumValues(), value) {
+		return fmt.Errorf("%w: %q is not one of %v (%T)", ErrInvalidEnum, value, enum.EnumValues(), param)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+type testStatus string
+
+func (testStatus) EnumValues() []string {
+	return []string{"active", "banned"}
+}
+
+func TestEnumParam(t *testing.T) {
+	query, err := NewQueryBuilder().Table("users").Where("status", "=", testStatus("active")).TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := "select * from users where status = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	_, err = NewQueryBuilder().Table("users").Where("status", "=", testStatus("deleted")).TryBuild()
+	if !errors.Is(err, ErrInvalidEnum) {
+		t.Errorf("Expected ErrInvalidEnum, got: %v", err)
+	}
+
+	status := testStatus("pending")
+	_, err = NewQueryBuilder().Table("users").Update(map[string]interface{}{"status": &status}).TryBuild()
+	if !errors.Is(err, ErrInvalidEnum) {
+		t.Errorf("Expected ErrInvalidEnum for a pointer, got: %v", err)
+	}
+
+	var missing *testStatus
+	if _, err := NewQueryBuilder().Table("users").Where("status", "=", missing).TryBuild(); err != nil {
+		t.Errorf("Expected a nil enum pointer to pass, got: %v", err)
+	}
+}
+
+func TestEnumTemplateBind(t *testing.T) {
+	tpl, err := NewQueryBuilder().Table("users").Where("status", "=", Slot("status")).Template()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := tpl.Bind(map[string]interface{}{"status": testStatus("banned")}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := tpl.Bind(map[string]interface{}{"status": testStatus("gone")}); !errors.Is(err, ErrInvalidEnum) {
+		t.Errorf("Expected ErrInvalidEnum, got: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// CountEstimate returns the planner's estimate of how many rows the query
+// would return, without scanning the table. Unfiltered queries read the
+// table statistics (pg_class.reltuples on Postgres, information_schema.tables
+// on MySQL); queries with joins or
+// conditions use the row estimate from Plan. Estimates are only as fresh as
+// the last ANALYZE.
+func (b *QueryBuilder) CountEstimate(ctx context.Context, db Querier) (int64, error) {
+	if err := b.validate(); err != nil {
+		return 0, err
+	}
+	if b.target() == DuckDB {
+		return 0, fmt.Errorf("%w: row estimates on %s", ErrUnsupportedFeature, b.target())
+	}
+
+	if len(b.whereClauses) == 0 && len(b.joinClauses) == 0 && !b.sampling() {
+		n, ok, err := b.tableEstimate(ctx, db)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return b.capEstimate(n), nil
+		}
+	}
+
+	plan, err := b.Plan(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	return b.capEstimate(int64(plan.Rows)), nil
+}
+
+// tableEstimate reads the row count kept in the table statistics. ok is
+// false when the table has never been analyzed.
+func (b *QueryBuilder) tableEstimate(ctx context.Context, db Querier) (n int64, ok bool, err error) {
+	var rows *sql.Rows
+	if b.target() == MySQL {
+		schema, table := "", b.table
+		if i := strings.LastIndexByte(table, '.'); i >= 0 {
+			schema, table = table[:i], table[i+1:]
+		}
+		if schema == "" {
+			rows, err = db.QueryContext(ctx, "select table_rows from information_schema.tables where table_schema = database() and table_name = ?", table)
+		} else {
+			rows, err = db.QueryContext(ctx, "select table_rows from information_schema.tables where table_schema = ? and table_name = ?", schema, table)
+		}
+	} else {
+		rows, err = db.QueryContext(ctx, "select reltuples::bigint from pg_class where oid = $1::regclass", b.table)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+
+	var estimate sql.NullInt64
+	if rows.Next() {
+		if err := rows.Scan(&estimate); err != nil {
+			return 0, false, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, false, err
+	}
+
+	// Postgres reports -1 for tables that have never been vacuumed or analyzed
+	if !estimate.Valid || estimate.Int64 < 0 {
+		return 0, false, nil
+	}
+	return estimate.Int64, true, nil
+}
+
+func (b *QueryBuilder) capEstimate(n int64) int64 {
+	if b.offset > 0 {
+		n = max(n-int64(b.offset), 0)
+	}
+	if b.limit > 0 {
+		n = min(n, int64(b.limit))
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestCountEstimatePostgresTable(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select reltuples::bigint from pg_class where oid = $1::regclass",
+		[]string{"reltuples"},
+		[]driver.Value{int64(12000000)},
+	)
+
+	n, err := NewQueryBuilder().Table("events").CountEstimate(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 12000000 {
+		t.Errorf("Expected 12000000, got: %d", n)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 || calls[0].Args[0] != "events" {
+		t.Errorf("Expected one pg_class lookup for events, got: %+v", calls)
+	}
+}
+
+func TestCountEstimateFallsBackToPlan(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select reltuples::bigint from pg_class where oid = $1::regclass",
+		[]string{"reltuples"},
+		[]driver.Value{int64(-1)},
+	)
+	fake.On("explain (format json) select * from events",
+		[]string{"QUERY PLAN"},
+		[]driver.Value{[]byte(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 2550}}]`)},
+	)
+
+	n, err := NewQueryBuilder().Table("events").CountEstimate(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 2550 {
+		t.Errorf("Expected 2550, got: %d", n)
+	}
+}
+
+func TestCountEstimateFiltered(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("explain select * from events where kind = ? limit 100",
+		[]string{"id", "table", "type", "key", "rows"},
+		[]driver.Value{int64(1), "events", "ref", "events_kind", int64(48000)},
+	)
+
+	n, err := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("events").
+		Where("kind", "=", "click").
+		Limit(100).
+		CountEstimate(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 100 {
+		t.Errorf("Expected the limit to cap the estimate at 100, got: %d", n)
+	}
+}
+
+func TestCountEstimateMySQLTable(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select table_rows from information_schema.tables where table_schema = ? and table_name = ?",
+		[]string{"table_rows"},
+		[]driver.Value{int64(731)},
+	)
+
+	n, err := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("shop.orders").
+		CountEstimate(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 731 {
+		t.Errorf("Expected 731, got: %d", n)
+	}
+}
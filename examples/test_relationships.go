@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"github.com/scape-labs/query"
 )
 
@@ -13,7 +14,10 @@ func main() {
 		Select("id", "name", "email").
 		Where("age", ">", 18)
 		
-	query1 := qb1.Build()
+	query1, err := qb1.TryBuild()
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Printf("SQL: %s\n", query1.Sql())
 	fmt.Printf("Params: %v\n\n", query1.Params)
 
@@ -25,7 +29,10 @@ func main() {
 		LeftJoin("accounts", "accounts.id = users.account_id").
 		Where("users.active", "=", true)
 		
-	query2 := qb2.Build()
+	query2, err := qb2.TryBuild()
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Printf("SQL: %s\n", query2.Sql())
 	fmt.Printf("Params: %v\n\n", query2.Params)
 
@@ -41,7 +48,10 @@ func main() {
 		OrderBy("p.created_at").
 		Limit(10)
 		
-	query3 := qb3.Build()
+	query3, err := qb3.TryBuild()
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Printf("SQL: %s\n", query3.Sql())
 	fmt.Printf("Params: %v\n\n", query3.Params)
 
@@ -0,0 +1,37 @@
+package query
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotSelect is returned by TryBuild when a select-only feature is used
+// on an insert, update or delete
+var ErrNotSelect = errors.New("only supported on select queries")
+
+// AsExists wraps the query as "select exists(select 1 from ...)", which
+// returns a single boolean and lets the database stop at the first match.
+// The select list is replaced, and ordering is dropped unless the query is
+// limited or offset.
+func (b *QueryBuilder) AsExists() *QueryBuilder {
+	b.exists = true
+	return b
+}
+
+// Exists runs qb as an existence check and reports whether any row matched.
+// A *QueryBuilder is wrapped with AsExists without modifying it; other
+// Builders must already select a single boolean.
+func (r *Runner) Exists(ctx context.Context, qb Builder) (bool, error) {
+	if b, ok := qb.(*QueryBuilder); ok && !b.exists {
+		qb = b.Clone().AsExists()
+	}
+	row, err := r.QueryRow(ctx, qb)
+	if err != nil {
+		return false, err
+	}
+	var exists bool
+	if err := row.Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestAsExists(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		Select("id", "email").
+		Where("email", "=", "a@example.com").
+		OrderBy("id").
+		AsExists().
+		Build()
+
+	expectedSQL := "select exists(select 1 from users where email = $1)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestAsExistsKeepsPagination(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("events").
+		OrderBy("id").
+		Offset(1000).
+		AsExists().
+		Build()
+
+	expectedSQL := "select exists(select 1 from events order by id offset 1000)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestAsExistsRejectsWrites(t *testing.T) {
+	_, err := NewQueryBuilder().Table("users").Delete().AsExists().TryBuild()
+	if !errors.Is(err, ErrNotSelect) {
+		t.Errorf("Expected ErrNotSelect, got: %v", err)
+	}
+}
+
+func TestRunnerExists(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select exists(select 1 from users where email = $1)",
+		[]string{"exists"},
+		[]driver.Value{true},
+	)
+
+	qb := NewQueryBuilder().Table("users").Where("email", "=", "a@example.com")
+	exists, err := NewRunner(db).Exists(context.Background(), qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !exists {
+		t.Errorf("Expected a match")
+	}
+	if query := qb.Build(); query.SQL != "select * from users where email = $1" {
+		t.Errorf("Expected caller's builder to be untouched, got: %s", query.SQL)
+	}
+}
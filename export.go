@@ -0,0 +1,188 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrDuplicateColumn is returned by ExportJSON when two result columns
+// share a name, which a JSON object cannot hold; alias one of them
+var ErrDuplicateColumn = errors.New("duplicate column name")
+
+// Querier is the subset of *sql.DB, *sql.Conn and *sql.Tx used to run
+// built queries
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type exportOptions struct {
+	header bool
+}
+
+// ExportOption configures ExportCSV and ExportJSON
+type ExportOption func(*exportOptions)
+
+// WithHeader writes the column names as the first CSV record
+func WithHeader() ExportOption {
+	return func(o *exportOptions) {
+		o.header = true
+	}
+}
+
+// ExportCSV runs qb like Query, with the Runner's checks and options, and
+// streams every row to w as CSV. Rows are read through database/sql rather
+// than Postgres' COPY (query) TO STDOUT, which database/sql cannot run; for
+// dumps too large for that, use the driver's own COPY support.
+func (r *Runner) ExportCSV(ctx context.Context, qb *QueryBuilder, w io.Writer, opts ...ExportOption) error {
+	var o exportOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	rows, err := r.Query(ctx, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if o.header {
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+	}
+
+	values, scanArgs := scanTargets(len(columns))
+	record := make([]string, len(columns))
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return err
+		}
+		qb.convertTimes(values)
+		for i, value := range values {
+			record[i] = formatCSVValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportJSON runs qb like Query, with the Runner's checks and options, and
+// streams the rows to w as a JSON array of objects keyed by column name, in
+// column order
+func (r *Runner) ExportJSON(ctx context.Context, qb *QueryBuilder, w io.Writer) error {
+	rows, err := r.Query(ctx, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	keys := make([][]byte, len(columns))
+	seen := make(map[string]bool, len(columns))
+	for i, column := range columns {
+		if seen[column] {
+			return fmt.Errorf("%w: %s", ErrDuplicateColumn, column)
+		}
+		seen[column] = true
+		if keys[i], err = json.Marshal(column); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	types := qb.columnTypes()
+	values, scanArgs := scanTargets(len(columns))
+	var row bytes.Buffer
+	first := true
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return err
+		}
+		qb.convertTimes(values)
+
+		row.Reset()
+		if !first {
+			row.WriteByte(',')
+		}
+		first = false
+		row.WriteByte('{')
+		for i, column := range columns {
+			value := values[i]
+			if raw, ok := value.([]byte); ok && types[column] == JSON && json.Valid(raw) {
+				value = json.RawMessage(raw)
+			} else if ok {
+				value = string(raw)
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				row.WriteByte(',')
+			}
+			row.Write(keys[i])
+			row.WriteByte(':')
+			row.Write(data)
+		}
+		row.WriteByte('}')
+		if _, err := w.Write(row.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+func scanTargets(n int) ([]interface{}, []interface{}) {
+	values := make([]interface{}, n)
+	scanArgs := make([]interface{}, n)
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	return values, scanArgs
+}
+
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,134 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestExportCSV(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select id, name from users where active = $1",
+		[]string{"id", "name"},
+		[]driver.Value{int64(1), "Doe, John"},
+		[]driver.Value{int64(2), `Jane "JJ" Doe`},
+		[]driver.Value{int64(3), nil},
+	)
+
+	var buf bytes.Buffer
+	qb := NewQueryBuilder().
+		Table("users").
+		Select("id", "name").
+		Where("active", "=", true)
+	err := NewRunner(db).ExportCSV(context.Background(), qb, &buf, WithHeader())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "id,name\n1,\"Doe, John\"\n2,\"Jane \"\"JJ\"\" Doe\"\n3,\n"
+	if buf.String() != expected {
+		t.Errorf("Expected CSV: %q, got: %q", expected, buf.String())
+	}
+
+	queries := fake.Calls()
+	if len(queries) != 1 || len(queries[0].Args) != 1 || queries[0].Args[0] != true {
+		t.Errorf("Expected one query with params [true], got: %v", queries)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select id, name from users",
+		[]string{"id", "name"},
+		[]driver.Value{int64(1), []byte("John")},
+		[]driver.Value{int64(2), nil},
+	)
+
+	var buf bytes.Buffer
+	qb := NewQueryBuilder().
+		Table("users").
+		Select("id", "name")
+	err := NewRunner(db).ExportJSON(context.Background(), qb, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `[{"id":1,"name":"John"},{"id":2,"name":null}]`
+	if buf.String() != expected {
+		t.Errorf("Expected JSON: %s, got: %s", expected, buf.String())
+	}
+}
+
+func TestExportJSONColumnOrder(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select users.name, users.id, accounts.id from users JOIN accounts on accounts.id = users.account_id",
+		[]string{"name", "id", "id"},
+		[]driver.Value{[]byte("John"), int64(1), int64(7)},
+	)
+	fake.On("select name, id from users",
+		[]string{"name", "id"},
+		[]driver.Value{[]byte("John"), int64(1)},
+	)
+
+	var buf bytes.Buffer
+	qb := NewQueryBuilder().Table("users").Select("name", "id")
+	err := NewRunner(db).ExportJSON(context.Background(), qb, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `[{"name":"John","id":1}]`
+	if buf.String() != expected {
+		t.Errorf("Expected JSON: %s, got: %s", expected, buf.String())
+	}
+
+	buf.Reset()
+	qb = NewQueryBuilder().
+		Table("users").
+		Select("users.name", "users.id", "accounts.id").
+		Join("accounts", "accounts.id = users.account_id")
+	err = NewRunner(db).ExportJSON(context.Background(), qb, &buf)
+	if !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("Expected ErrDuplicateColumn, got: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written, got: %s", buf.String())
+	}
+}
+
+func TestExportJSONEmpty(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select * from users", []string{"id"})
+
+	var buf bytes.Buffer
+	if err := NewRunner(db).ExportJSON(context.Background(), NewQueryBuilder().Table("users"), &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if buf.String() != "[]" {
+		t.Errorf("Expected empty JSON array, got: %s", buf.String())
+	}
+}
+
+func TestExportRunnerOptions(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select id, mask(email) as email from users limit 10",
+		[]string{"id", "email"},
+		[]driver.Value{int64(1), "j***@example.com"},
+	)
+
+	var buf bytes.Buffer
+	runner := NewRunner(db, MaxRows(10), WithColumnRewrite(supportRole))
+	if err := runner.ExportCSV(context.Background(), NewQueryBuilder().Table("users").Select("id", "email"), &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "1,j***@example.com\n" {
+		t.Errorf("Expected the masked, capped export, got: %q", buf.String())
+	}
+
+	err := NewRunner(db, RejectWrites()).ExportJSON(context.Background(), NewQueryBuilder().Table("users").Delete(), &buf)
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got: %v", err)
+	}
+}
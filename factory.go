@@ -0,0 +1,133 @@
+package query
+
+import "slices"
+
+// Factory creates builders sharing one configuration - dialect, table
+// prefix, hooks, scopes and a Runner - so it is set up once and injected
+// where queries are built instead of repeated at every call site. A
+// Factory is immutable after NewFactory and safe for concurrent use.
+type Factory struct {
+	dialect    Dialect
+	dialectSet bool
+	style      ParameterStyle
+	styleSet   bool
+	prefix     string
+	hooks      []func(q *QueryBuilder)
+	buildHooks []BuildHook
+	scopes     []func(q *QueryBuilder)
+	runner     *Runner
+}
+
+// FactoryOption configures a Factory
+type FactoryOption func(*Factory)
+
+// NewFactory returns a Factory configured by opts. Settings not given
+// fall back to the package defaults, see SetDefaultDialect.
+func NewFactory(opts ...FactoryOption) *Factory {
+	f := &Factory{}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// WithDialect sets the dialect, and its placeholder style, of the
+// Factory's builders
+func WithDialect(dialect Dialect) FactoryOption {
+	return func(f *Factory) {
+		f.dialect, f.dialectSet = dialect, true
+	}
+}
+
+// WithParameterStyle sets the placeholder style of the Factory's builders,
+// overriding the one implied by WithDialect
+func WithParameterStyle(style ParameterStyle) FactoryOption {
+	return func(f *Factory) {
+		f.style, f.styleSet = style, true
+	}
+}
+
+// WithTablePrefix prepends prefix to the table passed to Factory.Table,
+// e.g. a schema ("billing.") or a per-tenant prefix. Joined tables are
+// written as given.
+func WithTablePrefix(prefix string) FactoryOption {
+	return func(f *Factory) {
+		f.prefix = prefix
+	}
+}
+
+// WithHook runs fn on every builder the Factory creates, after the rest of
+// its configuration - e.g. to set QuoteStyle, TimeZone or
+// StrictIdentifiers
+func WithHook(fn func(q *QueryBuilder)) FactoryOption {
+	return func(f *Factory) {
+		f.hooks = append(f.hooks, fn)
+	}
+}
+
+// WithBuildHook runs hook on every builder the Factory creates each time
+// it is built, with the build context - e.g. to scope by the tenant in
+// ctx. See BuildHook.
+func WithBuildHook(hook BuildHook) FactoryOption {
+	return func(f *Factory) {
+		f.buildHooks = append(f.buildHooks, hook)
+	}
+}
+
+// WithScope adds the conditions fn adds, as one group, to every builder
+// of the Factory once it has a table, whatever the table - e.g. a tenant
+// filter. Like ConfigureTable's base filters they come first, with the
+// builder's own conditions grouped behind them, and Unscoped removes them;
+// calling Table again reapplies them.
+func WithScope(fn func(q *QueryBuilder)) FactoryOption {
+	return func(f *Factory) {
+		f.scopes = append(f.scopes, fn)
+	}
+}
+
+// WithRunner attaches the Runner returned by Factory.Runner, so one
+// injected Factory can both build and run queries
+func WithRunner(r *Runner) FactoryOption {
+	return func(f *Factory) {
+		f.runner = r
+	}
+}
+
+// New returns a configured builder without a table
+func (f *Factory) New() *QueryBuilder {
+	b := f.configure()
+	f.runHooks(b)
+	return b
+}
+
+// Table returns a configured builder for the prefixed table, with the
+// Factory's scopes applied
+func (f *Factory) Table(table string) *QueryBuilder {
+	b := f.configure().Table(f.prefix + table)
+	f.runHooks(b)
+	return b
+}
+
+// Runner returns the Runner set with WithRunner, or nil
+func (f *Factory) Runner() *Runner {
+	return f.runner
+}
+
+func (f *Factory) configure() *QueryBuilder {
+	b := NewQueryBuilder()
+	if f.dialectSet {
+		b.Dialect(f.dialect)
+	}
+	if f.styleSet {
+		b.ParameterPlaceholder(f.style)
+	}
+	b.buildHooks = slices.Clone(f.buildHooks)
+	b.scopes = f.scopes
+	return b
+}
+
+func (f *Factory) runHooks(b *QueryBuilder) {
+	for _, hook := range f.hooks {
+		hook(b)
+	}
+}
@@ -0,0 +1,93 @@
+package query
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFactory(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.OnExec("delete from app_sessions where tenant_id = ? and expired = ?", 2)
+	runner := NewRunner(db)
+
+	factory := NewFactory(
+		WithDialect(MySQL),
+		WithTablePrefix("app_"),
+		WithScope(func(q *QueryBuilder) { q.Where("tenant_id", "=", 7) }),
+		WithHook(func(q *QueryBuilder) { q.StrictIdentifiers() }),
+		WithRunner(runner),
+	)
+
+	query := factory.Table("users").Select("id", "name").Where("active", "=", true).Build()
+	expectedSQL := "select id, name from app_users where tenant_id = ? and active = ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[0] != 7 || query.Params[1] != true {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+
+	query = factory.Table("users").Unscoped().Build()
+	expectedSQL = "select * from app_users"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if _, err := factory.Table("users;").TryBuild(); err == nil {
+		t.Error("Expected the StrictIdentifiers hook to reject the table")
+	}
+
+	result, err := factory.Runner().Exec(context.Background(), factory.Table("sessions").Delete().Where("expired", "=", true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n != 2 {
+		t.Errorf("Expected 2 rows affected, got %d", n)
+	}
+}
+
+func TestFactoryDefaults(t *testing.T) {
+	factory := NewFactory(WithParameterStyle(QuestionMark))
+
+	query := factory.New().Table("users").Where("id", "=", 1).Build()
+	expectedSQL := "select * from users where id = ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if factory.Runner() != nil {
+		t.Error("Expected no Runner")
+	}
+}
+
+func TestFactoryScopesSurviveTable(t *testing.T) {
+	configureEvents(t)
+	factory := NewFactory(WithScope(func(q *QueryBuilder) { q.Where("tenant_id", "=", 7) }))
+
+	query := factory.New().Table("a").Table("b").Where("id", "=", 1).Build()
+	expectedSQL := "select * from b where tenant_id = $1 and id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = factory.Table("users").Table("events").Build()
+	expectedSQL = "select * from events where tenant_id = $1 and deleted_at is null order by created_at desc"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestFactoryScopeOrWhere(t *testing.T) {
+	factory := NewFactory(WithScope(func(q *QueryBuilder) { q.Where("tenant_id", "=", 7) }))
+
+	query := factory.Table("orders").Where("status", "=", "a").OrWhere("status", "=", "b").Build()
+	expectedSQL := "select * from orders where tenant_id = $1 and (status = $2 or status = $3)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = factory.Table("orders").Set("status", "c").Where("status", "=", "a").OrWhere("status", "=", "b").Build()
+	expectedSQL = "update orders set status = $1 where tenant_id = $2 and (status = $3 or status = $4)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
@@ -0,0 +1,236 @@
+package query
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type fanOutOptions struct {
+	order []mergeKey
+	limit int
+}
+
+// FanOutOption configures how FanOut merges shard results
+type FanOutOption func(*fanOutOptions)
+
+// MergeOrder sorts the merged rows by comma-separated result columns, each
+// descending with a leading "-": "-created_at,id". It replaces the merge on
+// the builder's own OrderBy, for queries whose shards return unsorted rows.
+func MergeOrder(order string) FanOutOption {
+	return func(o *fanOutOptions) {
+		for _, field := range strings.Split(order, ",") {
+			field = strings.TrimSpace(field)
+			desc := strings.HasPrefix(field, "-")
+			key := mergeKey{column: strings.TrimLeft(field, "+-"), desc: desc, nullsFirst: !desc}
+			if key.column != "" {
+				o.order = append(o.order, key)
+			}
+		}
+	}
+}
+
+// MergeLimit keeps the first n merged rows, overriding the builder's Limit
+func MergeLimit(n int) FanOutOption {
+	return func(o *fanOutOptions) {
+		o.limit = n
+	}
+}
+
+// FanOut builds qb once and runs it concurrently on every db - shards or
+// regions holding the same schema - and merges the rows into one result,
+// for scatter-gather reads. It fails if any db fails or returns different
+// columns.
+//
+// A *QueryBuilder's OrderBy, Limit and Offset apply to the merged result:
+// each shard returns its first limit+offset rows in order, these sorted
+// streams are k-way merged, and the offset is skipped afterwards, so
+// pages span shards correctly. Other builders' rows are concatenated in
+// the order of dbs. MaxRows caps the merged rows, not each shard's.
+func (r *Runner) FanOut(ctx context.Context, dbs []*sql.DB, qb Builder, opts ...FanOutOption) (PipelineResult, error) {
+	var options fanOutOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	resort := len(options.order) > 0
+	skip := 0
+	b, isBuilder := qb.(*QueryBuilder)
+	if isBuilder {
+		if !resort {
+			keys, err := b.mergeKeys()
+			if err != nil {
+				return PipelineResult{}, err
+			}
+			options.order = keys
+		}
+		if options.limit == 0 {
+			options.limit = b.limit
+		}
+	}
+	if r.overCap(options.limit) {
+		options.limit = r.maxRows
+		if isBuilder && b.limit == 0 && !resort && len(options.order) > 0 {
+			// sorted shards need no more rows than the merged page
+			b = b.Clone()
+			b.limit = options.limit
+			qb = b
+		}
+	}
+	if isBuilder && b.offset > 0 {
+		skip = b.offset
+		b = b.Clone()
+		if b.limit > 0 {
+			b.limit += b.offset
+		}
+		b.offset = 0
+		qb = b
+	}
+
+	// MaxRows caps the merged rows above, not each shard's
+	shards := *r
+	shards.maxRows = 0
+	q, err := shards.prepare(ctx, qb)
+	if err != nil {
+		return PipelineResult{}, err
+	}
+
+	results := make([]PipelineResult, len(dbs))
+	errs := make([]error, len(dbs))
+	var wg sync.WaitGroup
+	for i, db := range dbs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rows, err := db.QueryContext(ctx, q.SQL, q.Params...)
+			if err != nil {
+				errs[i] = fmt.Errorf("query: fan-out db %d: %w", i, err)
+				return
+			}
+			defer rows.Close()
+			if results[i], err = readResult(rows); err == nil {
+				err = rows.Err()
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("query: fan-out db %d: %w", i, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return PipelineResult{}, err
+	}
+
+	var merged PipelineResult
+	streams := make([][][]interface{}, len(results))
+	for i, result := range results {
+		if i == 0 {
+			merged.Columns = result.Columns
+		} else if !slices.Equal(result.Columns, merged.Columns) {
+			return PipelineResult{}, fmt.Errorf("query: fan-out db %d returned columns %v, want %v", i, result.Columns, merged.Columns)
+		}
+		streams[i] = result.Rows
+	}
+
+	indexes := make([]int, len(options.order))
+	for k, key := range options.order {
+		if indexes[k] = slices.Index(merged.Columns, key.column); indexes[k] < 0 {
+			return PipelineResult{}, fmt.Errorf("query: merge order column %q is not in the result", key.column)
+		}
+	}
+	compare := func(a, b []interface{}) int {
+		for k, key := range options.order {
+			if c := key.compare(a[indexes[k]], b[indexes[k]]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+	switch {
+	case resort:
+		merged.Rows = slices.Concat(streams...)
+		slices.SortStableFunc(merged.Rows, compare)
+	case len(options.order) > 0:
+		n := 0
+		if options.limit > 0 {
+			n = skip + options.limit
+		}
+		merged.Rows = mergeSorted(streams, compare, n)
+	default:
+		merged.Rows = slices.Concat(streams...)
+	}
+
+	merged.Rows = merged.Rows[min(skip, len(merged.Rows)):]
+	if options.limit > 0 && len(merged.Rows) > options.limit {
+		merged.Rows = merged.Rows[:options.limit]
+	}
+	return merged, nil
+}
+
+// compareValues orders two scanned values: nulls first, then numbers,
+// strings, bytes, times and bools by value. Bytes holding numeric text,
+// as drivers scan numeric columns, compare as numbers. Values of
+// different kinds compare by their formatted text.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return cmp.Compare(x, y)
+		}
+	}
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case []byte:
+		if y, ok := b.([]byte); ok {
+			if xn, ok := new(big.Rat).SetString(string(x)); ok {
+				if yn, ok := new(big.Rat).SetString(string(y)); ok {
+					return xn.Cmp(yn)
+				}
+			}
+			return bytes.Compare(x, y)
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y)
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			default:
+				return 1
+			}
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
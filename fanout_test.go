@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestFanOut(t *testing.T) {
+	query := "select id, total from orders order by total desc limit 2"
+	eu, euFake := newFakeDB(t)
+	euFake.On(query, []string{"id", "total"}, []driver.Value{int64(1), int64(90)}, []driver.Value{int64(2), int64(40)})
+	us, usFake := newFakeDB(t)
+	usFake.On(query, []string{"id", "total"}, []driver.Value{int64(7), int64(70)}, []driver.Value{int64(8), int64(60)})
+
+	runner := NewRunner(eu)
+	qb := NewQueryBuilder().Table("orders").Select("id", "total").OrderBy("total desc").Limit(2)
+
+	result, err := runner.FanOut(context.Background(), []*sql.DB{eu, us}, qb, MergeOrder("-total"), MergeLimit(3))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedIDs := []int64{1, 7, 8}
+	if len(result.Rows) != len(expectedIDs) {
+		t.Fatalf("Expected %d rows, got: %v", len(expectedIDs), result.Rows)
+	}
+	for i, row := range result.Rows {
+		if row[0] != expectedIDs[i] {
+			t.Errorf("Expected id %d at %d, got: %v", expectedIDs[i], i, row[0])
+		}
+	}
+
+	result, err = runner.FanOut(context.Background(), []*sql.DB{eu, us}, qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Rows) != 2 || result.Rows[0][0] != int64(1) || result.Rows[1][0] != int64(7) {
+		t.Errorf("Expected the builder's order and limit across shards, got: %v", result.Rows)
+	}
+}
+
+func TestFanOutErrors(t *testing.T) {
+	query := "select * from orders"
+	a, aFake := newFakeDB(t)
+	aFake.On(query, []string{"id"})
+	b, bFake := newFakeDB(t)
+	bFake.On(query, []string{"id", "total"})
+	c, _ := newFakeDB(t)
+
+	runner := NewRunner(a)
+	qb := NewQueryBuilder().Table("orders")
+	if _, err := runner.FanOut(context.Background(), []*sql.DB{a, b}, qb); err == nil {
+		t.Error("Expected an error for mismatched columns")
+	}
+	if _, err := runner.FanOut(context.Background(), []*sql.DB{a, c}, qb); err == nil {
+		t.Error("Expected an error when a db fails")
+	}
+	if _, err := runner.FanOut(context.Background(), []*sql.DB{a}, qb, MergeOrder("missing")); err == nil {
+		t.Error("Expected an error for an unknown merge column")
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	if compareValues(nil, int64(1)) >= 0 || compareValues(int64(2), 1.5) <= 0 || compareValues("a", "b") >= 0 || compareValues(false, true) >= 0 {
+		t.Error("Unexpected ordering")
+	}
+}
+
+func TestFanOutDecimals(t *testing.T) {
+	query := "select id, total from orders order by total desc limit 2"
+	eu, euFake := newFakeDB(t)
+	euFake.On(query, []string{"id", "total"}, []driver.Value{int64(1), []byte("100.00")}, []driver.Value{int64(2), []byte("9.50")})
+	us, usFake := newFakeDB(t)
+	usFake.On(query, []string{"id", "total"}, []driver.Value{int64(7), []byte("99.00")}, []driver.Value{int64(8), []byte("10.00")})
+
+	qb := NewQueryBuilder().Table("orders").Select("id", "total").OrderBy("total desc").Limit(2)
+	result, err := NewRunner(eu).FanOut(context.Background(), []*sql.DB{eu, us}, qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Rows) != 2 || result.Rows[0][0] != int64(1) || result.Rows[1][0] != int64(7) {
+		t.Errorf("Expected 100.00 before 99.00, got: %v", result.Rows)
+	}
+}
@@ -0,0 +1,97 @@
+package query
+
+import "fmt"
+
+// Feature is SQL syntax that only some databases accept, see Supports
+type Feature int
+
+const (
+	Returning    Feature = iota + 1 // insert/update/delete ... returning
+	CTE                             // with ... as (...)
+	RecursiveCTE                    // with recursive
+	Upsert                          // on conflict / on duplicate key update
+	Window                          // window functions, ... over (...)
+	Qualify                         // qualify, filtering on window functions
+	RowLocking                      // for update / for share
+	Joins
+	Offset
+	Having
+	SetOperations // union, intersect, except
+	Partitions    // partition (p1, p2) selection
+	Arrays        // array parameters and unnest
+	Sequences     // nextval
+)
+
+var featureNames = map[Feature]string{
+	Returning:     "returning",
+	CTE:           "common table expressions",
+	RecursiveCTE:  "recursive common table expressions",
+	Upsert:        "upserts",
+	Window:        "window functions",
+	Qualify:       "qualify",
+	RowLocking:    "row locking",
+	Joins:         "joins",
+	Offset:        "offset",
+	Having:        "having",
+	SetOperations: "set operations",
+	Partitions:    "partition selection",
+	Arrays:        "arrays",
+	Sequences:     "sequences",
+}
+
+func (f Feature) String() string {
+	if name, ok := featureNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("feature(%d)", int(f))
+}
+
+// missingFeatures lists the features each dialect lacks
+var missingFeatures = map[Dialect][]Feature{
+	Postgres: {Qualify, Partitions},
+	MySQL:    {Returning, Qualify, Arrays, Sequences},
+	DuckDB:   {RowLocking, Partitions, Arrays},
+	CQL: {Returning, CTE, RecursiveCTE, Upsert, Window, Qualify, RowLocking,
+		Joins, Offset, Having, SetOperations, Partitions, Arrays, Sequences},
+}
+
+// Supports reports whether the database accepts f. DefaultDialect supports
+// nothing, since its database is only known from a builder's placeholder
+// style; use GetDialect on the builder instead.
+func (d Dialect) Supports(f Feature) bool {
+	missing, ok := missingFeatures[d]
+	if !ok {
+		return false
+	}
+	for _, feature := range missing {
+		if feature == f {
+			return false
+		}
+	}
+	return f > 0 && f <= Sequences
+}
+
+// UnsupportedFeatureError is returned by TryBuild when a query needs a
+// Feature its dialect lacks, instead of SQL the database would reject. It
+// matches ErrUnsupportedFeature with errors.Is.
+type UnsupportedFeatureError struct {
+	Feature Feature
+	Dialect Dialect
+}
+
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%s: %s on %s", ErrUnsupportedFeature, e.Feature, e.Dialect)
+}
+
+func (e *UnsupportedFeatureError) Unwrap() error {
+	return ErrUnsupportedFeature
+}
+
+// require returns an UnsupportedFeatureError unless the target database
+// supports f
+func (b *QueryBuilder) require(f Feature) error {
+	if d := b.target(); !d.Supports(f) {
+		return &UnsupportedFeatureError{Feature: f, Dialect: d}
+	}
+	return nil
+}
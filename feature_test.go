@@ -0,0 +1,55 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		feature  Feature
+		expected bool
+	}{
+		{Postgres, Returning, true},
+		{MySQL, Returning, false},
+		{MySQL, CTE, true},
+		{DuckDB, Qualify, true},
+		{Postgres, Qualify, false},
+		{CQL, Joins, false},
+		{CQL, Window, false},
+		{DefaultDialect, Joins, false},
+		{Postgres, Feature(0), false},
+	}
+	for _, test := range tests {
+		if got := test.dialect.Supports(test.feature); got != test.expected {
+			t.Errorf("Expected %s.Supports(%s) = %v, got: %v", test.dialect, test.feature, test.expected, got)
+		}
+	}
+	if NewQueryBuilder().ParameterPlaceholder(QuestionMark).GetDialect() != MySQL {
+		t.Error("Expected GetDialect to resolve MySQL from the placeholder style")
+	}
+}
+
+func TestUnsupportedFeatureError(t *testing.T) {
+	_, err := NewQueryBuilder().Table("users").Where("id", "=", 1).ForUpdate().Dialect(DuckDB).TryBuild()
+	var unsupported *UnsupportedFeatureError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("Expected an UnsupportedFeatureError, got: %v", err)
+	}
+	if unsupported.Feature != RowLocking || unsupported.Dialect != DuckDB {
+		t.Errorf("Expected row locking on duckdb, got: %v", unsupported)
+	}
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Error("Expected the error to match ErrUnsupportedFeature")
+	}
+	expected := "feature not supported by this database: row locking on duckdb"
+	if err.Error() != expected {
+		t.Errorf("Expected error: %s, got: %s", expected, err)
+	}
+
+	_, err = NewQueryBuilder().Table("events").Dialect(CQL).Join("users", "users.id = events.user_id").TryBuild()
+	if !errors.As(err, &unsupported) || unsupported.Feature != Joins {
+		t.Errorf("Expected joins to be unsupported on cql, got: %v", err)
+	}
+}
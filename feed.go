@@ -0,0 +1,219 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidFeed is returned by Feed.TryBuild when the branches cannot be
+// combined
+var ErrInvalidFeed = errors.New("invalid union feed")
+
+// Feed combines selects from several tables into one UNION ALL query, the
+// classic activity feed. Build it with UnionFeed.
+type Feed struct {
+	branches []*QueryBuilder
+	source   string
+	order    string
+	limit    int
+	offset   int
+}
+
+var _ Builder = (*Feed)(nil)
+
+// UnionFeed combines the builders into one feed. Their select lists are
+// aligned by column name, padding with NULL where a branch lacks a column,
+// and each row is tagged with its branch's table in a "source" column.
+// Columns must be plain or aliased ("published_at as created_at"), and
+// expressions must end in "as name". The first builder decides the
+// placeholder style and dialect.
+func UnionFeed(builders ...*QueryBuilder) *Feed {
+	return &Feed{branches: builders, source: "source"}
+}
+
+// Source renames the column tagging each row with its branch's table
+func (f *Feed) Source(column string) *Feed {
+	f.source = column
+	return f
+}
+
+// OrderBy orders the combined rows by the aligned column names
+func (f *Feed) OrderBy(order string) *Feed {
+	f.order = order
+	return f
+}
+
+// Limit limits the combined rows. With OrderBy set, each branch without
+// its own ordering or limit is also limited to Offset + Limit rows, so the
+// database never sorts more than one page from each table.
+func (f *Feed) Limit(limit int) *Feed {
+	f.limit = limit
+	return f
+}
+
+// Offset skips the first combined rows
+func (f *Feed) Offset(offset int) *Feed {
+	f.offset = offset
+	return f
+}
+
+// Build generates the SQL and parameters, or an empty Query if the
+// branches are invalid (see TryBuild)
+func (f *Feed) Build() Query {
+	query, err := f.TryBuild()
+	if err != nil {
+		return Query{}
+	}
+	return query
+}
+
+type feedColumn struct {
+	name   string
+	column string // plain select column, or
+	expr   *Expr  // expression ending in "as name"
+}
+
+// TryBuild validates every branch and generates the SQL and parameters
+func (f *Feed) TryBuild() (Query, error) {
+	if len(f.branches) == 0 {
+		return Query{}, fmt.Errorf("%w: no branches", ErrInvalidFeed)
+	}
+	branches, err := hookBranches(f.branches)
+	if err != nil {
+		return Query{}, err
+	}
+
+	var names []string
+	branchColumns := make([]map[string]feedColumn, len(branches))
+	for i, branch := range branches {
+		if branch.queryType != SelectQuery || branch.exists {
+			return Query{}, fmt.Errorf("%w: branch %d is not a plain select", ErrInvalidFeed, i)
+		}
+		if err := branch.validate(); err != nil {
+			return Query{}, err
+		}
+		columns, err := branch.feedColumns()
+		if err != nil {
+			return Query{}, err
+		}
+		branchColumns[i] = map[string]feedColumn{}
+		for _, column := range columns {
+			if _, ok := branchColumns[i][column.name]; ok {
+				return Query{}, fmt.Errorf("%w: %s selected twice from %s", ErrInvalidFeed, column.name, branch.table)
+			}
+			branchColumns[i][column.name] = column
+			if !slices.Contains(names, column.name) {
+				names = append(names, column.name)
+			}
+		}
+	}
+
+	first := branches[0]
+	w := first.newWriter()
+	for i, branch := range branches {
+		if i > 0 {
+			w.write(" union all ")
+		}
+		if f.order != "" && f.limit > 0 && branch.order == "" && len(branch.orderExprs) == 0 && branch.limit == 0 && branch.offset == 0 {
+			branch = branch.Clone()
+			branch.order = f.order
+			branch.limit = f.offset + f.limit
+		}
+
+		w.types, w.casts = branch.columnTypes(), branch.castsParams()
+		w.write("(select ")
+		for _, name := range names {
+			column, ok := branchColumns[i][name]
+			switch {
+			case !ok:
+				w.write("null as ")
+				w.ident(name)
+			case column.expr != nil:
+				w.expr(*column.expr)
+			default:
+				w.column(column.column)
+			}
+			w.write(", ")
+		}
+		w.raw(quoteString(strings.Fields(branch.table)[0]))
+		w.write(" as ")
+		w.ident(f.source)
+		branch.writeSelectFrom(&w)
+		w.write(")")
+	}
+
+	if f.order != "" {
+		w.write(" order by ")
+		w.orderBy(f.order)
+	}
+	if f.limit > 0 {
+		w.write(" limit ")
+		w.writeInt(f.limit)
+	}
+	if f.offset > 0 {
+		w.write(" offset ")
+		w.writeInt(f.offset)
+	}
+
+	return first.paramSettings().finishParams(w.finish())
+}
+
+// feedColumns returns the select list of a feed branch with the name each
+// column appears under. Column rewrites apply as in a plain select: a
+// dropped column is padded with NULL like one the branch lacks.
+func (b *QueryBuilder) feedColumns() ([]feedColumn, error) {
+	var columns []feedColumn
+	sources := b.selectSources()
+	for _, column := range b.columns {
+		if column == "*" || strings.HasSuffix(column, ".*") {
+			return nil, fmt.Errorf("%w: %s selects %s; list the columns to align", ErrInvalidFeed, b.table, column)
+		}
+		name := columnAlias(column)
+		replacement, keep := b.rewriteColumn(b.columnTable(sources, columnName(column)), column)
+		switch {
+		case !keep:
+		case replacement.SQL != "":
+			columns = append(columns, feedColumn{name: name, expr: aliasedExpr(replacement, name)})
+		default:
+			columns = append(columns, feedColumn{name: name, column: column})
+		}
+	}
+	for i := range b.selectExprs {
+		expr := &b.selectExprs[i]
+		name := exprAlias(expr.SQL)
+		if name == "" {
+			return nil, fmt.Errorf("%w: %q from %s needs an alias", ErrInvalidFeed, expr.SQL, b.table)
+		}
+		replacement, keep := b.rewriteColumn(b.table, expr.SQL)
+		switch {
+		case !keep:
+		case replacement.SQL != "":
+			columns = append(columns, feedColumn{name: name, expr: aliasedExpr(replacement, name)})
+		default:
+			columns = append(columns, feedColumn{name: name, expr: expr})
+		}
+	}
+	return columns, nil
+}
+
+// aliasedExpr selects expr under name
+func aliasedExpr(expr Expr, name string) *Expr {
+	expr.SQL += " as " + name
+	return &expr
+}
+
+// exprAlias returns the name after a trailing "as name", if any
+func exprAlias(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) < 3 || !strings.EqualFold(fields[len(fields)-2], "as") || !isIdentifier(fields[len(fields)-1]) {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// quoteString renders s as a SQL string literal
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnionFeed(t *testing.T) {
+	posts := NewQueryBuilder().
+		Table("posts").
+		Select("id", "title", "created_at").
+		Where("author_id", "=", 7)
+	comments := NewQueryBuilder().
+		Table("comments").
+		Select("id", "posted_at as created_at").
+		SelectRaw("left(body, ?) as excerpt", 80).
+		Where("author_id", "=", 7)
+
+	query := UnionFeed(posts, comments).
+		OrderBy("created_at desc").
+		Limit(50).
+		Offset(50).
+		Build()
+
+	expectedSQL := "(select id, title, created_at, null as excerpt, 'posts' as source from posts where author_id = $1 order by created_at desc limit 100)" +
+		" union all " +
+		"(select id, null as title, posted_at as created_at, left(body, $2) as excerpt, 'comments' as source from comments where author_id = $3 order by created_at desc limit 100)" +
+		" order by created_at desc limit 50 offset 50"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	expectedParams := []interface{}{7, 80, 7}
+	if len(query.Params) != len(expectedParams) {
+		t.Fatalf("Expected %d params, got: %v", len(expectedParams), query.Params)
+	}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+
+	if got := posts.Build().SQL; got != "select id, title, created_at from posts where author_id = $1" {
+		t.Errorf("Expected branches to be untouched, got: %s", got)
+	}
+}
+
+func TestUnionFeedSourceColumn(t *testing.T) {
+	query := UnionFeed(
+		NewQueryBuilder().Table("likes").Select("id"),
+		NewQueryBuilder().Table("follows f").Select("f.id"),
+	).Source("kind").Build()
+
+	expectedSQL := "(select id, 'likes' as kind from likes) union all (select f.id, 'follows' as kind from follows f)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestUnionFeedErrors(t *testing.T) {
+	feeds := []*Feed{
+		UnionFeed(),
+		UnionFeed(NewQueryBuilder().Table("posts")),
+		UnionFeed(NewQueryBuilder().Table("posts").Select("id").SelectRaw("count(*)")),
+		UnionFeed(NewQueryBuilder().Table("posts").Select("id").Delete()),
+	}
+	for _, feed := range feeds {
+		if _, err := feed.TryBuild(); !errors.Is(err, ErrInvalidFeed) {
+			t.Errorf("Expected ErrInvalidFeed, got: %v", err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidFilter is returned by TryBuild when BindFilter was given a
+// value or struct tag it cannot translate
+var ErrInvalidFilter = errors.New("invalid filter")
+
+var filterOperators = map[string]string{
+	"eq":       "=",
+	"ne":       "<>",
+	"gt":       ">",
+	"gte":      ">=",
+	"lt":       "<",
+	"lte":      "<=",
+	"like":     "like",
+	"ilike":    "ilike",
+	"contains": "ilike",
+	"in":       "in",
+}
+
+// BindFilter adds a where condition for every non-zero field of the struct
+// f tagged `filter:"column,op"`. op is one of eq (default), ne, gt, gte,
+// lt, lte, like, ilike, contains (ilike with the value wrapped in escaped
+// wildcards) or in (for slice fields). As in Search, MySQL gets like for
+// ilike and contains, so set the dialect before calling BindFilter. Nil pointers and empty slices are
+// skipped; a non-nil pointer is applied even when it points at a zero
+// value. Problems with f are reported by TryBuild as ErrInvalidFilter.
+func (b *QueryBuilder) BindFilter(f interface{}) *QueryBuilder {
+	v := reflect.ValueOf(f)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return b
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return b.fail(fmt.Errorf("%w: %T is not a struct", ErrInvalidFilter, f))
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("filter")
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		column, op, _ := strings.Cut(tag, ",")
+		if op == "" {
+			op = "eq"
+		}
+		operator, ok := filterOperators[op]
+		if column == "" || !ok {
+			return b.fail(fmt.Errorf("%w: field %s has tag %q", ErrInvalidFilter, field.Name, tag))
+		}
+		if operator == "ilike" && b.target() == MySQL {
+			operator = "like"
+		}
+
+		value := v.Field(i)
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		} else if value.IsZero() {
+			continue
+		}
+
+		switch op {
+		case "in":
+			if value.Kind() != reflect.Slice {
+				return b.fail(fmt.Errorf("%w: field %s uses in but is not a slice", ErrInvalidFilter, field.Name))
+			}
+			if value.Len() == 0 {
+				continue
+			}
+			values := make([]interface{}, value.Len())
+			for j := range values {
+				values[j] = value.Index(j).Interface()
+			}
+			b.WhereIn(column, values...)
+		case "contains":
+			b.Where(column, operator, "%"+EscapeLike(fmt.Sprint(value.Interface()))+"%")
+		default:
+			b.Where(column, operator, value.Interface())
+		}
+	}
+	return b
+}
+
+// fail records the first error raised while configuring the builder
+func (b *QueryBuilder) fail(err error) *QueryBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
@@ -0,0 +1,87 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+type userFilter struct {
+	MinAge   int      `filter:"age,gte"`
+	Statuses []string `filter:"status,in"`
+	Name     string   `filter:"name,contains"`
+	Verified *bool    `filter:"verified"`
+	TeamID   int64    `filter:"team_id"`
+	Page     int      `filter:"-"`
+	internal string
+}
+
+func TestBindFilter(t *testing.T) {
+	verified := false
+	query, err := NewQueryBuilder().
+		Table("users").
+		BindFilter(userFilter{
+			MinAge:   18,
+			Statuses: []string{"active", "invited"},
+			Name:     "jo_",
+			Verified: &verified,
+			Page:     3,
+		}).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := "select * from users where age >= $1 and status in ($2, $3) and name ilike $4 and verified = $5"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	expectedParams := []interface{}{18, "active", "invited", `%jo\_%`, false}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+}
+
+func TestBindFilterMySQL(t *testing.T) {
+	type search struct {
+		Name  string `filter:"name,contains"`
+		Email string `filter:"email,ilike"`
+	}
+	query, err := NewQueryBuilder().
+		Dialect(MySQL).
+		Table("users").
+		BindFilter(search{Name: "jo", Email: "%@example.com"}).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := "select * from users where name like ? and email like ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestBindFilterEmpty(t *testing.T) {
+	query := NewQueryBuilder().Table("users").BindFilter(&userFilter{}).Build()
+	if query.SQL != "select * from users" {
+		t.Errorf("Expected no conditions, got: %s", query.SQL)
+	}
+}
+
+func TestBindFilterErrors(t *testing.T) {
+	type badOp struct {
+		Age int `filter:"age,between"`
+	}
+	type badIn struct {
+		Status string `filter:"status,in"`
+	}
+
+	for _, f := range []interface{}{42, badOp{Age: 1}, badIn{Status: "x"}} {
+		if _, err := NewQueryBuilder().Table("users").BindFilter(f).TryBuild(); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("Expected ErrInvalidFilter for %#v, got: %v", f, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package query
+
+import "strings"
+
+// FromFunction selects from a set-returning function such as
+// generate_series, unnest or jsonb_to_recordset instead of a table. Each ?
+// in call binds the next arg; a final string arg starting with "as " names
+// the result and optionally its columns:
+//
+//	FromFunction("generate_series(?, ?)", 1, 100, "as g(n)")
+//
+// renders "from generate_series($1, $2) as g(n)". As sets the same alias.
+func (b *QueryBuilder) FromFunction(call string, args ...interface{}) *QueryBuilder {
+	args, alias := splitFunctionAlias(args)
+	expr := Raw(call, args...)
+	b.fromExpr = &expr
+	b.table = functionName(call)
+	if alias != "" {
+		b.tableAlias = alias
+	}
+	return b
+}
+
+// JoinFunction joins a set-returning function, which may take columns of
+// the tables before it as arguments. Args and the trailing "as alias(...)"
+// work as in FromFunction; an empty condition joins every row ("on true").
+//
+//	JoinFunction("jsonb_to_recordset(o.items)", "", "as i(sku text, qty int)")
+func (b *QueryBuilder) JoinFunction(call, condition string, args ...interface{}) *QueryBuilder {
+	args, alias := splitFunctionAlias(args)
+	var on interface{} = condition
+	if condition == "" {
+		on = Raw("true")
+	}
+	b.join("JOIN", functionName(call), alias, on)
+	expr := Raw(call, args...)
+	b.joinClauses[len(b.joinClauses)-1].Source = &expr
+	return b
+}
+
+// splitFunctionAlias separates a trailing "as alias" string from the
+// function args
+func splitFunctionAlias(args []interface{}) ([]interface{}, string) {
+	if len(args) == 0 {
+		return args, ""
+	}
+	last, ok := args[len(args)-1].(string)
+	if !ok || len(last) < 3 || !strings.EqualFold(last[:3], "as ") {
+		return args, ""
+	}
+	return args[:len(args)-1], strings.TrimSpace(last[3:])
+}
+
+// functionName returns the name of the function called by call
+func functionName(call string) string {
+	if i := strings.IndexByte(call, '('); i >= 0 {
+		call = call[:i]
+	}
+	return strings.TrimSpace(call)
+}
+
+// aliasName strips the column list from an alias such as g(n)
+func aliasName(alias string) string {
+	if i := strings.IndexByte(alias, '('); i >= 0 {
+		return strings.TrimSpace(alias[:i])
+	}
+	return alias
+}
@@ -0,0 +1,59 @@
+package query
+
+import "testing"
+
+func TestFromFunction(t *testing.T) {
+	query := NewQueryBuilder().
+		FromFunction("generate_series(?, ?)", 1, 100, "as g(n)").
+		Select("g.n").
+		Where("g.n", ">", 50).
+		Build()
+
+	expectedSQL := "select g.n from generate_series($1, $2) as g(n) where g.n > $3"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	expectedParams := []interface{}{1, 100, 50}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+}
+
+func TestJoinFunction(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("orders").
+		As("o").
+		Select("o.id", "i.sku", "i.qty").
+		JoinFunction("jsonb_to_recordset(o.items)", "", "as i(sku text, qty int)").
+		JoinFunction("generate_series(?, i.qty)", "", 1, "as unit").
+		Where("o.status", "=", "paid").
+		ValidateAliases().
+		StrictIdentifiers().
+		Build()
+
+	expectedSQL := "select o.id, i.sku, i.qty from orders as o" +
+		" JOIN jsonb_to_recordset(o.items) as i(sku text, qty int) on true" +
+		" JOIN generate_series($1, i.qty) as unit on true where o.status = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[0] != 1 || query.Params[1] != "paid" {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestFunctionAliasArgument(t *testing.T) {
+	query := NewQueryBuilder().
+		FromFunction("unnest(?::text[])", "{as,is}").
+		Build()
+
+	expectedSQL := "select * from unnest($1::text[])"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 1 {
+		t.Errorf("Expected the string to stay an argument, got: %v", query.Params)
+	}
+}
@@ -0,0 +1,25 @@
+package query
+
+// Point is a WGS 84 longitude/latitude pair used by the PostGIS helpers
+type Point struct {
+	Lng float64
+	Lat float64
+}
+
+// geographyPoint renders a bound point as a geography value
+const geographyPoint = "ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography"
+
+// WhereDWithin keeps rows whose column lies within meters of point
+func (b *QueryBuilder) WhereDWithin(column string, point Point, meters float64) *QueryBuilder {
+	return b.WhereRaw("ST_DWithin("+column+"::geography, "+geographyPoint+", ?)", point.Lng, point.Lat, meters)
+}
+
+// SelectDistance selects the distance in meters between column and point
+func (b *QueryBuilder) SelectDistance(column string, point Point, alias string) *QueryBuilder {
+	return b.SelectRaw("ST_Distance("+column+"::geography, "+geographyPoint+") as "+alias, point.Lng, point.Lat)
+}
+
+// OrderByDistance orders rows nearest first by their distance to point
+func (b *QueryBuilder) OrderByDistance(column string, point Point) *QueryBuilder {
+	return b.OrderByRaw("ST_Distance("+column+"::geography, "+geographyPoint+")", point.Lng, point.Lat)
+}
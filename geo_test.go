@@ -0,0 +1,34 @@
+package query
+
+import "testing"
+
+func TestGeoHelpers(t *testing.T) {
+	here := Point{Lng: 13.405, Lat: 52.52}
+
+	query := NewQueryBuilder().
+		Table("shops").
+		Select("id", "name").
+		SelectDistance("location", here, "distance").
+		Where("open", "=", true).
+		WhereDWithin("location", here, 500).
+		OrderByDistance("location", here).
+		Limit(10).
+		Build()
+
+	expectedSQL := "select id, name, ST_Distance(location::geography, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography) as distance" +
+		" from shops where open = $3 and ST_DWithin(location::geography, ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography, $6)" +
+		" order by ST_Distance(location::geography, ST_SetSRID(ST_MakePoint($7, $8), 4326)::geography) limit 10"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	expectedParams := []interface{}{13.405, 52.52, true, 13.405, 52.52, 500.0, 13.405, 52.52}
+	if len(query.Params) != len(expectedParams) {
+		t.Fatalf("Expected params: %v, got: %v", expectedParams, query.Params)
+	}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package query
+
+import "strings"
+
+// GroupBy sets the GROUP BY columns
+func (b *QueryBuilder) GroupBy(columns ...string) *QueryBuilder {
+	b.groupBy = columns
+	return b
+}
+
+// Having adds a HAVING condition on an aggregate expression such as
+// "avg(score)". Expressions are written as given, like count(*) in Select.
+func (b *QueryBuilder) Having(aggregate string, operator string, value interface{}) *QueryBuilder {
+	b.having = append(b.having, &WhereClause{
+		Column:   aggregate,
+		Operator: operator,
+		Value:    value,
+		JoinType: "and",
+	})
+	return b
+}
+
+// HavingCount adds "count(*) operator value" to HAVING
+func (b *QueryBuilder) HavingCount(operator string, value interface{}) *QueryBuilder {
+	return b.Having("count(*)", operator, value)
+}
+
+// HavingSum adds "sum(column) operator value" to HAVING
+func (b *QueryBuilder) HavingSum(column string, operator string, value interface{}) *QueryBuilder {
+	return b.Having("sum("+column+")", operator, value)
+}
+
+// HavingRaw adds a raw HAVING condition; each ? in sql binds the next arg
+func (b *QueryBuilder) HavingRaw(sql string, args ...interface{}) *QueryBuilder {
+	expr := Raw(sql, args...)
+	b.having = append(b.having, &WhereClause{
+		Expr:     &expr,
+		JoinType: "and",
+	})
+	return b
+}
+
+func (b *QueryBuilder) writeGroupBy(w *sqlWriter) {
+	if len(b.groupBy) > 0 {
+		w.write(" group by ")
+		for i, column := range b.groupBy {
+			if i > 0 {
+				w.write(", ")
+			}
+			w.ident(column)
+		}
+	}
+	if len(b.having) > 0 {
+		w.write(" having ")
+		w.conditions(b.having)
+	}
+}
+
+// aggregateArgument returns the argument of a single-argument aggregate
+// such as sum(amount), or s unchanged
+func aggregateArgument(s string) string {
+	open := strings.IndexByte(s, '(')
+	if open <= 0 || !strings.HasSuffix(s, ")") || !isIdentifier(s[:open]) {
+		return s
+	}
+	return s[open+1 : len(s)-1]
+}
@@ -0,0 +1,58 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHaving(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("orders").
+		Select("customer_id").
+		SelectRaw("sum(amount) as total").
+		Where("status", "=", "paid").
+		GroupBy("customer_id").
+		HavingCount(">", 5).
+		HavingSum("amount", ">", 1000).
+		HavingRaw("max(amount) < ?", 500).
+		OrderBy("total desc").
+		Build()
+
+	expectedSQL := "select customer_id, sum(amount) as total from orders where status = $1 group by customer_id having count(*) > $2 and sum(amount) > $3 and max(amount) < $4 order by total desc"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	expectedParams := []interface{}{"paid", 5, 1000, 500}
+	if len(query.Params) != len(expectedParams) {
+		t.Fatalf("Expected %d params, got: %v", len(expectedParams), query.Params)
+	}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+}
+
+func TestHavingStrictIdentifiers(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("orders").
+		StrictIdentifiers().
+		GroupBy("customer_id").
+		HavingSum("amount); drop table orders; --", ">", 1).
+		TryBuild()
+	if !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("orders").
+		StrictIdentifiers().
+		GroupBy("customer_id").
+		HavingCount(">", 1).
+		HavingSum("amount", ">", 1).
+		TryBuild()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
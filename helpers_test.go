@@ -0,0 +1,22 @@
+package query
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/scape-labs/query/internal/fakedb"
+)
+
+func newFakeDB(t *testing.T) (*sql.DB, *fakedb.DB) {
+	t.Helper()
+	fake := fakedb.New()
+	db := fake.Open()
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+// staticQuery is a Builder returning a fixed statement
+type staticQuery Query
+
+func (s staticQuery) Build() Query             { return Query(s) }
+func (s staticQuery) TryBuild() (Query, error) { return Query(s), nil }
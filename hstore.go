@@ -0,0 +1,69 @@
+package query
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Hstore is the Postgres hstore column type, for RegisterColumnTypes
+const Hstore ColumnType = "hstore"
+
+var plainKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WhereHstoreHasKey keeps rows whose hstore column contains key
+func (b *QueryBuilder) WhereHstoreHasKey(column, key string) *QueryBuilder {
+	return b.WhereRaw(column+" ?? ?", key)
+}
+
+// SelectHstoreValue selects the value of key in an hstore column, named
+// after the key when it is a plain identifier
+func (b *QueryBuilder) SelectHstoreValue(column, key string) *QueryBuilder {
+	sql := column + " -> ?"
+	if plainKeyPattern.MatchString(key) {
+		sql += " as " + key
+	}
+	return b.SelectRaw(sql, key)
+}
+
+// HstoreText formats a map in hstore's text format, e.g. "a"=>"1", "b"=>"2",
+// with keys sorted so equal maps give equal text
+func HstoreText(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var text strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			text.WriteString(", ")
+		}
+		writeHstoreString(&text, key)
+		text.WriteString("=>")
+		writeHstoreString(&text, m[key])
+	}
+	return text.String()
+}
+
+func writeHstoreString(text *strings.Builder, s string) {
+	text.WriteByte('"')
+	for _, c := range []byte(s) {
+		if c == '"' || c == '\\' {
+			text.WriteByte('\\')
+		}
+		text.WriteByte(c)
+	}
+	text.WriteByte('"')
+}
+
+// convertHstores replaces map[string]string params, which no driver can
+// bind, with their hstore text
+func convertHstores(params []interface{}) {
+	for i, param := range params {
+		if m, ok := param.(map[string]string); ok {
+			params[i] = HstoreText(m)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package query
+
+import "testing"
+
+func TestHstoreHelpers(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("products").
+		Select("id").
+		SelectHstoreValue("attrs", "color").
+		SelectHstoreValue("attrs", "max-size").
+		WhereHstoreHasKey("attrs", "color").
+		Build()
+
+	expectedSQL := "select id, attrs -> $1 as color, attrs -> $2 from products where attrs ? $3"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	expectedParams := []interface{}{"color", "max-size", "color"}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+}
+
+func TestHstoreText(t *testing.T) {
+	text := HstoreText(map[string]string{"size": "10", "color": `say "hi" \o/`})
+	expected := `"color"=>"say \"hi\" \\o/", "size"=>"10"`
+	if text != expected {
+		t.Errorf("Expected %s, got: %s", expected, text)
+	}
+	if text := HstoreText(nil); text != "" {
+		t.Errorf("Expected empty hstore, got: %s", text)
+	}
+}
+
+func TestHstoreParams(t *testing.T) {
+	RegisterColumnTypes("hstore_products", map[string]ColumnType{"attrs": Hstore})
+	defer RegisterColumnTypes("hstore_products", nil)
+
+	query, err := NewQueryBuilder().
+		Table("hstore_products").
+		Update(map[string]interface{}{"attrs": map[string]string{"color": "red"}}).
+		Where("id", "=", 1).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := "update hstore_products set attrs = $1::hstore where id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[0] != `"color"=>"red"` {
+		t.Errorf("Expected hstore text param, got: %#v", query.Params[0])
+	}
+}
@@ -0,0 +1,89 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrIdempotencyKey is returned by TryBuild when IdempotencyKey is used on
+// anything but a single-row insert that sets the key column
+var ErrIdempotencyKey = errors.New("invalid idempotency key")
+
+// IdempotencyKey makes a single-row insert safe to retry. column must have
+// a unique index and be one of the inserted columns; a second insert with
+// the same key value does nothing instead of failing:
+// "on conflict (column) do nothing" on Postgres and DuckDB,
+// "on duplicate key update column = column" on MySQL. Use
+// Runner.InsertIdempotent to also read back the stored row.
+func (b *QueryBuilder) IdempotencyKey(column string) *QueryBuilder {
+	b.idempotencyKey = column
+	return b
+}
+
+func (b *QueryBuilder) validateIdempotency() error {
+	if b.idempotencyKey == "" {
+		return nil
+	}
+	if b.queryType != InsertQuery {
+		return fmt.Errorf("%w: %s statement", ErrIdempotencyKey, queryTypeName(b.queryType))
+	}
+	if b.target() == CQL {
+		return fmt.Errorf("%w: idempotency keys on %s", ErrUnsupportedFeature, b.target())
+	}
+	if len(b.rows()) != 1 {
+		return fmt.Errorf("%w: %d rows, want 1", ErrIdempotencyKey, len(b.rows()))
+	}
+	if !slices.Contains(b.insertColumns, b.idempotencyKey) {
+		return fmt.Errorf("%w: %q is not an inserted column", ErrIdempotencyKey, b.idempotencyKey)
+	}
+	return nil
+}
+
+func (b *QueryBuilder) writeIdempotency(w *sqlWriter) {
+	if b.idempotencyKey == "" {
+		return
+	}
+	if b.target() == MySQL {
+		w.write(" on duplicate key update ")
+		w.ident(b.idempotencyKey)
+		w.write(" = ")
+		w.ident(b.idempotencyKey)
+		return
+	}
+	w.write(" on conflict (")
+	w.ident(b.idempotencyKey)
+	w.write(") do nothing")
+}
+
+// idempotencyLookup selects the row stored under the insert's key value
+func (b *QueryBuilder) idempotencyLookup() *QueryBuilder {
+	key := b.rows()[0][slices.Index(b.insertColumns, b.idempotencyKey)]
+
+	c := b.Clone()
+	c.queryType = SelectQuery
+	c.idempotencyKey = ""
+	c.insertColumns = nil
+	c.insertValues = nil
+	c.insertRows = nil
+	c.whereClauses = nil
+	return c.Where(b.idempotencyKey, "=", key).Limit(1)
+}
+
+// InsertIdempotent runs an insert built with IdempotencyKey, then selects
+// the row stored under its key. Retrying after a timeout or a lost
+// response is safe: the first attempt's row is returned either way.
+func (r *Runner) InsertIdempotent(ctx context.Context, qb *QueryBuilder) (*sql.Row, error) {
+	if qb.idempotencyKey == "" {
+		return nil, fmt.Errorf("%w: no key column set", ErrIdempotencyKey)
+	}
+	if err := qb.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := r.Exec(ctx, qb); err != nil {
+		return nil, err
+	}
+	return r.QueryRow(ctx, qb.idempotencyLookup())
+}
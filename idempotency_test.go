@@ -0,0 +1,81 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("payments").
+		InsertColumns("request_id", "amount").
+		Values("req-1", 500).
+		IdempotencyKey("request_id").
+		Build()
+
+	expectedSQL := "insert into payments (request_id, amount) values ($1, $2) on conflict (request_id) do nothing"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestIdempotencyKeyMySQL(t *testing.T) {
+	query := NewQueryBuilder().
+		Dialect(MySQL).
+		Table("payments").
+		InsertColumns("request_id", "amount").
+		Values("req-1", 500).
+		IdempotencyKey("request_id").
+		Build()
+
+	expectedSQL := "insert into payments (request_id, amount) values (?, ?) on duplicate key update request_id = request_id"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestIdempotencyKeyErrors(t *testing.T) {
+	builders := map[string]*QueryBuilder{
+		"not inserted": NewQueryBuilder().Table("payments").InsertColumns("amount").Values(500).IdempotencyKey("request_id"),
+		"several rows": NewQueryBuilder().Table("payments").InsertColumns("request_id").Values("a").AddRow("b").IdempotencyKey("request_id"),
+		"update":       NewQueryBuilder().Table("payments").Update(map[string]interface{}{"amount": 1}).IdempotencyKey("request_id"),
+	}
+	for name, qb := range builders {
+		if _, err := qb.TryBuild(); !errors.Is(err, ErrIdempotencyKey) {
+			t.Errorf("%s: expected ErrIdempotencyKey, got: %v", name, err)
+		}
+	}
+}
+
+func TestInsertIdempotent(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.OnExec("insert into payments (request_id, amount) values ($1, $2) on conflict (request_id) do nothing", 0)
+	fake.On("select * from payments where request_id = $1 limit 1",
+		[]string{"request_id", "amount"}, []driver.Value{"req-1", int64(500)})
+
+	qb := NewQueryBuilder().
+		Table("payments").
+		InsertColumns("request_id", "amount").
+		Values("req-1", 500).
+		IdempotencyKey("request_id")
+
+	row, err := NewRunner(db).InsertIdempotent(context.Background(), qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var id string
+	var amount int64
+	if err := row.Scan(&id, &amount); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id != "req-1" || amount != 500 {
+		t.Errorf("Unexpected row: %s %d", id, amount)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[1].Args[0] != "req-1" {
+		t.Errorf("Unexpected calls: %+v", calls)
+	}
+}
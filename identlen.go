@@ -0,0 +1,93 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrIdentifierTooLong is returned by TryBuild in strict identifier mode
+// for a name longer than the dialect allows, see MaxIdentifierLength
+var ErrIdentifierTooLong = errors.New("identifier too long")
+
+// MaxIdentifierLength returns the longest table, column or alias name, in
+// bytes, the database accepts: 63 for Postgres, which silently truncates
+// longer names, 64 for MySQL and 48 for CQL. DefaultDialect returns 63,
+// the lower of the two it may stand for; DuckDB has no limit and returns 0.
+func (d Dialect) MaxIdentifierLength() int {
+	switch d {
+	case Postgres, DefaultDialect:
+		return 63
+	case MySQL:
+		return 64
+	case CQL:
+		return 48
+	default:
+		return 0
+	}
+}
+
+// longIdentifier returns the first name of the statement longer than the
+// dialect allows, checking each part of a qualified name on its own
+func (b *QueryBuilder) longIdentifier() (string, bool) {
+	limit := b.target().MaxIdentifierLength()
+	if limit == 0 {
+		return "", false
+	}
+	for _, name := range b.identifierNames() {
+		for _, part := range strings.Split(name, ".") {
+			if len(part) > limit {
+				return part, true
+			}
+		}
+	}
+	return "", false
+}
+
+// identifierNames returns the plain names of tables, aliases and columns;
+// expressions are left out
+func (b *QueryBuilder) identifierNames() []string {
+	var names []string
+	if b.fromExpr == nil && b.fromSub == nil {
+		names = append(names, b.table)
+	}
+	names = append(names, aliasName(b.tableAlias))
+	for _, join := range b.joinClauses {
+		if join.Source == nil {
+			names = append(names, join.Table)
+		}
+		names = append(names, aliasName(join.Alias))
+	}
+	for _, column := range b.columns {
+		names = append(names, strings.Fields(column)...)
+	}
+	for _, sel := range b.selectSubs {
+		names = append(names, sel.alias)
+	}
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		names = append(names, where.Column)
+	})
+	names = append(names, b.groupBy...)
+	names = append(names, b.insertColumns...)
+	names = append(names, b.updateColumns...)
+	for _, term := range strings.Split(b.order, ",") {
+		if fields := strings.Fields(term); len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+
+	identifiers := names[:0]
+	for _, name := range names {
+		if isIdentifier(name) {
+			identifiers = append(identifiers, name)
+		}
+	}
+	return identifiers
+}
+
+func (b *QueryBuilder) validateIdentifierLength() error {
+	if name, ok := b.longIdentifier(); ok {
+		return fmt.Errorf("%w: %q is %d bytes, %s allows %d", ErrIdentifierTooLong, name, len(name), b.target(), b.target().MaxIdentifierLength())
+	}
+	return nil
+}
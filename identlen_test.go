@@ -0,0 +1,40 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIdentifierLength(t *testing.T) {
+	long := strings.Repeat("a", 64)
+
+	tests := []*QueryBuilder{
+		NewQueryBuilder().Table("users").As(long),
+		NewQueryBuilder().Table("users").Select("id as " + long),
+		NewQueryBuilder().Table("users").Where("users."+long, "=", 1),
+		NewQueryBuilder().Table("users").Select("users.id").JoinAs("orders", long, long+".user_id = users.id"),
+	}
+	for _, qb := range tests {
+		if _, err := qb.Clone().StrictIdentifiers().TryBuild(); !errors.Is(err, ErrIdentifierTooLong) {
+			t.Errorf("Expected ErrIdentifierTooLong, got: %v", err)
+		}
+		if warnings := Lint(qb); len(warnings) != 1 || warnings[0].Code != WarnLongIdentifier {
+			t.Errorf("Expected a long-identifier warning, got: %v", warnings)
+		}
+	}
+
+	// MySQL allows one byte more, DuckDB any length
+	for _, dialect := range []Dialect{MySQL, DuckDB} {
+		qb := NewQueryBuilder().Dialect(dialect).Table("users").As(long).StrictIdentifiers()
+		if _, err := qb.TryBuild(); err != nil {
+			t.Errorf("Unexpected error on %s: %v", dialect, err)
+		}
+	}
+
+	// expressions are not names
+	qb := NewQueryBuilder().Table("users").Select("coalesce(" + long + ", 0)")
+	if warnings := Lint(qb); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+}
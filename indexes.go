@@ -0,0 +1,139 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// IndexSuggestion reports a sequential scan found in a query plan and,
+// when the query filters or sorts on columns of the scanned table, an
+// index that could replace it
+type IndexSuggestion struct {
+	Table   string
+	Rows    float64  // Planner's row estimate for the scan
+	Columns []string // Equality columns, then range columns, then sort columns
+	SQL     string   // CREATE INDEX statement, empty without columns
+}
+
+func (s IndexSuggestion) String() string {
+	if s.SQL == "" {
+		return fmt.Sprintf("sequential scan on %s (~%.0f rows)", s.Table, s.Rows)
+	}
+	return fmt.Sprintf("sequential scan on %s (~%.0f rows), consider: %s", s.Table, s.Rows, s.SQL)
+}
+
+// IndexSuggestions runs Plan and returns a suggestion for every full
+// table scan estimated at minRows rows or more: "Seq Scan" nodes on
+// Postgres, access type ALL on MySQL. Suggested columns come from the
+// where and order by clauses, so they are a starting point to review, not
+// a guarantee the planner will use the index. Intended for development.
+func (b *QueryBuilder) IndexSuggestions(ctx context.Context, db Querier, minRows float64) ([]IndexSuggestion, error) {
+	plan, err := b.Plan(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []IndexSuggestion
+	var visit func(node *PlanNode)
+	visit = func(node *PlanNode) {
+		if (node.NodeType == "Seq Scan" || node.NodeType == "ALL") && node.Relation != "" && node.Rows >= minRows {
+			suggestion := IndexSuggestion{Table: node.Relation, Rows: node.Rows, Columns: b.indexColumns(node.Relation)}
+			if len(suggestion.Columns) > 0 {
+				suggestion.SQL = fmt.Sprintf("create index idx_%s_%s on %s (%s)",
+					strings.ReplaceAll(node.Relation, ".", "_"), strings.Join(suggestion.Columns, "_"),
+					node.Relation, strings.Join(suggestion.Columns, ", "))
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+		for _, child := range node.Children {
+			visit(child)
+		}
+	}
+	visit(plan)
+	return suggestions, nil
+}
+
+// indexColumns returns the where and order by columns belonging to table,
+// unqualified: equality conditions first, then ranges, then sort columns
+func (b *QueryBuilder) indexColumns(table string) []string {
+	names := []string{table}
+	if table == b.table {
+		names = append(names, b.tableAlias)
+	}
+	for _, join := range b.joinClauses {
+		if join.Table == table && join.Alias != "" {
+			names = append(names, join.Alias)
+		}
+	}
+	// Unqualified columns are attributed to the queried table
+	owned := func(column string) (string, bool) {
+		i := strings.LastIndex(column, ".")
+		if i < 0 {
+			return column, table == b.table && isIdentifier(column)
+		}
+		return column[i+1:], slices.Contains(names, column[:i]) && isIdentifier(column)
+	}
+
+	var equality, ranges, sorts []string
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		column, ok := owned(where.Column)
+		if !ok || where.Subquery != nil {
+			return
+		}
+		switch strings.ToLower(where.Operator) {
+		case "=", "in", "is":
+			equality = append(equality, column)
+		case "<", "<=", ">", ">=", "like":
+			ranges = append(ranges, column)
+		}
+	})
+	if b.order != "" {
+		for _, term := range strings.Split(b.order, ",") {
+			if fields := strings.Fields(term); len(fields) > 0 {
+				if column, ok := owned(fields[0]); ok {
+					sorts = append(sorts, column)
+				}
+			}
+		}
+	}
+
+	var columns []string
+	for _, column := range slices.Concat(equality, ranges, sorts) {
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// ReportSeqScans explains each distinct select the Runner executes, once
+// per fingerprint, and logs IndexSuggestions for full scans of at least
+// minRows rows. It adds a round trip per new query; intended for
+// development.
+func ReportSeqScans(minRows float64, logger Logger) RunnerOption {
+	return func(r *Runner) {
+		r.seqScanRows = minRows
+		r.seqScanLogger = logger
+		r.seqScanSeen = &sync.Map{}
+	}
+}
+
+func (r *Runner) reportSeqScans(ctx context.Context, b *QueryBuilder, q Query) {
+	if r.seqScanLogger == nil || b.queryType != SelectQuery {
+		return
+	}
+	if _, seen := r.seqScanSeen.LoadOrStore(q.Fingerprint(), true); seen {
+		return
+	}
+	suggestions, err := b.IndexSuggestions(ctx, r.route(b), r.seqScanRows)
+	if err != nil {
+		r.seqScanLogger.Printf("query: explain failed: %v", err)
+		return
+	}
+	for _, suggestion := range suggestions {
+		r.seqScanLogger.Printf("query: %s: %s", suggestion, q.Normalize().SQL)
+	}
+}
@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+const seqScanPlan = `[{"Plan": {"Node Type": "Sort", "Plan Rows": 5000, "Plans": [
+	{"Node Type": "Seq Scan", "Relation Name": "events", "Plan Rows": 5000}
+]}}]`
+
+func TestIndexSuggestions(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("explain (format json) select * from events as e where e.created_at > $1 and e.kind = $2 order by e.id",
+		[]string{"QUERY PLAN"}, []driver.Value{[]byte(seqScanPlan)})
+
+	qb := NewQueryBuilder().
+		Table("events").
+		As("e").
+		Where("e.created_at", ">", "2024-01-01").
+		Where("e.kind", "=", "click").
+		OrderBy("e.id")
+
+	suggestions, err := qb.IndexSuggestions(context.Background(), db, 1000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("Expected one suggestion, got: %v", suggestions)
+	}
+	expected := "create index idx_events_kind_created_at_id on events (kind, created_at, id)"
+	if suggestions[0].SQL != expected {
+		t.Errorf("Expected: %s, got: %s", expected, suggestions[0].SQL)
+	}
+
+	suggestions, err = qb.IndexSuggestions(context.Background(), db, 10000)
+	if err != nil || len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions below minRows, got: %v, %v", suggestions, err)
+	}
+}
+
+func TestReportSeqScans(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("explain (format json) select * from events where kind = $1",
+		[]string{"QUERY PLAN"}, []driver.Value{[]byte(seqScanPlan)})
+	fake.On("select * from events where kind = $1", []string{"id"})
+
+	logger := &recordingLogger{}
+	runner := NewRunner(db, ReportSeqScans(1000, logger))
+	for _, kind := range []string{"click", "view"} {
+		rows, err := runner.Query(context.Background(), NewQueryBuilder().Table("events").Where("kind", "=", kind))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rows.Close()
+	}
+
+	if len(logger.messages) != 1 {
+		t.Fatalf("Expected one report per fingerprint, got: %v", logger.messages)
+	}
+	if !strings.Contains(logger.messages[0], "create index idx_events_kind on events (kind)") {
+		t.Errorf("Unexpected report: %s", logger.messages[0])
+	}
+}
@@ -0,0 +1,192 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNotInFlight is returned by Cancel for a backend PID that is not
+// running one of the Runner's statements
+var ErrNotInFlight = errors.New("no such in-flight query")
+
+// InFlightQuery is a statement a Runner is currently running
+type InFlightQuery struct {
+	Fingerprint string
+	SQL         string
+	Started     time.Time
+
+	// Server process or connection id of the *sql.Conn or *sql.Tx the
+	// statement runs on; 0 on a *sql.DB pool, where it is not known which
+	// connection runs the statement
+	PID int64
+
+	dialect Dialect
+}
+
+// inFlightRegistry holds a Runner's running statements, see TrackInFlight
+type inFlightRegistry struct {
+	control DB
+
+	mu      sync.Mutex
+	next    uint64
+	queries map[uint64]InFlightQuery
+
+	// Backend PID of each *sql.Conn or *sql.Tx statements ran on; the pool
+	// Runner and its transaction Runners share the registry
+	pids map[DB]int64
+}
+
+// TrackInFlight makes the Runner record the statements it is running, for
+// InFlight and Cancel. A statement counts as running until the driver
+// returns its result; rows still being read are not tracked. Cancel runs
+// on control, which must be able to reach the server while the Runner's
+// connection is busy - usually the *sql.DB pool. A nil control uses the
+// Runner's own DB.
+func TrackInFlight(control DB) RunnerOption {
+	return func(r *Runner) {
+		r.inFlight = &inFlightRegistry{
+			control: control,
+			queries: map[uint64]InFlightQuery{},
+			pids:    map[DB]int64{},
+		}
+	}
+}
+
+// InFlight returns the statements running now, oldest first. It is empty
+// unless the Runner was created with TrackInFlight.
+func (r *Runner) InFlight() []InFlightQuery {
+	if r.inFlight == nil {
+		return nil
+	}
+	r.inFlight.mu.Lock()
+	queries := make([]InFlightQuery, 0, len(r.inFlight.queries))
+	for _, q := range r.inFlight.queries {
+		queries = append(queries, q)
+	}
+	r.inFlight.mu.Unlock()
+
+	slices.SortFunc(queries, func(a, b InFlightQuery) int {
+		return a.Started.Compare(b.Started)
+	})
+	return queries
+}
+
+// Cancel stops the statement running on the backend pid, one of the PIDs
+// reported by InFlight: with pg_cancel_backend on Postgres, KILL QUERY on
+// MySQL. The connection stays open; the statement fails with the
+// database's cancellation error.
+func (r *Runner) Cancel(ctx context.Context, pid int64) error {
+	if r.inFlight == nil {
+		return fmt.Errorf("%w: backend %d (TrackInFlight is not enabled)", ErrNotInFlight, pid)
+	}
+	dialect, ok := r.inFlight.lookup(pid)
+	if !ok {
+		return fmt.Errorf("%w: backend %d", ErrNotInFlight, pid)
+	}
+	control := r.inFlight.control
+	if control == nil {
+		control = r.db
+	}
+
+	switch dialect {
+	case Postgres:
+		var cancelled bool
+		if err := control.QueryRowContext(ctx, "select pg_cancel_backend($1)", pid).Scan(&cancelled); err != nil {
+			return err
+		}
+		if !cancelled {
+			return fmt.Errorf("%w: backend %d", ErrNotInFlight, pid)
+		}
+		return nil
+	case MySQL:
+		_, err := control.ExecContext(ctx, "kill query "+strconv.FormatInt(pid, 10))
+		return err
+	default:
+		return fmt.Errorf("%w: cancel on %s", ErrUnsupportedFeature, dialect)
+	}
+}
+
+// track records q as running until the returned func is called
+func (r *Runner) track(ctx context.Context, qb Builder, q Query) func() {
+	if r.inFlight == nil {
+		return func() {}
+	}
+	entry := InFlightQuery{
+		Fingerprint: q.Fingerprint(),
+		SQL:         q.SQL,
+		Started:     time.Now(),
+	}
+	if b, ok := qb.(*QueryBuilder); ok {
+		entry.dialect = b.target()
+		entry.PID = r.inFlight.backendPID(ctx, r.route(qb), entry.dialect)
+	}
+	r.inFlight.mu.Lock()
+	r.inFlight.next++
+	id := r.inFlight.next
+	r.inFlight.queries[id] = entry
+	r.inFlight.mu.Unlock()
+
+	return func() {
+		r.inFlight.mu.Lock()
+		delete(r.inFlight.queries, id)
+		r.inFlight.mu.Unlock()
+	}
+}
+
+// backendPID returns the server's id for the connection of db, asked once
+// per *sql.Conn or *sql.Tx; a *sql.DB pool has no fixed connection
+func (reg *inFlightRegistry) backendPID(ctx context.Context, db DB, dialect Dialect) int64 {
+	switch db.(type) {
+	case *sql.Conn, *sql.Tx:
+	default:
+		return 0
+	}
+	reg.mu.Lock()
+	pid, ok := reg.pids[db]
+	reg.mu.Unlock()
+	if ok {
+		return pid
+	}
+
+	var query string
+	switch dialect {
+	case Postgres:
+		query = "select pg_backend_pid()"
+	case MySQL:
+		query = "select connection_id()"
+	default:
+		return 0
+	}
+	if err := db.QueryRowContext(ctx, query).Scan(&pid); err != nil {
+		return 0
+	}
+	reg.mu.Lock()
+	reg.pids[db] = pid
+	reg.mu.Unlock()
+	return pid
+}
+
+// forget drops the PID of a connection that is no longer used, such as a
+// finished transaction
+func (reg *inFlightRegistry) forget(db DB) {
+	reg.mu.Lock()
+	delete(reg.pids, db)
+	reg.mu.Unlock()
+}
+
+func (reg *inFlightRegistry) lookup(pid int64) (Dialect, bool) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	for _, q := range reg.queries {
+		if pid != 0 && q.PID == pid {
+			return q.dialect, true
+		}
+	}
+	return DefaultDialect, false
+}
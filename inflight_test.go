@@ -0,0 +1,132 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestInFlight(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select pg_backend_pid()", []string{"pg_backend_pid"}, []driver.Value{int64(4242)})
+	fake.On("select pg_cancel_backend($1)", []string{"pg_cancel_backend"}, []driver.Value{true})
+	fake.OnExec("delete from sessions where expired = $1", 3)
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	runner := NewRunner(conn, TrackInFlight(db))
+	qb := NewQueryBuilder().Table("sessions").Delete().Where("expired", "=", true)
+	q := qb.Build()
+
+	done := runner.track(ctx, qb, q)
+	inFlight := runner.InFlight()
+	if len(inFlight) != 1 {
+		t.Fatalf("Expected one in-flight query, got: %v", inFlight)
+	}
+	if inFlight[0].SQL != q.SQL || inFlight[0].Fingerprint != q.Fingerprint() || inFlight[0].PID != 4242 {
+		t.Errorf("Unexpected in-flight query: %+v", inFlight[0])
+	}
+	if inFlight[0].Started.IsZero() {
+		t.Error("Expected a start time")
+	}
+
+	if err := runner.Cancel(ctx, 4242); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := runner.Cancel(ctx, 99); !errors.Is(err, ErrNotInFlight) {
+		t.Errorf("Expected ErrNotInFlight, got: %v", err)
+	}
+
+	done()
+	if inFlight := runner.InFlight(); len(inFlight) != 0 {
+		t.Errorf("Expected no in-flight queries, got: %v", inFlight)
+	}
+
+	if _, err := runner.Exec(ctx, qb); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if inFlight := runner.InFlight(); len(inFlight) != 0 {
+		t.Errorf("Expected the finished statement to be removed, got: %v", inFlight)
+	}
+
+	var pidLookups int
+	for _, call := range fake.Calls() {
+		if call.SQL == "select pg_backend_pid()" {
+			pidLookups++
+		}
+	}
+	if pidLookups != 1 {
+		t.Errorf("Expected the backend PID to be looked up once, got %d", pidLookups)
+	}
+}
+
+func TestInFlightOnPool(t *testing.T) {
+	db, fake := newFakeDB(t)
+
+	runner := NewRunner(db)
+	if inFlight := runner.InFlight(); inFlight != nil {
+		t.Errorf("Expected nothing without TrackInFlight, got: %v", inFlight)
+	}
+	if err := runner.Cancel(context.Background(), 7); !errors.Is(err, ErrNotInFlight) {
+		t.Errorf("Expected ErrNotInFlight, got: %v", err)
+	}
+
+	runner = NewRunner(db, TrackInFlight(nil))
+	qb := NewQueryBuilder().ParameterPlaceholder(QuestionMark).Table("users")
+	done := runner.track(context.Background(), qb, qb.Build())
+	defer done()
+	if inFlight := runner.InFlight(); len(inFlight) != 1 || inFlight[0].PID != 0 {
+		t.Errorf("Expected one query without a PID on a pool, got: %v", inFlight)
+	}
+	if len(fake.Calls()) != 0 {
+		t.Errorf("Expected no PID lookup on a pool, got: %v", fake.Calls())
+	}
+}
+
+func TestInFlightPerConnection(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select pg_backend_pid()", []string{"pg_backend_pid"}, []driver.Value{int64(4242)})
+
+	ctx := context.Background()
+	runner := NewRunner(db, TrackInFlight(nil))
+	qb := NewQueryBuilder().Table("users")
+
+	for range 2 {
+		err := runner.RunInTx(ctx, TxOptions{}, func(tx *Runner) error {
+			done := tx.track(ctx, qb, qb.Build())
+			defer done()
+			if inFlight := runner.InFlight(); len(inFlight) != 1 || inFlight[0].PID != 4242 {
+				t.Errorf("Expected the transaction's PID, got: %v", inFlight)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	done := runner.track(ctx, qb, qb.Build())
+	defer done()
+	if inFlight := runner.InFlight(); len(inFlight) != 1 || inFlight[0].PID != 0 {
+		t.Errorf("Expected no PID for a pool statement after a transaction, got: %v", inFlight)
+	}
+
+	var pidLookups int
+	for _, call := range fake.Calls() {
+		if call.SQL == "select pg_backend_pid()" {
+			pidLookups++
+		}
+	}
+	if pidLookups != 2 {
+		t.Errorf("Expected the PID looked up once per transaction, got %d", pidLookups)
+	}
+	if len(runner.inFlight.pids) != 0 {
+		t.Errorf("Expected finished transactions forgotten, got: %v", runner.inFlight.pids)
+	}
+}
@@ -0,0 +1,149 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+)
+
+// InsertIDs runs a multi-row insert with "returning idColumn, keyColumn"
+// and returns the generated ids in the order the rows were added, so
+// children can be linked to freshly inserted parents:
+//
+//	ids, err := query.InsertIDs[int64](ctx, runner, posts, "id", "slug")
+//	for i, id := range ids {
+//		drafts[i].ID = id
+//	}
+//
+// Postgres does not promise RETURNING rows in VALUES order, so each id is
+// matched to its row by keyColumn, an inserted column whose values are
+// unique across the rows: a natural key or a client-generated UUID. MySQL
+// has no RETURNING and fails with an UnsupportedFeatureError. An insert
+// larger than the parameter limit is split as in Exec, and its chunks run
+// in one transaction unless the Runner is already bound to a *sql.Tx. It
+// fails unless every row comes back, so it does not combine with
+// IdempotencyKey, Upsert or insert-selects.
+func InsertIDs[K any](ctx context.Context, r *Runner, qb *QueryBuilder, idColumn, keyColumn string) ([]K, error) {
+	if err := qb.validateInsertIDs(idColumn, keyColumn); err != nil {
+		return nil, err
+	}
+	b := qb.Clone()
+	b.returnID = idColumn
+	b.returnKey = keyColumn
+	r = r.routed(b)
+
+	limit := b.target().maxParams()
+	if r.maxParamsSet {
+		limit = r.maxParams
+	}
+	chunks := b.insertChunks(limit)
+	if chunks == nil {
+		return insertChunkIDs[K](ctx, r, b)
+	}
+
+	var ids []K
+	run := func(tx *Runner) error {
+		for _, chunk := range chunks {
+			chunkIDs, err := insertChunkIDs[K](ctx, tx, chunk)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, chunkIDs...)
+		}
+		return nil
+	}
+	var err error
+	if _, ok := r.db.(*sql.Tx); ok {
+		err = run(r)
+	} else {
+		err = r.RunInTx(ctx, TxOptions{}, run)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (b *QueryBuilder) validateInsertIDs(idColumn, keyColumn string) error {
+	switch {
+	case b.queryType != InsertQuery:
+		return fmt.Errorf("query: InsertIDs needs an insert, got %s statement", queryTypeName(b.queryType))
+	case b.insertSelect != nil:
+		return fmt.Errorf("query: InsertIDs cannot map ids back to an insert-select")
+	case b.idempotencyKey != "" || len(b.upsertKey) > 0:
+		return fmt.Errorf("query: InsertIDs cannot map ids back with IdempotencyKey or Upsert")
+	case !isIdentifier(idColumn):
+		return unsafeIdentifier(idColumn)
+	case !slices.Contains(b.insertColumns, keyColumn):
+		return fmt.Errorf("query: InsertIDs key %s is not an inserted column", keyColumn)
+	}
+	if err := b.require(Returning); err != nil {
+		return err
+	}
+	key := slices.Index(b.insertColumns, keyColumn)
+	seen := make(map[string]bool, len(b.rows()))
+	for i, row := range b.rows() {
+		k := insertKey(row[key])
+		if seen[k] {
+			return fmt.Errorf("query: InsertIDs key %s repeats %s in row %d", keyColumn, k, i+1)
+		}
+		seen[k] = true
+	}
+	return nil
+}
+
+// insertChunkIDs runs one insert with its returning clause and orders the
+// ids by the rows' keys
+func insertChunkIDs[K any](ctx context.Context, r *Runner, b *QueryBuilder) ([]K, error) {
+	rows, err := r.Query(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returned := make(map[string]K, len(b.rows()))
+	for rows.Next() {
+		var id K
+		var key interface{}
+		if err := rows.Scan(&id, &key); err != nil {
+			return nil, err
+		}
+		returned[insertKey(key)] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	key := slices.Index(b.insertColumns, b.returnKey)
+	ids := make([]K, 0, len(b.rows()))
+	for i, row := range b.rows() {
+		id, ok := returned[insertKey(row[key])]
+		if !ok {
+			return nil, fmt.Errorf("query: insert returned no id for row %d (%s %v)", i+1, b.returnKey, row[key])
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// insertKey renders an inserted or returned key so the two compare equal
+func insertKey(v interface{}) string {
+	if converted, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+		v = converted
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func (b *QueryBuilder) writeReturnID(w *sqlWriter) {
+	if b.returnID != "" {
+		w.write(" returning ")
+		w.ident(b.returnID)
+		w.write(", ")
+		w.ident(b.returnKey)
+	}
+}
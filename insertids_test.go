@@ -0,0 +1,75 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestInsertIDs(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("insert into parents (name) values ($1), ($2), ($3) returning id, name", []string{"id", "name"},
+		[]driver.Value{int64(12), "c"}, []driver.Value{int64(10), "a"}, []driver.Value{int64(11), "b"})
+
+	qb := NewQueryBuilder().Table("parents").InsertColumns("name").AddRow("a").AddRow("b").AddRow("c")
+	ids, err := InsertIDs[int64](context.Background(), NewRunner(db), qb, "id", "name")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 10 || ids[2] != 12 {
+		t.Errorf("Expected ids [10 11 12], got: %v", ids)
+	}
+	if query := qb.Build(); query.SQL != "insert into parents (name) values ($1), ($2), ($3)" {
+		t.Errorf("Expected InsertIDs to leave the builder unchanged, got: %s", query.SQL)
+	}
+}
+
+func TestInsertIDsChunks(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("insert into parents (name) values ($1), ($2) returning id, name", []string{"id", "name"},
+		[]driver.Value{int64(2), []byte("b")}, []driver.Value{int64(1), []byte("a")})
+	fake.On("insert into parents (name) values ($1) returning id, name", []string{"id", "name"},
+		[]driver.Value{int64(3), []byte("c")})
+
+	qb := NewQueryBuilder().Table("parents").InsertColumns("name").AddRow("a").AddRow("b").AddRow("c")
+	ids, err := InsertIDs[int64](context.Background(), NewRunner(db, MaxParams(2)), qb, "id", "name")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf("Expected ids [1 2 3], got: %v", ids)
+	}
+	if txs := fake.Txs(); len(txs) != 1 || !txs[0].Committed {
+		t.Errorf("Expected one committed transaction, got: %+v", txs)
+	}
+}
+
+func TestInsertIDsErrors(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("insert into parents (name) values ($1), ($2) returning id, name", []string{"id", "name"}, []driver.Value{int64(1), "a"})
+	runner := NewRunner(db)
+
+	qb := NewQueryBuilder().Table("parents").InsertColumns("name").AddRow("a").AddRow("b")
+	if _, err := InsertIDs[int64](context.Background(), runner, qb, "id", "name"); err == nil {
+		t.Error("Expected an error when fewer ids come back than rows")
+	}
+
+	dup := NewQueryBuilder().Table("parents").InsertColumns("name").AddRow("a").AddRow("a")
+	if _, err := InsertIDs[int64](context.Background(), runner, dup, "id", "name"); err == nil {
+		t.Error("Expected an error for a repeated key")
+	}
+	if _, err := InsertIDs[int64](context.Background(), runner, qb, "id", "slug"); err == nil {
+		t.Error("Expected an error for a key that is not inserted")
+	}
+
+	mysql := NewQueryBuilder().Dialect(MySQL).Table("parents").InsertColumns("name").AddRow("a")
+	if _, err := InsertIDs[int64](context.Background(), runner, mysql, "id", "name"); !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature on MySQL, got: %v", err)
+	}
+
+	update := NewQueryBuilder().Table("parents").Set("name", "a")
+	if _, err := InsertIDs[int64](context.Background(), runner, update, "id", "name"); err == nil {
+		t.Error("Expected an error for an update")
+	}
+}
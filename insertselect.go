@@ -0,0 +1,46 @@
+package query
+
+import "fmt"
+
+// InsertFromSelect makes the query an "insert into table (columns...)
+// select ..." copying the rows sub selects. columns name the target
+// columns in the order sub selects them; with none, sub must select every
+// column in table order. Combine it with Upsert to deduplicate while
+// ingesting:
+//
+//	insert into dim (key, name) select key, name from staging
+//	on conflict (key) do update set name = excluded.name
+func (b *QueryBuilder) InsertFromSelect(sub *QueryBuilder, columns ...string) *QueryBuilder {
+	b.queryType = InsertQuery
+	b.insertColumns = columns
+	b.insertValues = nil
+	b.insertRows = nil
+	b.insertSelect = sub
+	return b
+}
+
+// validateInsertSelect checks the select of an InsertFromSelect
+func (b *QueryBuilder) validateInsertSelect() error {
+	if b.insertSelect == nil || b.queryType != InsertQuery {
+		return nil
+	}
+	if b.insertSelect.queryType != SelectQuery {
+		return fmt.Errorf("%w: insert source is a %s statement", ErrNotSelect, queryTypeName(b.insertSelect.queryType))
+	}
+	if len(b.upsertKey) > 0 && len(b.insertColumns) == 0 {
+		return fmt.Errorf("%w: insert from select needs its columns named", ErrInvalidUpsert)
+	}
+	if err := b.insertSelect.check(); err != nil {
+		return err
+	}
+	return b.insertSelect.validateScope(nil)
+}
+
+// writeInsertSelect writes the select of an InsertFromSelect, sharing the
+// placeholder numbering of the insert
+func (b *QueryBuilder) writeInsertSelect(w *sqlWriter) {
+	types, casts := w.types, w.casts
+	w.types, w.casts = b.insertSelect.columnTypes(), b.insertSelect.castsParams()
+	b.insertSelect.writeSelect(w)
+	w.types, w.casts = types, casts
+}
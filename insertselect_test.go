@@ -0,0 +1,93 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInsertFromSelect(t *testing.T) {
+	staging := NewQueryBuilder().
+		Table("staging").
+		Select("key", "name").
+		Where("batch", "=", 7)
+
+	query := NewQueryBuilder().
+		Table("dim").
+		InsertFromSelect(staging, "key", "name").
+		Build()
+
+	expectedSQL := "insert into dim (key, name) select key, name from staging where batch = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 1 || query.Params[0] != 7 {
+		t.Errorf("Expected params: [7], got: %v", query.Params)
+	}
+}
+
+func TestInsertFromSelectUpsert(t *testing.T) {
+	staging := NewQueryBuilder().Table("staging").Select("key", "name").Where("batch", "=", 7)
+
+	query := NewQueryBuilder().
+		Table("dim").
+		InsertFromSelect(staging, "key", "name").
+		Upsert("key").
+		Build()
+
+	expectedSQL := "insert into dim (key, name) select key, name from staging where batch = $1" +
+		" on conflict (key) do update set name = excluded.name"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().
+		Dialect(MySQL).
+		Table("dim").
+		InsertFromSelect(NewQueryBuilder().Table("staging").Select("key", "name"), "key", "name").
+		Upsert("key").
+		Build()
+
+	expectedSQL = "insert into dim (key, name) select key, name from staging on duplicate key update name = values(name)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestInsertFromSelectErrors(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("dim").
+		InsertFromSelect(NewQueryBuilder().Table("staging").Delete()).
+		TryBuild()
+	if !errors.Is(err, ErrNotSelect) {
+		t.Errorf("Expected ErrNotSelect, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("dim").
+		InsertFromSelect(NewQueryBuilder().Table("staging")).
+		Upsert("key").
+		TryBuild()
+	if !errors.Is(err, ErrInvalidUpsert) {
+		t.Errorf("Expected ErrInvalidUpsert, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("dim").
+		InsertFromSelect(NewQueryBuilder().StrictIdentifiers().Table("staging;")).
+		TryBuild()
+	if !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+}
+
+func TestInsertFromSelectClone(t *testing.T) {
+	staging := NewQueryBuilder().Table("staging").Select("key")
+	base := NewQueryBuilder().Table("dim").InsertFromSelect(staging, "key")
+	clone := base.Clone()
+	clone.insertSelect.Where("batch", "=", 1)
+
+	expectedSQL := "insert into dim (key) select key from staging"
+	if query := base.Build(); query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
@@ -0,0 +1,81 @@
+package query
+
+import "slices"
+
+// The accessors below let middleware, linters and authorization layers see
+// what a query touches before it is built. They return copies, so changing
+// the result never changes the builder.
+
+// GetType returns the statement type
+func (b *QueryBuilder) GetType() QueryType {
+	return b.queryType
+}
+
+// GetTable returns the table the query reads or writes
+func (b *QueryBuilder) GetTable() string {
+	return b.table
+}
+
+// GetAlias returns the table alias set with As, if any
+func (b *QueryBuilder) GetAlias() string {
+	return b.tableAlias
+}
+
+// GetAliases returns the declared aliases: the table's, from As or FromSub,
+// followed by those of aliased joins
+func (b *QueryBuilder) GetAliases() []string {
+	var aliases []string
+	if b.tableAlias != "" {
+		aliases = append(aliases, b.tableAlias)
+	}
+	for _, join := range b.joinClauses {
+		if join.Alias != "" {
+			aliases = append(aliases, join.Alias)
+		}
+	}
+	return aliases
+}
+
+// GetTag returns the routing tag set with Tag, if any
+func (b *QueryBuilder) GetTag() string {
+	return b.tag
+}
+
+// GetDialect returns the database the query is built for, resolving
+// DefaultDialect from the placeholder style
+func (b *QueryBuilder) GetDialect() Dialect {
+	return b.target()
+}
+
+// GetColumns returns the selected columns, excluding raw expressions
+func (b *QueryBuilder) GetColumns() []string {
+	return slices.Clone(b.columns)
+}
+
+// GetWheres returns the top-level where clauses. Grouped conditions are in
+// each clause's Group.
+func (b *QueryBuilder) GetWheres() []WhereClause {
+	wheres := make([]WhereClause, len(b.whereClauses))
+	for i, where := range cloneWheres(b.whereClauses) {
+		wheres[i] = *where
+	}
+	return wheres
+}
+
+// GetJoins returns the join clauses
+func (b *QueryBuilder) GetJoins() []JoinClause {
+	joins := make([]JoinClause, len(b.joinClauses))
+	for i, join := range b.joinClauses {
+		joins[i] = *join
+	}
+	return joins
+}
+
+// GetTables returns the query's table followed by every joined table
+func (b *QueryBuilder) GetTables() []string {
+	tables := []string{b.table}
+	for _, join := range b.joinClauses {
+		tables = append(tables, join.Table)
+	}
+	return tables
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIntrospection(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("orders").
+		As("o").
+		Select("o.id", "o.total").
+		LeftJoin("customers c", "c.id = o.customer_id").
+		Where("o.status", "=", "paid").
+		WhereGroup(func(g *QueryBuilder) {
+			g.Where("o.total", ">", 100).OrWhere("c.vip", "=", true)
+		})
+
+	if qb.GetType() != SelectQuery || qb.GetTable() != "orders" || qb.GetAlias() != "o" {
+		t.Errorf("Unexpected table info: %v %s %s", qb.GetType(), qb.GetTable(), qb.GetAlias())
+	}
+
+	if columns := qb.GetColumns(); !slices.Equal(columns, []string{"o.id", "o.total"}) {
+		t.Errorf("Unexpected columns: %v", columns)
+	}
+
+	if tables := qb.GetTables(); !slices.Equal(tables, []string{"orders", "customers c"}) {
+		t.Errorf("Unexpected tables: %v", tables)
+	}
+
+	joins := qb.GetJoins()
+	if len(joins) != 1 || joins[0].Type != "LEFT JOIN" || joins[0].Condition != "c.id = o.customer_id" {
+		t.Errorf("Unexpected joins: %+v", joins)
+	}
+
+	wheres := qb.GetWheres()
+	if len(wheres) != 2 || wheres[0].Column != "o.status" || len(wheres[1].Group) != 2 {
+		t.Fatalf("Unexpected wheres: %+v", wheres)
+	}
+}
+
+func TestIntrospectionReturnsCopies(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		Select("id").
+		Where("id", "=", 1).
+		WhereGroup(func(g *QueryBuilder) { g.Where("a", "=", 1) })
+
+	qb.GetColumns()[0] = "password"
+	wheres := qb.GetWheres()
+	wheres[0].Column = "role"
+	wheres[1].Group[0].Column = "role"
+	qb.GetJoins()
+
+	expectedSQL := "select id from users where id = $1 and (a = $2)"
+	if query := qb.Build(); query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
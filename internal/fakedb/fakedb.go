@@ -0,0 +1,222 @@
+// Package fakedb is a minimal in-memory database/sql driver returning
+// canned results keyed by SQL string. It backs the package tests and the
+// public querytest fakes.
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Call is one statement received by the driver
+type Call struct {
+	SQL  string
+	Args []interface{}
+}
+
+type result struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+// Tx is one transaction begun on the driver
+type Tx struct {
+	Isolation  driver.IsolationLevel
+	ReadOnly   bool
+	Committed  bool
+	RolledBack bool
+}
+
+// DB holds the canned results and the recorded calls of one fake database
+type DB struct {
+	mu      sync.Mutex
+	name    string
+	results map[string]result
+	calls   []Call
+	txs     []*Tx
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*DB{}
+	nextID     int
+)
+
+func init() {
+	sql.Register("fakedb", fakeDriver{})
+}
+
+// New creates an empty fake database
+func New() *DB {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	nextID++
+	f := &DB{
+		name:    fmt.Sprintf("fakedb-%d", nextID),
+		results: map[string]result{},
+	}
+	registry[f.name] = f
+	return f
+}
+
+// Open returns a *sql.DB connected to the fake
+func (f *DB) Open() *sql.DB {
+	db, err := sql.Open("fakedb", f.name)
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// On registers the rows returned for sql
+func (f *DB) On(sql string, columns []string, rows ...[]driver.Value) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[sql] = result{columns: columns, rows: rows}
+}
+
+// OnExec registers the rows affected reported when sql is executed
+func (f *DB) OnExec(sql string, affected int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[sql] = result{affected: affected}
+}
+
+// OnError registers the error returned for sql
+func (f *DB) OnError(sql string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[sql] = result{err: err}
+}
+
+// Calls returns every statement received so far, in order
+func (f *DB) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Txs returns every transaction begun so far, in order
+func (f *DB) Txs() []Tx {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	txs := make([]Tx, len(f.txs))
+	for i, t := range f.txs {
+		txs[i] = *t
+	}
+	return txs
+}
+
+func (f *DB) record(query string, args []driver.NamedValue) (result, bool) {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{SQL: query, Args: values})
+	r, ok := f.results[query]
+	return r, ok
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	f, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("fakedb: unknown database %q", name)
+	}
+	return &conn{db: f}, nil
+}
+
+type conn struct {
+	db *DB
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fakedb: prepare not supported")
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	t := &Tx{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.txs = append(c.db.txs, t)
+	return tx{db: c.db, tx: t}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	r, ok := c.db.record(query, args)
+	if !ok {
+		return nil, fmt.Errorf("fakedb: no result registered for %q", query)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &rows{result: r}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	r, ok := c.db.record(query, args)
+	if !ok {
+		return driver.RowsAffected(1), nil
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return driver.RowsAffected(r.affected), nil
+}
+
+type tx struct {
+	db *DB
+	tx *Tx
+}
+
+func (t tx) Commit() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.tx.Committed = true
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.tx.RolledBack = true
+	return nil
+}
+
+type rows struct {
+	result result
+	pos    int
+}
+
+func (r *rows) Columns() []string { return r.result.columns }
+
+func (r *rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.pos])
+	r.pos++
+	return nil
+}
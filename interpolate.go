@@ -0,0 +1,160 @@
+package query
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BoolLiterals selects how BuildInterpolated writes booleans
+type BoolLiterals int
+
+const (
+	BoolKeywords BoolLiterals = iota // true, false
+	BoolNumbers                      // 1, 0
+)
+
+var (
+	boolLiteralsMu sync.RWMutex
+	boolLiterals   = map[Dialect]BoolLiterals{MySQL: BoolNumbers}
+)
+
+// SetBoolLiterals changes how booleans are inlined for dialect. MySQL
+// defaults to BoolNumbers, since its BOOLEAN is TINYINT(1) and older
+// servers and compatibility modes know no true or false; every other
+// dialect defaults to BoolKeywords.
+func SetBoolLiterals(dialect Dialect, style BoolLiterals) {
+	boolLiteralsMu.Lock()
+	defer boolLiteralsMu.Unlock()
+	boolLiterals[dialect] = style
+}
+
+func (d Dialect) boolLiteral(v bool) string {
+	boolLiteralsMu.RLock()
+	style := boolLiterals[d]
+	boolLiteralsMu.RUnlock()
+	switch {
+	case style == BoolNumbers && v:
+		return "1"
+	case style == BoolNumbers:
+		return "0"
+	default:
+		return strconv.FormatBool(v)
+	}
+}
+
+// BuildInterpolated returns the SQL with every parameter inlined as an
+// escaped literal, for drivers and tools without placeholder support such
+// as HTTP query interfaces, some proxies, or pasting into a console to
+// EXPLAIN. Strings are single-quoted (with backslashes doubled on MySQL),
+// bytes are hex-encoded, never quoted as text (a nil slice is null), and
+// times are formatted for the dialect. Booleans are true and false, or 1
+// and 0 on MySQL (see SetBoolLiterals), and nil is null; like keywords,
+// both follow KeywordCase. Build hooks and comments apply as in TryBuild.
+// Prefer bound parameters whenever the driver supports them.
+func (b *QueryBuilder) BuildInterpolated() (string, error) {
+	// Render the hooked builder the params come from
+	b, err := b.withBuildHooks()
+	if err != nil {
+		return "", err
+	}
+	q, err := b.TryBuild()
+	if err != nil {
+		return "", err
+	}
+
+	literals := make([]string, len(q.Params))
+	for i, param := range q.Params {
+		if literals[i], err = b.target().literal(param, b.keywordCase); err != nil {
+			return "", err
+		}
+	}
+
+	w := b.newWriter()
+	w.literals = literals
+	b.render(&w)
+	b.writeComments(&w)
+	return w.finish().SQL, nil
+}
+
+// literal renders a parameter value as a SQL literal, writing keywords in
+// casing
+func (d Dialect) literal(param interface{}, casing KeywordCasing) (string, error) {
+	value, err := driver.DefaultParameterConverter.ConvertValue(param)
+	if err != nil {
+		return "", fmt.Errorf("%w: %T", ErrUnsupportedParam, param)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return casing.apply("null"), nil
+	case bool:
+		return casing.apply(d.boolLiteral(v)), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("%w: %v cannot be inlined", ErrUnsupportedParam, v)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case string:
+		return d.stringLiteral(v)
+	case []byte:
+		if v == nil {
+			return casing.apply("null"), nil
+		}
+		return d.bytesLiteral(v), nil
+	case time.Time:
+		return d.timeLiteral(v), nil
+	default:
+		return "", fmt.Errorf("%w: %T", ErrUnsupportedParam, param)
+	}
+}
+
+func (d Dialect) stringLiteral(s string) (string, error) {
+	if strings.IndexByte(s, 0) >= 0 {
+		return "", fmt.Errorf("%w: string contains a NUL byte", ErrUnsupportedParam)
+	}
+	if d == MySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return quoteString(s), nil
+}
+
+func (d Dialect) bytesLiteral(b []byte) string {
+	switch d {
+	case MySQL:
+		return "X'" + hex.EncodeToString(b) + "'"
+	case CQL:
+		return "0x" + hex.EncodeToString(b)
+	case DuckDB:
+		var s strings.Builder
+		s.WriteByte('\'')
+		for _, c := range b {
+			fmt.Fprintf(&s, `\x%02X`, c)
+		}
+		s.WriteString("'::blob")
+		return s.String()
+	default:
+		return `'\x` + hex.EncodeToString(b) + "'::bytea"
+	}
+}
+
+func (d Dialect) timeLiteral(t time.Time) string {
+	switch d {
+	case MySQL:
+		// DATETIME has no zone; the value is written in its own location
+		return "'" + t.Format("2006-01-02 15:04:05.999999") + "'"
+	case CQL:
+		return "'" + t.UTC().Format("2006-01-02T15:04:05.000Z") + "'"
+	default:
+		return "'" + t.Format("2006-01-02 15:04:05.999999-07:00") + "'::timestamptz"
+	}
+}
@@ -0,0 +1,125 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildInterpolated(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	sql, err := NewQueryBuilder().
+		Table("users").
+		Where("name", "=", "O'Brien").
+		Where("active", "=", true).
+		Where("score", ">", 9.5).
+		Where("created_at", ">", created).
+		Where("avatar", "=", []byte{0xde, 0xad}).
+		Where("team_id", "is", nil).
+		BuildInterpolated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := `select * from users where name = 'O''Brien' and active = true and score > 9.5 and created_at > '2024-03-01 09:30:00+00:00'::timestamptz and avatar = '\xdead'::bytea and team_id is null`
+	if sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+}
+
+func TestBuildInterpolatedMySQL(t *testing.T) {
+	sql, err := NewQueryBuilder().
+		Dialect(MySQL).
+		Table("files").
+		WhereRaw("path like ? and checksum = ?", `C:\temp\%`, []byte{0x01}).
+		Limit(5).
+		BuildInterpolated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := `select * from files where path like 'C:\\temp\\%' and checksum = X'01' limit 5`
+	if sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+}
+
+func TestBuildInterpolatedBoolLiterals(t *testing.T) {
+	build := func(dialect Dialect) string {
+		sql, err := NewQueryBuilder().
+			Dialect(dialect).
+			KeywordCase(Upper).
+			Table("users").
+			Where("active", "=", true).
+			Where("banned", "=", false).
+			Where("team_id", "is", nil).
+			BuildInterpolated()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return sql
+	}
+
+	expectedSQL := "SELECT * FROM users WHERE active = 1 AND banned = 0 AND team_id IS NULL"
+	if sql := build(MySQL); sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+	expectedSQL = "SELECT * FROM users WHERE active = TRUE AND banned = FALSE AND team_id IS NULL"
+	if sql := build(Postgres); sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+
+	SetBoolLiterals(Postgres, BoolNumbers)
+	defer SetBoolLiterals(Postgres, BoolKeywords)
+	expectedSQL = "SELECT * FROM users WHERE active = 1 AND banned = 0 AND team_id IS NULL"
+	if sql := build(Postgres); sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+}
+
+func TestBuildInterpolatedKeepsLiteralQuestionMarks(t *testing.T) {
+	sql, err := NewQueryBuilder().
+		Table("docs").
+		WhereRaw("attrs ?? ?", "color").
+		BuildInterpolated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sql != "select * from docs where attrs ? 'color'" {
+		t.Errorf("Unexpected SQL: %s", sql)
+	}
+}
+
+func TestBuildInterpolatedRejects(t *testing.T) {
+	for _, value := range []interface{}{"a\x00b", math.NaN()} {
+		_, err := NewQueryBuilder().Table("t").Where("x", "=", value).BuildInterpolated()
+		if !errors.Is(err, ErrUnsupportedParam) {
+			t.Errorf("Expected ErrUnsupportedParam for %q, got: %v", value, err)
+		}
+	}
+}
+
+func TestBuildInterpolatedHooks(t *testing.T) {
+	calls := 0
+	remove := OnBuild(func(ctx context.Context, q *QueryBuilder) error {
+		calls++
+		q.Where("tenant_id", "=", 42).Comment("tenant=42")
+		return nil
+	})
+	defer remove()
+
+	sql, err := NewQueryBuilder().Table("users").Where("id", "=", 1).BuildInterpolated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := "select * from users where id = 1 and tenant_id = 42 /* tenant=42 */"
+	if sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the hook to run once, got %d", calls)
+	}
+}
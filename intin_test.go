@@ -0,0 +1,42 @@
+package query
+
+import "testing"
+
+func TestWhereIntIn(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		Where("active", "=", true).
+		WhereIntIn("id", []int64{3, -1, 42}).
+		Build()
+
+	expectedSQL := "select * from users where active = $1 and id in (3, -1, 42)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 1 {
+		t.Errorf("Expected only the active param, got: %v", query.Params)
+	}
+}
+
+func TestWhereIntInEmpty(t *testing.T) {
+	query := NewQueryBuilder().Table("users").WhereIntIn("id", nil).Build()
+
+	expectedSQL := "select * from users where 1 = 0"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestWhereIntInLargeList(t *testing.T) {
+	ids := make([]int64, 100000)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	query, err := NewQueryBuilder().Table("events").WhereIntIn("id", ids).TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if query.Params != nil {
+		t.Errorf("Expected no params, got %d", len(query.Params))
+	}
+}
@@ -0,0 +1,133 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidJoin is returned by TryBuild for a join condition that is not
+// a comparison of columns
+var ErrInvalidJoin = errors.New("invalid join condition")
+
+// joinTerm is one "left op right" comparison of a join condition
+type joinTerm struct {
+	left, op, right string
+}
+
+var joinIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*`)
+
+// joinOperators is ordered so two-character operators match first
+var joinOperators = []string{"<=", ">=", "<>", "!=", "=", "<", ">"}
+
+// joinLiterals are keywords the identifier pattern would take for columns
+var joinLiterals = []string{"and", "or", "not", "null", "true", "false"}
+
+// join adds a join clause. condition is a string comparing columns,
+// "left = right [and ...]", or a Raw expression for anything else.
+func (b *QueryBuilder) join(joinType, table, alias string, condition interface{}) *QueryBuilder {
+	join := &JoinClause{Type: joinType, Table: table, Alias: alias}
+	switch c := condition.(type) {
+	case string:
+		join.Condition = c
+	case Expr:
+		join.Condition = c.SQL
+		join.On = &c
+	default:
+		return b.fail(fmt.Errorf("%w: %T on %s, use a string or Raw", ErrInvalidJoin, condition, table))
+	}
+	b.joinClauses = append(b.joinClauses, join)
+	return b
+}
+
+// parseJoinCondition splits condition into its comparisons, which are
+// joined by and
+func parseJoinCondition(condition string) ([]joinTerm, error) {
+	invalid := fmt.Errorf("%w: %q is not a comparison of columns, wrap it in Raw", ErrInvalidJoin, condition)
+	var terms []joinTerm
+	rest := condition
+	for {
+		var term joinTerm
+		var ok bool
+		if term.left, rest, ok = scanJoinIdentifier(rest); !ok {
+			return nil, invalid
+		}
+		if term.op, rest, ok = scanJoinOperator(rest); !ok {
+			return nil, invalid
+		}
+		if term.right, rest, ok = scanJoinIdentifier(rest); !ok {
+			return nil, invalid
+		}
+		terms = append(terms, term)
+
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return terms, nil
+		}
+		fields := strings.SplitN(rest, " ", 2)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "and") {
+			return nil, invalid
+		}
+		rest = fields[1]
+	}
+}
+
+func scanJoinIdentifier(s string) (string, string, bool) {
+	s = strings.TrimSpace(s)
+	name := joinIdentifierPattern.FindString(s)
+	if name == "" {
+		return "", s, false
+	}
+	for _, literal := range joinLiterals {
+		if strings.EqualFold(name, literal) {
+			return "", s, false
+		}
+	}
+	return name, s[len(name):], true
+}
+
+func scanJoinOperator(s string) (string, string, bool) {
+	s = strings.TrimSpace(s)
+	for _, op := range joinOperators {
+		if strings.HasPrefix(s, op) {
+			return op, s[len(op):], true
+		}
+	}
+	return "", s, false
+}
+
+// validateJoins checks every join condition not given as Raw
+func (b *QueryBuilder) validateJoins() error {
+	for _, join := range b.joinClauses {
+		if join.On != nil {
+			continue
+		}
+		if _, err := parseJoinCondition(join.Condition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinCondition writes the condition of join, quoting each column
+func (w *sqlWriter) joinCondition(join *JoinClause) {
+	if join.On != nil {
+		w.expr(*join.On)
+		return
+	}
+	terms, err := parseJoinCondition(join.Condition)
+	if err != nil {
+		// unreachable after validateJoins
+		w.raw(join.Condition)
+		return
+	}
+	for i, term := range terms {
+		if i > 0 {
+			w.write(" and ")
+		}
+		w.ident(term.left)
+		w.write(" " + term.op + " ")
+		w.ident(term.right)
+	}
+}
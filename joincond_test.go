@@ -0,0 +1,57 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJoinCondition(t *testing.T) {
+	query, err := NewQueryBuilder().Table("users").As("u").
+		QuoteStyle(DoubleQuote).
+		JoinAs("orders", "o", "o.user_id=u.id  AND o.region <> u.region").
+		Where("u.id", "=", 1).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := `select * from "users" as "u" JOIN "orders" as "o" on "o"."user_id" = "u"."id" and "o"."region" <> "u"."region" where "u"."id" = $1`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestJoinConditionRaw(t *testing.T) {
+	query, err := NewQueryBuilder().Table("users").As("u").
+		LeftJoinAs("orders", "o", Raw("o.user_id = u.id and o.total > ?", 100)).
+		Where("u.id", "=", 1).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := "select * from users as u LEFT JOIN orders as o on o.user_id = u.id and o.total > $1 where u.id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[0] != 100 || query.Params[1] != 1 {
+		t.Errorf("Expected params [100 1], got: %v", query.Params)
+	}
+}
+
+func TestJoinConditionInvalid(t *testing.T) {
+	conditions := []interface{}{
+		"",
+		"o.total > 100",
+		"o.user_id = u.id or 1 = 1",
+		"o.user_id = u.id; drop table users",
+		"lower(o.email) = u.email",
+		"o.active = true",
+		"o.user_id = u.id and",
+		42,
+	}
+	for _, condition := range conditions {
+		_, err := NewQueryBuilder().Table("users").As("u").JoinAs("orders", "o", condition).TryBuild()
+		if !errors.Is(err, ErrInvalidJoin) {
+			t.Errorf("Expected ErrInvalidJoin for %v, got: %v", condition, err)
+		}
+	}
+}
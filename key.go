@@ -0,0 +1,36 @@
+package query
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrEmptyKey is returned by TryBuild when WhereKey or FindByKey is given
+// no key columns, which would otherwise match every row
+var ErrEmptyKey = errors.New("empty key")
+
+// WhereKey adds one parenthesized group of equality conditions, one per
+// key column, for tables with composite primary keys:
+// "(id = $1 and tenant_id = $2)". Columns are sorted so the SQL is stable.
+// Works with select, update and delete.
+func (b *QueryBuilder) WhereKey(key map[string]interface{}) *QueryBuilder {
+	if len(key) == 0 {
+		return b.fail(ErrEmptyKey)
+	}
+	columns := make([]string, 0, len(key))
+	for column := range key {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+
+	return b.WhereGroup(func(q *QueryBuilder) {
+		for _, column := range columns {
+			q.Where(column, "=", key[column])
+		}
+	})
+}
+
+// FindByKey selects the single row with the given key
+func (b *QueryBuilder) FindByKey(key map[string]interface{}) *QueryBuilder {
+	return b.WhereKey(key).Limit(1)
+}
@@ -0,0 +1,46 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWhereKey(t *testing.T) {
+	key := map[string]interface{}{"tenant_id": 1, "id": 42}
+
+	tests := []struct {
+		qb          *QueryBuilder
+		expectedSQL string
+	}{
+		{
+			NewQueryBuilder().Table("invoices").FindByKey(key),
+			"select * from invoices where (id = $1 and tenant_id = $2) limit 1",
+		},
+		{
+			NewQueryBuilder().Table("invoices").Set("status", "void").WhereKey(key),
+			"update invoices set status = $1 where (id = $2 and tenant_id = $3)",
+		},
+		{
+			NewQueryBuilder().Table("invoices").Delete().WhereKey(key),
+			"delete from invoices where (id = $1 and tenant_id = $2)",
+		},
+	}
+
+	for _, tt := range tests {
+		if query := tt.qb.Build(); query.SQL != tt.expectedSQL {
+			t.Errorf("Expected SQL: %s, got: %s", tt.expectedSQL, query.SQL)
+		}
+	}
+
+	query := NewQueryBuilder().Table("invoices").FindByKey(key).Build()
+	if len(query.Params) != 2 || query.Params[0] != 42 || query.Params[1] != 1 {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestWhereKeyEmpty(t *testing.T) {
+	_, err := NewQueryBuilder().Table("invoices").Delete().WhereKey(nil).TryBuild()
+	if !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("Expected ErrEmptyKey, got: %v", err)
+	}
+}
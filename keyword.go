@@ -0,0 +1,31 @@
+package query
+
+import "strings"
+
+// KeywordCasing selects how SQL keywords are written
+type KeywordCasing int
+
+const (
+	AsWritten KeywordCasing = iota // lowercase keywords, uppercase join types
+	Upper                          // SELECT ... LEFT JOIN ... WHERE
+	Lower                          // select ... left join ... where
+)
+
+// KeywordCase writes every keyword the builder renders, join types
+// included, in one case. Identifiers, bound values and raw fragments such
+// as SelectRaw, WhereRaw and Raw join conditions are left untouched.
+func (b *QueryBuilder) KeywordCase(casing KeywordCasing) *QueryBuilder {
+	b.keywordCase = casing
+	return b
+}
+
+func (c KeywordCasing) apply(s string) string {
+	switch c {
+	case Upper:
+		return strings.ToUpper(s)
+	case Lower:
+		return strings.ToLower(s)
+	default:
+		return s
+	}
+}
@@ -0,0 +1,52 @@
+package query
+
+import "testing"
+
+func TestKeywordCaseUpper(t *testing.T) {
+	query := NewQueryBuilder().
+		KeywordCase(Upper).
+		Table("users").
+		As("u").
+		Select("u.id", "u.name as label").
+		LeftJoinAs("orders", "o", "o.user_id = u.id").
+		Where("u.active", "=", true).
+		WhereIn("u.role", "admin", "staff").
+		OrderBy("u.name desc").
+		Limit(5).
+		Build()
+
+	expectedSQL := "SELECT u.id, u.name AS label FROM users AS u LEFT JOIN orders AS o ON o.user_id = u.id WHERE u.active = $1 AND u.role IN ($2, $3) ORDER BY u.name DESC LIMIT 5"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestKeywordCaseLower(t *testing.T) {
+	query := NewQueryBuilder().
+		KeywordCase(Lower).
+		Table("users").
+		InnerJoin("teams", "teams.ID = users.team_id").
+		WhereRaw("users.Name LIKE ?", "A%").
+		Build()
+
+	expectedSQL := "select * from users inner join teams on teams.ID = users.team_id where users.Name LIKE $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestKeywordCaseInterpolated(t *testing.T) {
+	sql, err := NewQueryBuilder().
+		KeywordCase(Upper).
+		Table("users").
+		Where("name", "=", "o'neil").
+		BuildInterpolated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := "SELECT * FROM users WHERE name = 'o''neil'"
+	if sql != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, sql)
+	}
+}
@@ -0,0 +1,100 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeepOffsetThreshold is the OFFSET above which Lint reports deep pagination
+const DeepOffsetThreshold = 1000
+
+// Warning codes reported by Lint
+const (
+	WarnSelectStarWithJoin = "select-star-with-join"
+	WarnMissingWhere       = "missing-where"
+	WarnDeepOffset         = "deep-offset"
+	WarnNonSargableWhere   = "non-sargable-where"
+	WarnCartesianJoin      = "cartesian-join"
+	WarnLongIdentifier     = "long-identifier"
+)
+
+// Warning describes a potentially problematic pattern found by Lint
+type Warning struct {
+	Code    string
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Code + ": " + w.Message
+}
+
+// Lint inspects a builder for common query anti-patterns
+func Lint(b *QueryBuilder) []Warning {
+	var warnings []Warning
+
+	if b.queryType == SelectQuery && len(b.joinClauses) > 0 {
+		for _, column := range b.columns {
+			if column == "*" {
+				warnings = append(warnings, Warning{
+					Code:    WarnSelectStarWithJoin,
+					Message: "select * with joins returns every column of every joined table",
+				})
+				break
+			}
+		}
+	}
+
+	if (b.queryType == DeleteQuery || b.queryType == UpdateQuery) && len(b.whereClauses) == 0 {
+		warnings = append(warnings, Warning{
+			Code:    WarnMissingWhere,
+			Message: fmt.Sprintf("%s on %s has no where clause and affects every row", queryTypeName(b.queryType), b.table),
+		})
+	}
+
+	if b.queryType == SelectQuery && b.offset > DeepOffsetThreshold {
+		warnings = append(warnings, Warning{
+			Code:    WarnDeepOffset,
+			Message: fmt.Sprintf("offset %d scans and discards every skipped row; consider keyset pagination", b.offset),
+		})
+	}
+
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		if where.Expr == nil && strings.Contains(where.Column, "(") {
+			warnings = append(warnings, Warning{
+				Code:    WarnNonSargableWhere,
+				Message: fmt.Sprintf("where on %s wraps the column in a function and cannot use an index", where.Column),
+			})
+		}
+	})
+
+	for _, join := range b.joinClauses {
+		if strings.TrimSpace(join.Condition) == "" {
+			warnings = append(warnings, Warning{
+				Code:    WarnCartesianJoin,
+				Message: fmt.Sprintf("join on %s has no condition and produces a cartesian product", join.Table),
+			})
+		}
+	}
+
+	if name, ok := b.longIdentifier(); ok {
+		warnings = append(warnings, Warning{
+			Code:    WarnLongIdentifier,
+			Message: fmt.Sprintf("%s is longer than the %d bytes %s allows and may be truncated or rejected", name, b.target().MaxIdentifierLength(), b.target()),
+		})
+	}
+
+	return warnings
+}
+
+func queryTypeName(queryType QueryType) string {
+	switch queryType {
+	case InsertQuery:
+		return "insert"
+	case UpdateQuery:
+		return "update"
+	case DeleteQuery:
+		return "delete"
+	default:
+		return "select"
+	}
+}
@@ -0,0 +1,50 @@
+package query
+
+import "testing"
+
+func lintCodes(warnings []Warning) map[string]bool {
+	codes := make(map[string]bool)
+	for _, w := range warnings {
+		codes[w.Code] = true
+	}
+	return codes
+}
+
+func TestLintCleanQuery(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		Select("users.id", "accounts.name").
+		Join("accounts", "accounts.id = users.account_id").
+		Where("users.id", "=", 1)
+
+	if warnings := Lint(qb); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+}
+
+func TestLintWarnings(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		Join("accounts", "").
+		Where("lower(email)", "=", "a@example.com").
+		Offset(5000)
+
+	codes := lintCodes(Lint(qb))
+	for _, code := range []string{WarnSelectStarWithJoin, WarnDeepOffset, WarnNonSargableWhere, WarnCartesianJoin} {
+		if !codes[code] {
+			t.Errorf("Expected warning %s, got: %v", code, codes)
+		}
+	}
+}
+
+func TestLintMissingWhereOnDelete(t *testing.T) {
+	codes := lintCodes(Lint(NewQueryBuilder().Table("users").Delete()))
+	if !codes[WarnMissingWhere] {
+		t.Errorf("Expected warning %s, got: %v", WarnMissingWhere, codes)
+	}
+
+	codes = lintCodes(Lint(NewQueryBuilder().Table("users").Delete().Where("id", "=", 1)))
+	if codes[WarnMissingWhere] {
+		t.Errorf("Expected no %s warning, got: %v", WarnMissingWhere, codes)
+	}
+}
@@ -0,0 +1,72 @@
+package query
+
+import "fmt"
+
+// ForUpdate locks the selected rows against concurrent updates and deletes
+// until the transaction ends
+func (b *QueryBuilder) ForUpdate() *QueryBuilder {
+	b.lockStrength = "update"
+	return b
+}
+
+// ForShare locks the selected rows against concurrent updates and deletes
+// while still letting other transactions read and share-lock them
+func (b *QueryBuilder) ForShare() *QueryBuilder {
+	b.lockStrength = "share"
+	return b
+}
+
+// ForUpdateOf is ForUpdate restricted to rows of the named tables or
+// aliases, so a select joining lookup tables only locks what it will
+// change: "for update of orders"
+func (b *QueryBuilder) ForUpdateOf(tables ...string) *QueryBuilder {
+	b.lockOf = append(b.lockOf, tables...)
+	return b.ForUpdate()
+}
+
+// SkipLocked makes a locking select skip rows locked by other transactions
+// instead of waiting, for queue-style consumers
+func (b *QueryBuilder) SkipLocked() *QueryBuilder {
+	b.lockWait = "skip locked"
+	return b
+}
+
+// NoWait makes a locking select fail at once if a row is already locked
+func (b *QueryBuilder) NoWait() *QueryBuilder {
+	b.lockWait = "nowait"
+	return b
+}
+
+func (b *QueryBuilder) writeLock(w *sqlWriter) {
+	if b.lockStrength == "" {
+		return
+	}
+	w.write(" for ")
+	w.write(b.lockStrength)
+	if len(b.lockOf) > 0 {
+		w.write(" of ")
+		for i, table := range b.lockOf {
+			if i > 0 {
+				w.write(", ")
+			}
+			w.ident(table)
+		}
+	}
+	if b.lockWait != "" {
+		w.write(" ")
+		w.write(b.lockWait)
+	}
+}
+
+func (b *QueryBuilder) validateLock() error {
+	if b.lockStrength == "" {
+		if b.lockWait != "" {
+			return fmt.Errorf("%w: %s without a locking clause", ErrUnsupportedFeature, b.lockWait)
+		}
+		return nil
+	}
+	if b.queryType != SelectQuery {
+		return fmt.Errorf("%w: row locking on %s statement", ErrNotSelect, queryTypeName(b.queryType))
+	}
+	return b.require(RowLocking)
+}
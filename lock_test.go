@@ -0,0 +1,51 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForUpdateOf(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("orders").
+		As("o").
+		Select("o.id", "c.name").
+		Join("customers c", "c.id = o.customer_id").
+		Where("o.status", "=", "pending").
+		Limit(10).
+		ForUpdateOf("o").
+		SkipLocked().
+		Build()
+
+	expectedSQL := "select o.id, c.name from orders as o JOIN customers c on c.id = o.customer_id where o.status = $1 limit 10 for update of o skip locked"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestForShare(t *testing.T) {
+	query := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("accounts").
+		Where("id", "=", 1).
+		ForShare().
+		NoWait().
+		Build()
+
+	expectedSQL := "select * from accounts where id = ? for share nowait"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestLockingErrors(t *testing.T) {
+	if _, err := NewQueryBuilder().Table("a").Delete().ForUpdate().TryBuild(); !errors.Is(err, ErrNotSelect) {
+		t.Errorf("Expected ErrNotSelect, got: %v", err)
+	}
+	if _, err := NewQueryBuilder().Table("a").SkipLocked().TryBuild(); !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature for SkipLocked alone, got: %v", err)
+	}
+	if _, err := NewQueryBuilder().Dialect(DuckDB).Table("a").ForUpdate().TryBuild(); !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature on DuckDB, got: %v", err)
+	}
+}
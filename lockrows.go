@@ -0,0 +1,61 @@
+package query
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+)
+
+// LockRows locks the rows of table whose id is in ids with
+// "select id from table where id in (...) order by id for update" and
+// returns the ids it locked, in order; ids without a row are left out.
+// Sorting and deduplicating first means every caller takes the locks in
+// the same order, so two transactions locking overlapping sets wait for
+// each other instead of deadlocking. db must be a *sql.Tx (or a *sql.Conn
+// inside a transaction), since the locks last until the transaction ends.
+// The package default dialect is used, see SetDefaultDialect.
+func LockRows[K cmp.Ordered](ctx context.Context, db Querier, table string, ids []K) ([]K, error) {
+	if _, ok := db.(*sql.DB); ok {
+		return nil, errors.New("query: LockRows needs a transaction, not a *sql.DB")
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	values := make([]interface{}, len(sorted))
+	for i, id := range sorted {
+		values[i] = id
+	}
+	q, err := NewQueryBuilder().
+		StrictIdentifiers().
+		Table(table).
+		Select("id").
+		WhereIn("id", values...).
+		OrderBy("id").
+		ForUpdate().
+		TryBuild()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, q.SQL, q.Params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var locked []K
+	for rows.Next() {
+		var id K
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		locked = append(locked, id)
+	}
+	return locked, rows.Err()
+}
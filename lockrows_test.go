@@ -0,0 +1,57 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestLockRows(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select id from accounts where id in ($1, $2, $3) order by id for update",
+		[]string{"id"}, []driver.Value{int64(2)}, []driver.Value{int64(5)})
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer tx.Rollback()
+
+	locked, err := LockRows(ctx, tx, "accounts", []int64{9, 2, 5, 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(locked) != 2 || locked[0] != 2 || locked[1] != 5 {
+		t.Errorf("Expected [2 5] locked, got: %v", locked)
+	}
+
+	args := fake.Calls()[0].Args
+	expectedArgs := []interface{}{int64(2), int64(5), int64(9)}
+	for i, arg := range args {
+		if arg != expectedArgs[i] {
+			t.Errorf("Expected sorted, deduplicated ids %v, got: %v", expectedArgs, args)
+			break
+		}
+	}
+}
+
+func TestLockRowsErrors(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+	if _, err := LockRows(ctx, db, "accounts", []int64{1}); err == nil {
+		t.Error("Expected an error outside a transaction")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := LockRows(ctx, tx, "accounts; drop table x", []string{"a"}); err == nil {
+		t.Error("Expected an error for an unsafe table")
+	}
+	if locked, err := LockRows(ctx, tx, "accounts", []string(nil)); err != nil || locked != nil {
+		t.Errorf("Expected nothing locked, got: %v (%v)", locked, err)
+	}
+}
@@ -0,0 +1,102 @@
+// Package maint builds database maintenance statements such as VACUUM,
+// ANALYZE and OPTIMIZE TABLE.
+package maint
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/scape-labs/query"
+)
+
+// ErrNoTable is returned by TryBuild for a statement without a table
+var ErrNoTable = errors.New("no table")
+
+// Statement is a maintenance statement. It implements query.Builder, so a
+// Runner can execute it.
+type Statement struct {
+	sql string
+	err error
+}
+
+// Build returns the statement, or an empty Query if it is invalid.
+//
+// Deprecated: Build drops the error; use TryBuild.
+func (s *Statement) Build() query.Query {
+	q, _ := s.TryBuild()
+	return q
+}
+
+// TryBuild returns the statement, or ErrNoTable or query.ErrUnsafeIdentifier
+// for a missing or malformed table name
+func (s *Statement) TryBuild() (query.Query, error) {
+	if s.err != nil {
+		return query.Query{}, s.err
+	}
+	return query.Query{SQL: s.sql}, nil
+}
+
+// statement quotes tables for dialect and appends them to prefix
+func statement(dialect query.Dialect, prefix string, tables ...string) *Statement {
+	if len(tables) == 0 {
+		return &Statement{err: ErrNoTable}
+	}
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		name, err := dialect.QuoteIdentifier(table)
+		if err != nil {
+			return &Statement{err: err}
+		}
+		quoted[i] = name
+	}
+	return &Statement{sql: prefix + " " + strings.Join(quoted, ", ")}
+}
+
+type vacuumOptions struct {
+	full    bool
+	analyze bool
+}
+
+// VacuumOption configures a VACUUM statement
+type VacuumOption func(*vacuumOptions)
+
+// Full reclaims space by rewriting the table (VACUUM FULL)
+func Full() VacuumOption {
+	return func(o *vacuumOptions) {
+		o.full = true
+	}
+}
+
+// WithAnalyze updates planner statistics after vacuuming (VACUUM ANALYZE)
+func WithAnalyze() VacuumOption {
+	return func(o *vacuumOptions) {
+		o.analyze = true
+	}
+}
+
+// Vacuum builds a Postgres VACUUM statement for table
+func Vacuum(table string, opts ...VacuumOption) *Statement {
+	var o vacuumOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	prefix := "vacuum"
+	if o.full {
+		prefix += " full"
+	}
+	if o.analyze {
+		prefix += " analyze"
+	}
+	return statement(query.Postgres, prefix, table)
+}
+
+// Analyze builds a Postgres ANALYZE statement for table
+func Analyze(table string) *Statement {
+	return statement(query.Postgres, "analyze", table)
+}
+
+// OptimizeTable builds a MySQL OPTIMIZE TABLE statement
+func OptimizeTable(tables ...string) *Statement {
+	return statement(query.MySQL, "optimize table", tables...)
+}
@@ -0,0 +1,92 @@
+package maint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scape-labs/query"
+	"github.com/scape-labs/query/querytest"
+)
+
+func TestVacuum(t *testing.T) {
+	query, err := Vacuum("users").TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := `vacuum "users"`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestVacuumWithOptions(t *testing.T) {
+	query, err := Vacuum("public.users", Full(), WithAnalyze()).TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := `vacuum full analyze "public"."users"`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 0 {
+		t.Errorf("Expected no params, got: %v", query.Params)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	query, err := Analyze("users").TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := `analyze "users"`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestOptimizeTable(t *testing.T) {
+	query, err := OptimizeTable("users", "orders").TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := "optimize table `users`, `orders`"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestInvalidTables(t *testing.T) {
+	tests := []struct {
+		statement *Statement
+		err       error
+	}{
+		{Vacuum(""), query.ErrUnsafeIdentifier},
+		{Analyze("users; drop table users"), query.ErrUnsafeIdentifier},
+		{OptimizeTable(), ErrNoTable},
+		{OptimizeTable("users", ""), query.ErrUnsafeIdentifier},
+	}
+	for _, test := range tests {
+		if _, err := test.statement.TryBuild(); !errors.Is(err, test.err) {
+			t.Errorf("Expected %v, got: %v", test.err, err)
+		}
+		if q := test.statement.Build(); q.SQL != "" {
+			t.Errorf("Expected empty SQL, got: %s", q.SQL)
+		}
+	}
+}
+
+func TestRunnerExecutesStatement(t *testing.T) {
+	fake := querytest.New(t)
+	vacuum := Vacuum("users", WithAnalyze())
+	fake.Affects(vacuum, 0)
+
+	if _, err := fake.Runner.Exec(context.Background(), vacuum); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	calls := fake.Calls()
+	if len(calls) != 1 || calls[0].SQL != `vacuum analyze "users"` {
+		t.Errorf("Expected one recorded vacuum, got: %v", calls)
+	}
+}
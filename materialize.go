@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+	"fmt"
+)
+
+// Materialize stores the query's rows in a temporary table with
+// "create temp table name as select ..." and returns a builder selecting
+// from it, so multi-pass jobs run expensive filters once. Temporary tables
+// belong to one connection: pass a *sql.Conn or *sql.Tx, not a pooled
+// *sql.DB. The returned builder keeps the dialect, placeholder style,
+// quoting, keyword casing and time zone of b.
+func (b *QueryBuilder) Materialize(ctx context.Context, db DB, name string) (*QueryBuilder, error) {
+	if !isIdentifier(name) {
+		return nil, unsafeIdentifier(name)
+	}
+	if b.queryType != SelectQuery {
+		return nil, fmt.Errorf("%w: Materialize on %s statement", ErrNotSelect, queryTypeName(b.queryType))
+	}
+	create := "create temp table "
+	switch b.target() {
+	case MySQL:
+		create = "create temporary table "
+	case CQL:
+		return nil, fmt.Errorf("%w: temporary tables on %s", ErrUnsupportedFeature, b.target())
+	}
+
+	q, err := b.TryBuild()
+	if err != nil {
+		return nil, err
+	}
+	sql := b.keywordCase.apply(create) + b.quoteStyle.quoteIdentifier(name) + b.keywordCase.apply(" as ") + q.SQL
+	if _, err := db.ExecContext(ctx, sql, q.Params...); err != nil {
+		return nil, err
+	}
+
+	materialized := NewQueryBuilder().Table(name)
+	materialized.paramStyle = b.paramStyle
+	materialized.dialect = b.dialect
+	materialized.quoteStyle = b.quoteStyle
+	materialized.keywordCase = b.keywordCase
+	materialized.timeLocation = b.timeLocation
+	return materialized, nil
+}
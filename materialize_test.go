@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMaterialize(t *testing.T) {
+	db, fake := newFakeDB(t)
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	qb := NewQueryBuilder().
+		Table("events").
+		Select("user_id", "kind").
+		Where("created_at", ">", "2024-01-01")
+
+	tmp, err := qb.Materialize(context.Background(), conn, "tmp_results")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	calls := fake.Calls()
+	expectedSQL := "create temp table tmp_results as select user_id, kind from events where created_at > $1"
+	if len(calls) != 1 || calls[0].SQL != expectedSQL || calls[0].Args[0] != "2024-01-01" {
+		t.Errorf("Unexpected calls: %+v", calls)
+	}
+
+	query := tmp.Where("kind", "=", "click").Build()
+	expectedSQL = "select * from tmp_results where kind = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestMaterializeMySQL(t *testing.T) {
+	db, fake := newFakeDB(t)
+	tmp, err := NewQueryBuilder().Dialect(MySQL).Table("events").Materialize(context.Background(), db, "tmp_events")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls := fake.Calls(); calls[0].SQL != "create temporary table tmp_events as select * from events" {
+		t.Errorf("Unexpected SQL: %s", calls[0].SQL)
+	}
+	if query := tmp.Where("id", "=", 1).Build(); query.SQL != "select * from tmp_events where id = ?" {
+		t.Errorf("Expected the MySQL placeholder style, got: %s", query.SQL)
+	}
+}
+
+func TestMaterializeErrors(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	if _, err := NewQueryBuilder().Table("events").Materialize(ctx, db, "tmp; drop table x"); !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+	if _, err := NewQueryBuilder().Table("events").Delete().Materialize(ctx, db, "tmp"); !errors.Is(err, ErrNotSelect) {
+		t.Errorf("Expected ErrNotSelect, got: %v", err)
+	}
+}
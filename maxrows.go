@@ -0,0 +1,45 @@
+package query
+
+// MaxRows caps every select the Runner runs at n rows: selects without a
+// limit get "limit n", and larger limits are lowered to n, so a forgotten
+// filter cannot stream a whole table into the service. Union and UnionFeed
+// are capped on their combined rows, and FanOut on its merged rows rather
+// than each shard's. A limit of 0 disables the cap.
+func MaxRows(n int) RunnerOption {
+	return func(r *Runner) {
+		r.maxRows = n
+	}
+}
+
+// capRows returns qb limited to the Runner's MaxRows, copied when changed
+func (r *Runner) capRows(qb Builder) Builder {
+	switch qb := qb.(type) {
+	case *QueryBuilder:
+		if qb.queryType != SelectQuery || qb.exists || !r.overCap(qb.limit) {
+			return qb
+		}
+		b := qb.Clone()
+		b.limit = r.maxRows
+		return b
+	case *Compound:
+		if !r.overCap(qb.limit) {
+			return qb
+		}
+		c := *qb
+		c.limit = r.maxRows
+		return &c
+	case *Feed:
+		if !r.overCap(qb.limit) {
+			return qb
+		}
+		f := *qb
+		f.limit = r.maxRows
+		return &f
+	}
+	return qb
+}
+
+// overCap reports whether limit lets through more rows than MaxRows
+func (r *Runner) overCap(limit int) bool {
+	return r.maxRows > 0 && (limit <= 0 || limit > r.maxRows)
+}
@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestMaxRows(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select * from events limit 100", []string{"id"})
+	fake.On("select * from events limit 10", []string{"id"})
+	fake.On("select exists(select 1 from events)", []string{"exists"})
+	fake.OnExec("delete from events", 0)
+
+	runner := NewRunner(db, MaxRows(100))
+	ctx := context.Background()
+
+	base := NewQueryBuilder().Table("events")
+	for _, qb := range []*QueryBuilder{base, NewQueryBuilder().Table("events").Limit(500), NewQueryBuilder().Table("events").Limit(10)} {
+		rows, err := runner.Query(ctx, qb)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rows.Close()
+	}
+	if _, err := runner.QueryRow(ctx, NewQueryBuilder().Table("events").AsExists()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := runner.Exec(ctx, NewQueryBuilder().Table("events").Delete()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"select * from events limit 100",
+		"select * from events limit 100",
+		"select * from events limit 10",
+		"select exists(select 1 from events)",
+		"delete from events",
+	}
+	calls := fake.Calls()
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected %d calls, got: %v", len(expected), calls)
+	}
+	for i, call := range calls {
+		if call.SQL != expected[i] {
+			t.Errorf("Expected SQL: %s, got: %s", expected[i], call.SQL)
+		}
+	}
+	if base.limit != 0 {
+		t.Errorf("Expected the builder to be left unchanged, got limit %d", base.limit)
+	}
+}
+
+func TestMaxRowsCompound(t *testing.T) {
+	db, fake := newFakeDB(t)
+	union := "select id from events union select id from archived_events limit 100"
+	feed := "(select id, 'events' as source from events) union all (select id, 'archived_events' as source from archived_events) limit 100"
+	fake.On(union, []string{"id"})
+	fake.On(feed, []string{"id", "source"})
+
+	runner := NewRunner(db, MaxRows(100))
+	ctx := context.Background()
+	builders := []Builder{
+		Union(NewQueryBuilder().Table("events").Select("id"), NewQueryBuilder().Table("archived_events").Select("id")).Limit(500),
+		UnionFeed(NewQueryBuilder().Table("events").Select("id"), NewQueryBuilder().Table("archived_events").Select("id")),
+	}
+	for _, qb := range builders {
+		rows, err := runner.Query(ctx, qb)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rows.Close()
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0].SQL != union || calls[1].SQL != feed {
+		t.Errorf("Expected SQL: %s and %s, got: %+v", union, feed, calls)
+	}
+}
+
+func TestMaxRowsFanOut(t *testing.T) {
+	query := "select id from orders order by id limit 4"
+	eu, euFake := newFakeDB(t)
+	euFake.On(query, []string{"id"}, []driver.Value{int64(1)}, []driver.Value{int64(3)}, []driver.Value{int64(5)}, []driver.Value{int64(7)})
+	us, usFake := newFakeDB(t)
+	usFake.On(query, []string{"id"}, []driver.Value{int64(2)}, []driver.Value{int64(4)}, []driver.Value{int64(6)}, []driver.Value{int64(8)})
+
+	runner := NewRunner(eu, MaxRows(3))
+	qb := NewQueryBuilder().Table("orders").Select("id").OrderBy("id").Limit(2).Offset(2)
+
+	result, err := runner.FanOut(context.Background(), []*sql.DB{eu, us}, qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Rows) != 2 || result.Rows[0][0] != int64(3) || result.Rows[1][0] != int64(4) {
+		t.Errorf("Expected the third and fourth merged rows, got: %v", result.Rows)
+	}
+
+	qb = NewQueryBuilder().Table("orders").Select("id").OrderBy("id").Limit(4)
+	result, err = runner.FanOut(context.Background(), []*sql.DB{eu, us}, qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Rows) != 3 || result.Rows[2][0] != int64(3) {
+		t.Errorf("Expected the merged rows capped at 3, got: %v", result.Rows)
+	}
+}
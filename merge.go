@@ -0,0 +1,118 @@
+package query
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+)
+
+// mergeKey is one ordering column of merged shard results
+type mergeKey struct {
+	column     string
+	desc       bool
+	nullsFirst bool
+}
+
+func (k mergeKey) compare(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case (a == nil) == k.nullsFirst:
+			return -1
+		default:
+			return 1
+		}
+	}
+	c := compareValues(a, b)
+	if k.desc {
+		return -c
+	}
+	return c
+}
+
+// mergeKeys turns the builder's OrderBy into merge keys over its result
+// columns: "o.created_at desc nulls last" orders by created_at. Nulls
+// follow the database default unless the term says otherwise.
+func (b *QueryBuilder) mergeKeys() ([]mergeKey, error) {
+	if len(b.orderExprs) > 0 || b.randomOrder || b.sampleFilter() {
+		return nil, fmt.Errorf("%w: merging OrderByRaw or random order across shards", ErrUnsupportedFeature)
+	}
+	order := b.order
+	if order == "" && b.queryType == SelectQuery {
+		order = b.defaultOrder
+	}
+	var keys []mergeKey
+	for _, term := range strings.Split(order, ",") {
+		fields := strings.Fields(term)
+		if len(fields) == 0 {
+			continue
+		}
+		column := fields[0][strings.LastIndex(fields[0], ".")+1:]
+		modifiers := strings.ToLower(strings.Join(fields[1:], " "))
+		key := mergeKey{column: strings.Trim(column, "\"`"), desc: strings.HasPrefix(modifiers, "desc")}
+		switch {
+		case strings.HasSuffix(modifiers, "nulls first"):
+			key.nullsFirst = true
+		case strings.HasSuffix(modifiers, "nulls last"):
+		case b.target() == Postgres:
+			key.nullsFirst = key.desc
+		case b.target() != DuckDB:
+			key.nullsFirst = !key.desc
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// mergeSorted k-way merges streams already sorted by cmp, keeping stream
+// order for equal rows and stopping after n rows when n > 0
+func mergeSorted(streams [][][]interface{}, cmp func(a, b []interface{}) int, n int) [][]interface{} {
+	h := &mergeHeap{streams: streams, pos: make([]int, len(streams)), cmp: cmp}
+	for i, stream := range streams {
+		if len(stream) > 0 {
+			h.heads = append(h.heads, i)
+		}
+	}
+	heap.Init(h)
+
+	var merged [][]interface{}
+	for h.Len() > 0 && (n <= 0 || len(merged) < n) {
+		i := h.heads[0]
+		merged = append(merged, streams[i][h.pos[i]])
+		if h.pos[i]++; h.pos[i] < len(streams[i]) {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return merged
+}
+
+// mergeHeap orders stream indexes by their next row
+type mergeHeap struct {
+	streams [][][]interface{}
+	pos     []int
+	heads   []int
+	cmp     func(a, b []interface{}) int
+}
+
+func (h *mergeHeap) Len() int { return len(h.heads) }
+
+func (h *mergeHeap) Less(i, j int) bool {
+	a, b := h.heads[i], h.heads[j]
+	if c := h.cmp(h.streams[a][h.pos[a]], h.streams[b][h.pos[b]]); c != 0 {
+		return c < 0
+	}
+	return a < b
+}
+
+func (h *mergeHeap) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+
+func (h *mergeHeap) Push(x interface{}) { h.heads = append(h.heads, x.(int)) }
+
+func (h *mergeHeap) Pop() interface{} {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
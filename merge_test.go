@@ -0,0 +1,103 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestFanOutPagination(t *testing.T) {
+	query := "select id, created_at from events order by events.created_at desc, id limit 4"
+	a, aFake := newFakeDB(t)
+	aFake.On(query, []string{"id", "created_at"},
+		[]driver.Value{int64(1), int64(50)}, []driver.Value{int64(2), int64(30)},
+		[]driver.Value{int64(3), int64(20)}, []driver.Value{int64(4), int64(10)})
+	b, bFake := newFakeDB(t)
+	bFake.On(query, []string{"id", "created_at"},
+		[]driver.Value{int64(5), int64(40)}, []driver.Value{int64(6), int64(30)},
+		[]driver.Value{int64(7), int64(25)})
+
+	qb := NewQueryBuilder().Table("events").Select("id", "created_at").OrderBy("events.created_at desc, id").Limit(2).Offset(2)
+	result, err := NewRunner(a).FanOut(context.Background(), []*sql.DB{a, b}, qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedIDs := []int64{2, 6}
+	if len(result.Rows) != len(expectedIDs) {
+		t.Fatalf("Expected %d rows, got: %v", len(expectedIDs), result.Rows)
+	}
+	for i, row := range result.Rows {
+		if row[0] != expectedIDs[i] {
+			t.Errorf("Expected id %d at %d, got: %v", expectedIDs[i], i, row[0])
+		}
+	}
+	if qb.offset != 2 || qb.limit != 2 {
+		t.Errorf("Expected FanOut to leave the builder unchanged, got limit %d offset %d", qb.limit, qb.offset)
+	}
+}
+
+func TestFanOutUnordered(t *testing.T) {
+	query := "select id from events"
+	a, aFake := newFakeDB(t)
+	aFake.On(query, []string{"id"}, []driver.Value{int64(3)})
+	b, bFake := newFakeDB(t)
+	bFake.On(query, []string{"id"}, []driver.Value{int64(1)})
+
+	result, err := NewRunner(a).FanOut(context.Background(), []*sql.DB{a, b}, staticQuery{SQL: query})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Rows) != 2 || result.Rows[0][0] != int64(3) {
+		t.Errorf("Expected rows in db order, got: %v", result.Rows)
+	}
+
+	qb := NewQueryBuilder().Table("events").OrderByRandom()
+	if _, err := NewRunner(a).FanOut(context.Background(), []*sql.DB{a, b}, qb); !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+	}
+}
+
+func TestMergeKeys(t *testing.T) {
+	keys, err := NewQueryBuilder().OrderBy(`o."total" desc, name nulls first, id`).mergeKeys()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []mergeKey{{"total", true, true}, {"name", false, true}, {"id", false, false}}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %v, got: %v", expected, keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Errorf("Expected %v, got: %v", expected[i], keys[i])
+		}
+	}
+
+	keys, _ = NewQueryBuilder().Dialect(MySQL).OrderBy("total").mergeKeys()
+	if !keys[0].nullsFirst {
+		t.Error("Expected MySQL to sort nulls first in ascending order")
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	streams := [][][]interface{}{
+		{{int64(1)}, {int64(4)}},
+		{},
+		{{int64(2)}, {int64(4)}, {int64(9)}},
+	}
+	compare := func(a, b []interface{}) int { return compareValues(a[0], b[0]) }
+	merged := mergeSorted(streams, compare, 4)
+	expected := []int64{1, 2, 4, 4}
+	if len(merged) != len(expected) {
+		t.Fatalf("Expected %v, got: %v", expected, merged)
+	}
+	for i, row := range merged {
+		if row[0] != expected[i] {
+			t.Errorf("Expected %d at %d, got: %v", expected[i], i, row[0])
+		}
+	}
+	if &merged[2][0] != &streams[0][1][0] {
+		t.Error("Expected equal rows to keep stream order")
+	}
+}
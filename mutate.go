@@ -0,0 +1,55 @@
+package query
+
+import "slices"
+
+// RemoveWhere removes the top-level conditions on column, as added by
+// Where, OrWhere, WhereIn and the like, so a derived query can drop a
+// filter. Conditions inside groups and the base filters of ConfigureTable
+// (see Unscoped) are kept.
+func (b *QueryBuilder) RemoveWhere(column string) *QueryBuilder {
+	own := slices.DeleteFunc(b.whereClauses[b.baseFilters:], func(where *WhereClause) bool {
+		return where.Column == column
+	})
+	b.whereClauses = b.whereClauses[:b.baseFilters+len(own)]
+	return b
+}
+
+// ReplaceWhere replaces the top-level conditions on column with the single
+// condition "column operator value", in the place of the first one and
+// joined the same way. Without a condition on column it works as Where.
+func (b *QueryBuilder) ReplaceWhere(column, operator string, value interface{}) *QueryBuilder {
+	i := slices.IndexFunc(b.whereClauses[b.baseFilters:], func(where *WhereClause) bool {
+		return where.Column == column
+	})
+	if i < 0 {
+		return b.Where(column, operator, value)
+	}
+	i += b.baseFilters
+	joinType := b.whereClauses[i].JoinType
+	b.RemoveWhere(column)
+	b.whereClauses = slices.Insert(b.whereClauses, i, &WhereClause{
+		Column:   column,
+		Operator: operator,
+		Value:    value,
+		JoinType: joinType,
+	})
+	return b
+}
+
+// ClearOrder removes every ordering: OrderBy, OrderByRaw, random order and
+// the table's default order
+func (b *QueryBuilder) ClearOrder() *QueryBuilder {
+	b.order = ""
+	b.orderExprs = nil
+	b.randomOrder = false
+	b.defaultOrder = ""
+	return b
+}
+
+// ClearLimit removes the limit and the offset, which means little without
+// it, e.g. to export every row of a paginated list query
+func (b *QueryBuilder) ClearLimit() *QueryBuilder {
+	b.limit = 0
+	b.offset = 0
+	return b
+}
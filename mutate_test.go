@@ -0,0 +1,64 @@
+package query
+
+import "testing"
+
+func TestRemoveWhere(t *testing.T) {
+	ConfigureTable("docs", WithBaseFilter(func(q *QueryBuilder) {
+		q.Where("tenant_id", "=", 7)
+	}))
+	t.Cleanup(func() { ConfigureTable("docs") })
+
+	query := NewQueryBuilder().Table("docs").
+		Where("tenant_id", "=", 8).
+		Where("status", "=", "draft").
+		WhereIn("status", "review", "done").
+		Where("author_id", "=", 3).
+		RemoveWhere("status").
+		RemoveWhere("tenant_id").
+		Build()
+	expectedSQL := "select * from docs where tenant_id = $1 and author_id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[0] != 7 || query.Params[1] != 3 {
+		t.Errorf("Expected params [7 3], got: %v", query.Params)
+	}
+}
+
+func TestReplaceWhere(t *testing.T) {
+	query := NewQueryBuilder().Table("orders").
+		Where("region", "=", "eu").
+		OrWhere("status", "=", "paid").
+		Where("status", "<>", "void").
+		Where("total", ">", 100).
+		ReplaceWhere("status", "in", []interface{}{"paid", "shipped"}).
+		ReplaceWhere("customer_id", "=", 9).
+		Build()
+	expectedSQL := "select * from orders where region = $1 or status in ($2, $3) and total > $4 and customer_id = $5"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestClearOrderAndLimit(t *testing.T) {
+	configureEvents(t)
+
+	list := NewQueryBuilder().Table("events").
+		Where("kind", "=", "click").
+		OrderBy("id").
+		OrderByRaw("score <-> ?", 1).
+		Limit(20).
+		Offset(40)
+
+	query := list.Clone().ClearOrder().ClearLimit().Build()
+	expectedSQL := "select * from events where deleted_at is null and kind = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = list.Build()
+	expectedSQL = "select * from events where deleted_at is null and kind = $1 order by id, score <-> $2 limit 20 offset 40"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
@@ -0,0 +1,164 @@
+package query
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// NormalizedQuery is the canonical form of a query, suitable for grouping
+// executions that differ only in their parameter values
+type NormalizedQuery struct {
+	SQL  string
+	Hash string
+}
+
+var sqlKeywords = map[string]bool{
+	"select": true, "from": true, "where": true, "and": true, "or": true,
+	"not": true, "in": true, "is": true, "null": true, "like": true,
+	"ilike": true, "between": true, "as": true, "on": true, "join": true,
+	"inner": true, "left": true, "right": true, "full": true, "outer": true,
+	"cross": true, "order": true, "by": true, "group": true, "having": true,
+	"limit": true, "offset": true, "asc": true, "desc": true, "insert": true,
+	"into": true, "values": true, "update": true, "set": true, "delete": true,
+	"distinct": true, "union": true, "all": true, "exists": true, "case": true,
+	"when": true, "then": true, "else": true, "end": true, "true": true,
+	"false": true, "returning": true, "with": true,
+}
+
+// Normalize returns the canonical form of the query: keywords are
+// lowercased, whitespace is collapsed and every literal or placeholder is
+// replaced by a sequentially numbered $n placeholder, in the style of
+// pg_stat_statements. As in SafetyCheck, when the query has $n placeholders
+// a ? is the Postgres jsonb operator (?, ?| and ?&) and is kept. Hash is a
+// stable hex digest of the canonical SQL.
+func (q Query) Normalize() NormalizedQuery {
+	sql := normalizeSQL(q.SQL)
+	h := fnv.New64a()
+	h.Write([]byte(sql))
+	return NormalizedQuery{
+		SQL:  sql,
+		Hash: fmt.Sprintf("%016x", h.Sum64()),
+	}
+}
+
+// Fingerprint returns a stable identifier for the query's shape, the Hash of
+// Normalize. It is computed from the same normalized text pg_stat_statements
+// shows in its query column, so metrics can be correlated by joining on that
+// text; it is not the server's queryid, which is derived from the parse tree
+// and cannot be reproduced client-side.
+func (q Query) Fingerprint() string {
+	return q.Normalize().Hash
+}
+
+func normalizeSQL(sql string) string {
+	var out strings.Builder
+	runes := []rune(sql)
+	paramCount := 0
+	pendingSpace := false
+	questionOperator := hasDollarPlaceholder(runes)
+
+	placeholder := func() {
+		paramCount++
+		out.WriteString(fmt.Sprintf("$%d", paramCount))
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		if unicode.IsSpace(r) {
+			pendingSpace = out.Len() > 0
+			i++
+			continue
+		}
+		if r == '/' && i+1 < len(runes) && runes[i+1] == '*' {
+			// Comments, such as audit comments, do not change the shape
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i = min(i+2, len(runes))
+			pendingSpace = out.Len() > 0
+			continue
+		}
+		if pendingSpace {
+			out.WriteByte(' ')
+			pendingSpace = false
+		}
+
+		switch {
+		case r == '\'':
+			// String literal, '' is an escaped quote
+			i++
+			for i < len(runes) {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			placeholder()
+		case r == '"' || r == '`':
+			// Quoted identifier, kept verbatim
+			start := i
+			i++
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i < len(runes) {
+				i++
+			}
+			out.WriteString(string(runes[start:i]))
+		case r == '?' && !questionOperator:
+			i++
+			placeholder()
+		case r == '$' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			i++
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			placeholder()
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			placeholder()
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			word := string(runes[start:i])
+			if lower := strings.ToLower(word); sqlKeywords[lower] {
+				word = lower
+			}
+			out.WriteString(word)
+		default:
+			out.WriteRune(r)
+			i++
+		}
+	}
+
+	return out.String()
+}
+
+// hasDollarPlaceholder reports whether sql has a $n placeholder outside
+// string literals and quoted identifiers
+func hasDollarPlaceholder(sql []rune) bool {
+	for i := 0; i < len(sql); i++ {
+		switch r := sql[i]; {
+		case r == '\'' || r == '"' || r == '`':
+			// A doubled quote closes and reopens, which scans the same
+			for i++; i < len(sql) && sql[i] != r; i++ {
+			}
+		case r == '$' && i+1 < len(sql) && unicode.IsDigit(sql[i+1]):
+			return true
+		}
+	}
+	return false
+}
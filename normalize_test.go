@@ -0,0 +1,78 @@
+package query
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	q := Query{SQL: "SELECT id,  name\n FROM users WHERE age > 18 AND name = 'O''Brien' AND team_id = ? LIMIT 10"}
+
+	normalized := q.Normalize()
+	expectedSQL := "select id, name from users where age > $1 and name = $2 and team_id = $3 limit $4"
+	if normalized.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, normalized.SQL)
+	}
+
+	if len(normalized.Hash) != 16 {
+		t.Errorf("Expected 16 character hash, got: %s", normalized.Hash)
+	}
+}
+
+func TestNormalizeMatchesAcrossParameterStyles(t *testing.T) {
+	dollar := NewQueryBuilder().
+		Table("users").
+		Select("id").
+		Where("age", ">", 18).
+		Limit(10).
+		Build()
+	question := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("users").
+		Select("id").
+		Where("age", ">", 21).
+		Limit(50).
+		Build()
+
+	a, b := dollar.Normalize(), question.Normalize()
+	if a.SQL != b.SQL || a.Hash != b.Hash {
+		t.Errorf("Expected identical normalized queries, got: %+v and %+v", a, b)
+	}
+}
+
+func TestNormalizeKeepsIdentifiers(t *testing.T) {
+	q := Query{SQL: `select "Order"."Total", table1.col2 from "Order" join table1 on table1.id = "Order".id`}
+
+	expectedSQL := `select "Order"."Total", table1.col2 from "Order" join table1 on table1.id = "Order".id`
+	if got := q.Normalize().SQL; got != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, got)
+	}
+}
+
+func TestNormalizeKeepsJSONBOperators(t *testing.T) {
+	q := Query{SQL: `select * from docs where tags ? $1 and tags ?| $2 and tags ?& $3 and note = '$4?'`}
+
+	expectedSQL := "select * from docs where tags ? $1 and tags ?| $2 and tags ?& $3 and note = $4"
+	if got := q.Normalize().SQL; got != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, got)
+	}
+
+	q = Query{SQL: "select * from docs where note = '$1' and id = ?"}
+	expectedSQL = "select * from docs where note = $1 and id = $2"
+	if got := q.Normalize().SQL; got != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := NewQueryBuilder().Table("users").Where("id", "=", 1).Build()
+	b := NewQueryBuilder().Table("users").Where("id", "=", 2).Build()
+	c := NewQueryBuilder().Table("users").Where("email", "=", "a@example.com").Build()
+
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("Expected equal fingerprints for different params, got: %s and %s", a.Fingerprint(), b.Fingerprint())
+	}
+	if a.Fingerprint() == c.Fingerprint() {
+		t.Errorf("Expected different fingerprints for different shapes")
+	}
+	if a.Fingerprint() != a.Normalize().Hash {
+		t.Errorf("Expected Fingerprint to match Normalize().Hash")
+	}
+}
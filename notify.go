@@ -0,0 +1,112 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Notification is a message received on a LISTEN channel
+type Notification struct {
+	Channel string
+	Payload string
+
+	// Server process id of the sender
+	PID uint32
+}
+
+// Listener is one dedicated Postgres connection that can LISTEN. The
+// package has no driver dependency, so wrap your driver's connection:
+// with pgx, Exec calls (*pgx.Conn).Exec, WaitForNotification converts the
+// *pgconn.Notification returned by (*pgx.Conn).WaitForNotification, and
+// Close closes the connection.
+type Listener interface {
+	Exec(ctx context.Context, sql string) error
+	WaitForNotification(ctx context.Context) (Notification, error)
+	Close(ctx context.Context) error
+}
+
+// notifyQuery is the Builder returned by Notify
+type notifyQuery struct {
+	channel string
+	payload string
+}
+
+// Notify builds "select pg_notify($1, $2)", which sends payload to the
+// listeners of channel. Run it in the transaction making the change it
+// announces: Postgres delivers it only if the transaction commits.
+func Notify(channel, payload string) Builder {
+	return notifyQuery{channel: channel, payload: payload}
+}
+
+func (n notifyQuery) Build() Query {
+	q, err := n.TryBuild()
+	if err != nil {
+		return Query{}
+	}
+	return q
+}
+
+func (n notifyQuery) TryBuild() (Query, error) {
+	if n.channel == "" {
+		return Query{}, errors.New("query: notify needs a channel")
+	}
+	return Query{
+		SQL:    "select pg_notify($1, $2)",
+		Params: []interface{}{n.channel, n.payload},
+	}, nil
+}
+
+// ListenWith lets the Runner Listen, opening a dedicated connection with
+// connect for each call
+func ListenWith(connect func(ctx context.Context) (Listener, error)) RunnerOption {
+	return func(r *Runner) {
+		r.listen = connect
+	}
+}
+
+// Listen subscribes to channel on a new connection from ListenWith and
+// delivers its notifications until ctx is done or the connection fails;
+// the channel is then closed and the connection released.
+func (r *Runner) Listen(ctx context.Context, channel string) (<-chan Notification, error) {
+	if r.listen == nil {
+		return nil, errors.New("query: Listen needs the ListenWith option")
+	}
+	if channel == "" {
+		return nil, errors.New("query: listen needs a channel")
+	}
+	conn, err := r.listen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Exec(ctx, "listen "+quoteChannel(channel)); err != nil {
+		conn.Close(context.WithoutCancel(ctx))
+		return nil, err
+	}
+
+	notifications := make(chan Notification)
+	go func() {
+		defer close(notifications)
+		defer conn.Close(context.WithoutCancel(ctx))
+		for {
+			n, err := conn.WaitForNotification(ctx)
+			if err != nil {
+				return
+			}
+			if n.Channel != channel {
+				continue
+			}
+			select {
+			case notifications <- n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return notifications, nil
+}
+
+// quoteChannel quotes a channel name, which LISTEN takes as an identifier
+func quoteChannel(channel string) string {
+	return `"` + strings.ReplaceAll(channel, `"`, `""`) + `"`
+}
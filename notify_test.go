@@ -0,0 +1,87 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeListener struct {
+	execs    []string
+	incoming chan Notification
+	closed   chan struct{}
+}
+
+func (l *fakeListener) Exec(ctx context.Context, sql string) error {
+	l.execs = append(l.execs, sql)
+	return nil
+}
+
+func (l *fakeListener) WaitForNotification(ctx context.Context) (Notification, error) {
+	select {
+	case n, ok := <-l.incoming:
+		if !ok {
+			return Notification{}, errors.New("connection closed")
+		}
+		return n, nil
+	case <-ctx.Done():
+		return Notification{}, ctx.Err()
+	}
+}
+
+func (l *fakeListener) Close(ctx context.Context) error {
+	close(l.closed)
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	query := Notify("orders", `{"id":1}`).Build()
+
+	expectedSQL := "select pg_notify($1, $2)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[0] != "orders" || query.Params[1] != `{"id":1}` {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+	if _, err := Notify("", "x").TryBuild(); err == nil {
+		t.Error("Expected an error without a channel")
+	}
+}
+
+func TestListen(t *testing.T) {
+	listener := &fakeListener{incoming: make(chan Notification, 2), closed: make(chan struct{})}
+	db, _ := newFakeDB(t)
+	runner := NewRunner(db, ListenWith(func(ctx context.Context) (Listener, error) {
+		return listener, nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	notifications, err := runner.Listen(ctx, `order"events`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(listener.execs) != 1 || listener.execs[0] != `listen "order""events"` {
+		t.Errorf("Unexpected statements: %v", listener.execs)
+	}
+
+	listener.incoming <- Notification{Channel: "other", Payload: "skip"}
+	listener.incoming <- Notification{Channel: `order"events`, Payload: "42", PID: 7}
+	n := <-notifications
+	if n.Payload != "42" || n.PID != 7 {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+
+	cancel()
+	if _, ok := <-notifications; ok {
+		t.Error("Expected the channel to close")
+	}
+	<-listener.closed
+}
+
+func TestListenWithoutOption(t *testing.T) {
+	db, _ := newFakeDB(t)
+	if _, err := NewRunner(db).Listen(context.Background(), "orders"); err == nil {
+		t.Error("Expected an error without ListenWith")
+	}
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+type queryTrackerKey struct{}
+
+type queryTracker struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// WithQueryTracker returns a context that scopes N+1 detection, typically
+// one per incoming request. Queries run through a Runner with
+// DetectNPlusOne are counted per tracker.
+func WithQueryTracker(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryTrackerKey{}, &queryTracker{counts: map[string]int{}})
+}
+
+// DetectNPlusOne logs a warning, naming the calling code, when the same
+// parameterized query runs more than threshold times within one
+// WithQueryTracker context. Intended for development.
+func DetectNPlusOne(threshold int, logger Logger) RunnerOption {
+	return func(r *Runner) {
+		r.nPlusOneThreshold = threshold
+		r.nPlusOneLogger = logger
+	}
+}
+
+func (r *Runner) trackNPlusOne(ctx context.Context, q Query) {
+	if r.nPlusOneLogger == nil {
+		return
+	}
+	tracker, ok := ctx.Value(queryTrackerKey{}).(*queryTracker)
+	if !ok {
+		return
+	}
+
+	normalized := q.Normalize()
+	tracker.mu.Lock()
+	tracker.counts[normalized.Hash]++
+	count := tracker.counts[normalized.Hash]
+	tracker.mu.Unlock()
+
+	// Warn once, when the threshold is first crossed
+	if count == r.nPlusOneThreshold+1 {
+		r.nPlusOneLogger.Printf("query: possible N+1, query ran %d times from %s: %s", count, caller(), normalized.SQL)
+	}
+}
+
+// caller returns the first stack frame outside this package
+func caller() string {
+	_, self, _, _ := runtime.Caller(0)
+	dir := filepath.Dir(self)
+
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != dir || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestDetectNPlusOne(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select * from posts where user_id = $1", []string{"id"})
+
+	logger := &recordingLogger{}
+	runner := NewRunner(db, DetectNPlusOne(3, logger))
+	ctx := WithQueryTracker(context.Background())
+
+	for userID := 1; userID <= 5; userID++ {
+		rows, err := runner.Query(ctx, NewQueryBuilder().Table("posts").Where("user_id", "=", userID))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rows.Close()
+	}
+
+	if len(logger.messages) != 1 {
+		t.Fatalf("Expected one warning, got: %v", logger.messages)
+	}
+	if !strings.Contains(logger.messages[0], "nplusone_test.go") || !strings.Contains(logger.messages[0], "select * from posts where user_id = $1") {
+		t.Errorf("Expected warning naming caller and query, got: %s", logger.messages[0])
+	}
+}
+
+func TestDetectNPlusOneIsScopedToTracker(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select * from posts where user_id = $1", []string{"id"}, []driver.Value{int64(1)})
+
+	logger := &recordingLogger{}
+	runner := NewRunner(db, DetectNPlusOne(1, logger))
+
+	for i := 0; i < 3; i++ {
+		ctx := WithQueryTracker(context.Background())
+		rows, err := runner.Query(ctx, NewQueryBuilder().Table("posts").Where("user_id", "=", i))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rows.Close()
+	}
+
+	if len(logger.messages) != 0 {
+		t.Errorf("Expected no warnings across separate trackers, got: %v", logger.messages)
+	}
+}
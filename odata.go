@@ -0,0 +1,270 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var odataOperators = map[string]string{
+	"eq": "=",
+	"ne": "<>",
+	"gt": ">",
+	"ge": ">=",
+	"lt": "<",
+	"le": "<=",
+}
+
+// WhereOData adds the conditions of an OData $filter expression such as
+// "age gt 18 and startswith(name,'Jo')". Supported are the comparison
+// operators eq, ne, gt, ge, lt and le, the functions startswith, endswith
+// and contains, and/or with parentheses, and string (quotes doubled to
+// escape), number and boolean literals. Fields are looked up in allowed
+// (public name to column) and values are always bound. Anything else is
+// reported by TryBuild as ErrInvalidFilter.
+func (b *QueryBuilder) WhereOData(filter string, allowed map[string]string) *QueryBuilder {
+	if strings.TrimSpace(filter) == "" {
+		return b
+	}
+	tokens, err := odataTokens(filter)
+	if err != nil {
+		return b.fail(err)
+	}
+	p := &odataParser{tokens: tokens, allowed: allowed}
+	clauses, err := p.or(0)
+	if err != nil {
+		return b.fail(err)
+	}
+	if p.pos < len(p.tokens) {
+		return b.fail(fmt.Errorf("%w: unexpected %q", ErrInvalidFilter, p.tokens[p.pos].text))
+	}
+	b.whereClauses = append(b.whereClauses, documentGroup(clauses, "and"))
+	return b
+}
+
+type odataToken struct {
+	text   string
+	quoted bool
+}
+
+func odataTokens(filter string) ([]odataToken, error) {
+	var tokens []odataToken
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, odataToken{text: string(r)})
+			i++
+		case r == '\'':
+			var s strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("%w: unterminated string", ErrInvalidFilter)
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						s.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				s.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, odataToken{text: s.String(), quoted: true})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("(),'", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, odataToken{text: string(runes[start:i])})
+		}
+	}
+	return tokens, nil
+}
+
+type odataParser struct {
+	tokens  []odataToken
+	pos     int
+	allowed map[string]string
+}
+
+func (p *odataParser) peek() (odataToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return odataToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// keyword consumes the next token if it is the unquoted word
+func (p *odataParser) keyword(word string) bool {
+	t, ok := p.peek()
+	if ok && !t.quoted && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *odataParser) expect(text string) error {
+	if !p.keyword(text) {
+		return p.unexpected("expected " + strconv.Quote(text))
+	}
+	return nil
+}
+
+func (p *odataParser) unexpected(what string) error {
+	if t, ok := p.peek(); ok {
+		return fmt.Errorf("%w: %s, got %q", ErrInvalidFilter, what, t.text)
+	}
+	return fmt.Errorf("%w: %s at end of filter", ErrInvalidFilter, what)
+}
+
+func (p *odataParser) or(depth int) ([]*WhereClause, error) {
+	return parseJoined(p.keyword, "or", func() ([]*WhereClause, error) {
+		return parseJoined(p.keyword, "and", func() ([]*WhereClause, error) {
+			return p.primary(depth)
+		})
+	})
+}
+
+func (p *odataParser) primary(depth int) ([]*WhereClause, error) {
+	if p.keyword("(") {
+		if depth >= maxDocumentDepth {
+			return nil, fmt.Errorf("%w: filter nested deeper than %d", ErrInvalidFilter, maxDocumentDepth)
+		}
+		clauses, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return clauses, p.expect(")")
+	}
+
+	t, ok := p.peek()
+	if !ok || t.quoted {
+		return nil, p.unexpected("expected a field or function")
+	}
+	p.pos++
+
+	switch name := strings.ToLower(t.text); name {
+	case "startswith", "endswith", "contains":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		column, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		value, ok := p.peek()
+		if !ok || !value.quoted {
+			return nil, p.unexpected(name + " needs a string")
+		}
+		p.pos++
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		pattern := EscapeLike(value.text)
+		switch name {
+		case "startswith":
+			pattern += "%"
+		case "endswith":
+			pattern = "%" + pattern
+		default:
+			pattern = "%" + pattern + "%"
+		}
+		return []*WhereClause{{Column: column, Operator: "like", Value: pattern, JoinType: "and"}}, nil
+	}
+
+	p.pos--
+	column, err := p.field()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.peek()
+	operator, known := odataOperators[strings.ToLower(op.text)]
+	if !ok || op.quoted || !known {
+		return nil, p.unexpected("expected a comparison operator")
+	}
+	p.pos++
+	value, err := p.literal()
+	if err != nil {
+		return nil, err
+	}
+	return []*WhereClause{{Column: column, Operator: operator, Value: value, JoinType: "and"}}, nil
+}
+
+func (p *odataParser) field() (string, error) {
+	t, ok := p.peek()
+	if !ok || t.quoted {
+		return "", p.unexpected("expected a field")
+	}
+	column, known := p.allowed[t.text]
+	if !known {
+		return "", fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, t.text)
+	}
+	p.pos++
+	return column, nil
+}
+
+func (p *odataParser) literal() (interface{}, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, p.unexpected("expected a value")
+	}
+	p.pos++
+	if t.quoted {
+		return t.text, nil
+	}
+	switch strings.ToLower(t.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if value, ok := parseNumber(t.text); ok {
+		return value, nil
+	}
+	p.pos--
+	return nil, p.unexpected("expected a string, number or boolean")
+}
+
+// parseJoined parses "item (sep item)*", joining the items with sep and
+// parenthesizing any that have several conditions
+func parseJoined(accept func(string) bool, sep string, item func() ([]*WhereClause, error)) ([]*WhereClause, error) {
+	var clauses []*WhereClause
+	for {
+		sub, err := item()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, documentGroup(sub, sep))
+		if !accept(sep) {
+			return clauses, nil
+		}
+	}
+}
+
+var numberPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// parseNumber reads an integer or decimal literal
+func parseNumber(s string) (interface{}, bool) {
+	if !numberPattern.MatchString(s) {
+		return nil, false
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
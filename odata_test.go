@@ -0,0 +1,63 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+var odataFields = map[string]string{
+	"age":    "age",
+	"name":   "name",
+	"status": "status",
+	"vip":    "vip",
+}
+
+func TestWhereOData(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		WhereOData("age gt 18 and startswith(name,'Jo')", odataFields).
+		Build()
+
+	expectedSQL := "select * from users where (age > $1 and name like $2)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[0] != int64(18) || query.Params[1] != "Jo%" {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestWhereODataPrecedence(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		WhereOData("status eq 'it''s' or vip eq true and (age le 1.5 or contains(name,'_'))", odataFields).
+		Build()
+
+	expectedSQL := "select * from users where (status = $1 or (vip = $2 and (age <= $3 or name like $4)))"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[0] != "it's" || query.Params[2] != 1.5 || query.Params[3] != `%\_%` {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestWhereODataErrors(t *testing.T) {
+	filters := []string{
+		"password eq 'x'",
+		"age between 1",
+		"age eq",
+		"age eq 1 and",
+		"(age eq 1",
+		"age eq 1)",
+		"startswith(name, 1)",
+		"name eq 'open",
+		"age eq null",
+	}
+	for _, filter := range filters {
+		_, err := NewQueryBuilder().Table("users").WhereOData(filter, odataFields).TryBuild()
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s: expected ErrInvalidFilter, got: %v", filter, err)
+		}
+	}
+}
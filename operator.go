@@ -0,0 +1,58 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownOperator is returned by TryBuild in strict identifier mode
+// when a where or having operator is not one the dialect knows
+var ErrUnknownOperator = errors.New("unknown operator")
+
+var commonOperators = []string{
+	"=", "<>", "!=", "<", "<=", ">", ">=",
+	"like", "not like", "in", "not in", "is", "is not",
+}
+
+var dialectOperators = map[Dialect][]string{
+	Postgres: {
+		"ilike", "not ilike", "similar to", "not similar to",
+		"~", "~*", "!~", "!~*", "@>", "<@", "&&", "?", "?|", "?&", "@@",
+		"is distinct from", "is not distinct from",
+	},
+	MySQL: {"<=>", "regexp", "not regexp", "rlike", "not rlike", "sounds like"},
+	DuckDB: {
+		"ilike", "not ilike", "similar to", "not similar to", "glob",
+		"~", "@>", "<@", "&&", "is distinct from", "is not distinct from",
+	},
+}
+
+// cqlOperators replaces commonOperators for CQL
+var cqlOperators = []string{"=", "<", "<=", ">", ">=", "in", "contains", "contains key", "like"}
+
+// SupportsOperator reports whether the database accepts op as a where
+// comparison. Case and spacing are ignored: "NOT  LIKE" is "not like".
+// DefaultDialect knows only the operators every database shares.
+func (d Dialect) SupportsOperator(op string) bool {
+	op = strings.ToLower(strings.Join(strings.Fields(op), " "))
+	if d == CQL {
+		return slices.Contains(cqlOperators, op)
+	}
+	return slices.Contains(commonOperators, op) || slices.Contains(dialectOperators[d], op)
+}
+
+// validateOperators checks the operators of where and having conditions;
+// conditions on subqueries, raw conditions and groups have none
+func (b *QueryBuilder) validateOperators() error {
+	var err error
+	check := func(where *WhereClause) {
+		if err == nil && where.Column != "" && where.Subquery == nil && !b.target().SupportsOperator(where.Operator) {
+			err = fmt.Errorf("%w: %q on %s", ErrUnknownOperator, where.Operator, b.target())
+		}
+	}
+	walkWheres(b.whereClauses, check)
+	walkWheres(b.having, check)
+	return err
+}
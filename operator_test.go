@@ -0,0 +1,65 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrictOperators(t *testing.T) {
+	query, err := NewQueryBuilder().Dialect(Postgres).Table("users").
+		StrictIdentifiers().
+		Where("name", "ILIKE", "a%").
+		WhereGroup(func(q *QueryBuilder) {
+			q.Where("tags", "@>", "{admin}").OrWhere("deleted_at", "is not", nil)
+		}).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := "select * from users where name ILIKE $1 and (tags @> $2 or deleted_at is not $3)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	tests := []*QueryBuilder{
+		NewQueryBuilder().Table("users").StrictIdentifiers().Where("id", "= 1 or 1 =", 1),
+		NewQueryBuilder().Dialect(MySQL).Table("users").StrictIdentifiers().Where("name", "ilike", "a%"),
+		NewQueryBuilder().Dialect(Postgres).Table("users").StrictIdentifiers().
+			WhereGroup(func(q *QueryBuilder) { q.Where("id", "=", 1).OrWhere("id", "<=>", 2) }),
+		NewQueryBuilder().Table("orders").StrictIdentifiers().
+			Select("user_id").GroupBy("user_id").Having("count(*)", "; drop", 1),
+	}
+	for _, qb := range tests {
+		if _, err := qb.TryBuild(); !errors.Is(err, ErrUnknownOperator) {
+			t.Errorf("Expected ErrUnknownOperator, got: %v", err)
+		}
+	}
+
+	// without strict mode the operator is written as given
+	if _, err := NewQueryBuilder().Dialect(MySQL).Table("users").Where("name", "ilike", "a%").TryBuild(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestSupportsOperator(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		op      string
+		want    bool
+	}{
+		{Postgres, "NOT  ILIKE", true},
+		{Postgres, "regexp", false},
+		{MySQL, "<=>", true},
+		{MySQL, "ilike", false},
+		{DuckDB, "glob", true},
+		{CQL, "contains key", true},
+		{CQL, "<>", false},
+		{DefaultDialect, "like", true},
+		{DefaultDialect, "ilike", false},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.SupportsOperator(tt.op); got != tt.want {
+			t.Errorf("%s.SupportsOperator(%q) = %v, want %v", tt.dialect, tt.op, got, tt.want)
+		}
+	}
+}
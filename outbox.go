@@ -0,0 +1,118 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Event is a message written to an outbox table. Payload is stored as
+// JSON: strings, []byte and json.RawMessage as they are, anything else
+// encoded with encoding/json. Headers are stored as a JSON object.
+type Event struct {
+	Topic   string
+	Payload interface{}
+	Headers map[string]string
+}
+
+// Outbox runs write builders together with the events announcing them,
+// for the transactional outbox pattern: a relay process later reads the
+// table and publishes the events, which exist exactly when the write
+// committed
+type Outbox struct {
+	runner *Runner
+	table  string
+}
+
+// WithOutbox returns an Outbox writing events to table, which needs topic,
+// payload and headers columns (text or jsonb for the last two)
+func (r *Runner) WithOutbox(table string) *Outbox {
+	return &Outbox{runner: r, table: table}
+}
+
+// Exec runs qb and inserts one outbox row per event in the same
+// transaction: the Runner's own when it is bound to a *sql.Tx, otherwise
+// a new one on the DB qb is routed to. The result is qb's.
+func (o *Outbox) Exec(ctx context.Context, qb Builder, events ...Event) (sql.Result, error) {
+	insert, err := o.insert(qb, events)
+	if err != nil {
+		return nil, err
+	}
+
+	var result sql.Result
+	run := func(tx *Runner) error {
+		var err error
+		if result, err = tx.Exec(ctx, qb); err != nil {
+			return err
+		}
+		if insert == nil {
+			return nil
+		}
+		_, err = tx.Exec(ctx, insert)
+		return err
+	}
+
+	runner := o.runner.routed(qb)
+	if _, ok := runner.db.(*sql.Tx); ok {
+		err = run(runner)
+	} else {
+		err = runner.RunInTx(ctx, TxOptions{}, run)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// insert builds the outbox insert for events, in the dialect of qb
+func (o *Outbox) insert(qb Builder, events []Event) (*QueryBuilder, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	insert := NewQueryBuilder()
+	if b, ok := qb.(*QueryBuilder); ok {
+		insert.paramStyle = b.paramStyle
+		insert.dialect = b.dialect
+		insert.quoteStyle = b.quoteStyle
+		insert.keywordCase = b.keywordCase
+	}
+	insert.Table(o.table).InsertColumns("topic", "payload", "headers")
+
+	for _, event := range events {
+		if event.Topic == "" {
+			return nil, errors.New("query: outbox event without a topic")
+		}
+		payload, err := eventJSON(event.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("query: outbox event %s: %w", event.Topic, err)
+		}
+		headers := "{}"
+		if len(event.Headers) > 0 {
+			data, err := json.Marshal(event.Headers)
+			if err != nil {
+				return nil, err
+			}
+			headers = string(data)
+		}
+		insert.AddRow(event.Topic, payload, headers)
+	}
+	return insert, nil
+}
+
+func eventJSON(payload interface{}) (string, error) {
+	switch p := payload.(type) {
+	case string:
+		return p, nil
+	case []byte:
+		return string(p), nil
+	case json.RawMessage:
+		return string(p), nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
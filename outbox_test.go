@@ -0,0 +1,67 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOutbox(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.OnExec("update orders set status = $1 where id = $2", 1)
+
+	outbox := NewRunner(db).WithOutbox("outbox")
+	result, err := outbox.Exec(context.Background(),
+		NewQueryBuilder().Table("orders").Set("status", "paid").Where("id", "=", 7),
+		Event{Topic: "order.paid", Payload: map[string]int{"id": 7}, Headers: map[string]string{"trace": "abc"}},
+		Event{Topic: "audit", Payload: "raw"},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n != 1 {
+		t.Errorf("Expected the update's result, got %d rows", n)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected the update and the outbox insert, got: %v", calls)
+	}
+	expectedSQL := "insert into outbox (topic, payload, headers) values ($1, $2, $3), ($4, $5, $6)"
+	if calls[1].SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, calls[1].SQL)
+	}
+	expectedArgs := []interface{}{"order.paid", `{"id":7}`, `{"trace":"abc"}`, "audit", "raw", "{}"}
+	for i, arg := range calls[1].Args {
+		if arg != expectedArgs[i] {
+			t.Errorf("Expected arg %d: %v, got: %v", i+1, expectedArgs[i], arg)
+		}
+	}
+	if txs := fake.Txs(); len(txs) != 1 || !txs[0].Committed {
+		t.Errorf("Expected one committed transaction, got: %+v", txs)
+	}
+}
+
+func TestOutboxRollback(t *testing.T) {
+	db, fake := newFakeDB(t)
+	failure := errors.New("constraint violated")
+	fake.OnError("delete from orders where id = ?", failure)
+
+	_, err := NewRunner(db).WithOutbox("outbox").Exec(context.Background(),
+		NewQueryBuilder().Dialect(MySQL).Table("orders").Delete().Where("id", "=", 7),
+		Event{Topic: "order.deleted", Payload: map[string]int{"id": 7}},
+	)
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the delete error, got: %v", err)
+	}
+	if calls := fake.Calls(); len(calls) != 1 {
+		t.Errorf("Expected no outbox insert, got: %v", calls)
+	}
+	if txs := fake.Txs(); len(txs) != 1 || !txs[0].RolledBack {
+		t.Errorf("Expected the transaction rolled back, got: %+v", txs)
+	}
+
+	if _, err := NewRunner(db).WithOutbox("outbox").Exec(context.Background(), NewQueryBuilder().Table("orders").Delete(), Event{}); err == nil {
+		t.Error("Expected an error for an event without a topic")
+	}
+}
@@ -0,0 +1,121 @@
+package query
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// ErrInvalidPageToken is returned by ParsePageToken for tokens it did not issue
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// Paginator describes one page of results. Paginate fills in the request
+// side; Observe completes it once the rows have been fetched.
+type Paginator struct {
+	Page    int
+	PerPage int
+
+	// Set by Observe
+	HasMore bool
+	From    int // 1-based index of the first row on the page, 0 if empty
+	To      int // 1-based index of the last row on the page, 0 if empty
+}
+
+// Paginate limits b to the given 1-based page and returns its Paginator.
+// One extra row is requested so HasMore can be answered without a count
+// query; pass the number of rows fetched to Observe to find how many to keep.
+func (b *QueryBuilder) Paginate(page, perPage int) *Paginator {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 1
+	}
+	b.Limit(perPage + 1).Offset((page - 1) * perPage)
+	return &Paginator{Page: page, PerPage: perPage}
+}
+
+// Observe records that fetched rows came back and returns how many of them
+// belong on the page
+func (p *Paginator) Observe(fetched int) int {
+	p.HasMore = fetched > p.PerPage
+	n := min(fetched, p.PerPage)
+	p.From, p.To = 0, 0
+	if n > 0 {
+		p.From = (p.Page-1)*p.PerPage + 1
+		p.To = p.From + n - 1
+	}
+	return n
+}
+
+// NextPage returns the following page number, if there is one
+func (p *Paginator) NextPage() (int, bool) {
+	return p.Page + 1, p.HasMore
+}
+
+// PrevPage returns the preceding page number, if there is one
+func (p *Paginator) PrevPage() (int, bool) {
+	return p.Page - 1, p.Page > 1
+}
+
+// NextToken returns an opaque token for the next page, or "" on the last page
+func (p *Paginator) NextToken() string {
+	if page, ok := p.NextPage(); ok {
+		return pageToken(page)
+	}
+	return ""
+}
+
+// PrevToken returns an opaque token for the previous page, or "" on the first page
+func (p *Paginator) PrevToken() string {
+	if page, ok := p.PrevPage(); ok {
+		return pageToken(page)
+	}
+	return ""
+}
+
+// NextURL returns u with param set to the next page number, or "" on the last page
+func (p *Paginator) NextURL(u *url.URL, param string) string {
+	if page, ok := p.NextPage(); ok {
+		return pageURL(u, param, page)
+	}
+	return ""
+}
+
+// PrevURL returns u with param set to the previous page number, or "" on the first page
+func (p *Paginator) PrevURL(u *url.URL, param string) string {
+	if page, ok := p.PrevPage(); ok {
+		return pageURL(u, param, page)
+	}
+	return ""
+}
+
+// ParsePageToken returns the page number encoded by NextToken or PrevToken.
+// An empty token is the first page.
+func ParsePageToken(token string) (int, error) {
+	if token == "" {
+		return 1, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	page, err := strconv.Atoi(string(raw))
+	if err != nil || page < 1 {
+		return 0, ErrInvalidPageToken
+	}
+	return page, nil
+}
+
+func pageToken(page int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(page)))
+}
+
+func pageURL(u *url.URL, param string, page int) string {
+	next := *u
+	values := next.Query()
+	values.Set(param, strconv.Itoa(page))
+	next.RawQuery = values.Encode()
+	return next.String()
+}
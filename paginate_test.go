@@ -0,0 +1,68 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	qb := NewQueryBuilder().Table("posts").OrderBy("id")
+	p := qb.Paginate(3, 20)
+	query := qb.Build()
+
+	expectedSQL := "select * from posts order by id limit 21 offset 40"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if keep := p.Observe(21); keep != 20 {
+		t.Errorf("Expected to keep 20 rows, got: %d", keep)
+	}
+	if !p.HasMore || p.From != 41 || p.To != 60 {
+		t.Errorf("Unexpected paginator state: %+v", p)
+	}
+
+	u, _ := url.Parse("https://example.com/posts?sort=-id&page=3")
+	if next := p.NextURL(u, "page"); next != "https://example.com/posts?page=4&sort=-id" {
+		t.Errorf("Unexpected next URL: %s", next)
+	}
+	if prev := p.PrevURL(u, "page"); prev != "https://example.com/posts?page=2&sort=-id" {
+		t.Errorf("Unexpected prev URL: %s", prev)
+	}
+
+	page, err := ParsePageToken(p.NextToken())
+	if err != nil || page != 4 {
+		t.Errorf("Expected next token to decode to page 4, got: %d, %v", page, err)
+	}
+}
+
+func TestPaginateLastPage(t *testing.T) {
+	p := NewQueryBuilder().Table("posts").Paginate(1, 10)
+
+	if keep := p.Observe(4); keep != 4 {
+		t.Errorf("Expected to keep 4 rows, got: %d", keep)
+	}
+	if p.HasMore || p.From != 1 || p.To != 4 {
+		t.Errorf("Unexpected paginator state: %+v", p)
+	}
+	if p.NextToken() != "" || p.PrevToken() != "" {
+		t.Errorf("Expected no tokens on a single page, got: %q, %q", p.NextToken(), p.PrevToken())
+	}
+
+	p.Observe(0)
+	if p.From != 0 || p.To != 0 {
+		t.Errorf("Expected empty range, got: %d-%d", p.From, p.To)
+	}
+}
+
+func TestParsePageToken(t *testing.T) {
+	if page, err := ParsePageToken(""); err != nil || page != 1 {
+		t.Errorf("Expected empty token to be page 1, got: %d, %v", page, err)
+	}
+	for _, token := range []string{"!!", pageToken(0), "YWJj"} {
+		if _, err := ParsePageToken(token); !errors.Is(err, ErrInvalidPageToken) {
+			t.Errorf("Expected ErrInvalidPageToken for %q, got: %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,780 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedSQL is returned by Parse for statements outside the subset
+// it understands
+var ErrUnsupportedSQL = errors.New("unsupported SQL")
+
+// Parse turns a SQL statement back into a builder, for migrating raw-SQL
+// code and for round-trip testing of generated queries. args are the
+// statement's bound parameters, consumed by ? placeholders in order or by
+// $n placeholders by position. The supported subset is what the builder
+// itself generates:
+//
+//   - select columns from table [as alias] with joins, where, group by,
+//     order by, limit and offset
+//   - insert into table (columns) values (...), (...)
+//   - update table set column = value, ... where ...
+//   - delete from table where ...
+//
+// Conditions are comparisons (=, <>, !=, <, <=, >, >=, like, ilike, not
+// like, in, not in) of a column with a literal or placeholder, is [not]
+// null, and parenthesized and/or groups. A comparison between two columns
+// is kept as a raw condition. Anything else fails with ErrUnsupportedSQL.
+func Parse(sql string, args ...interface{}) (*QueryBuilder, error) {
+	tokens, err := sqlTokens(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{sql: sql, tokens: tokens, args: args}
+	b, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept(";"); p.pos < len(p.tokens) {
+		return nil, p.unexpected("end of statement")
+	}
+	if used := p.used(); used != len(args) {
+		return nil, fmt.Errorf("%w: statement has %d placeholders for %d args", ErrUnsupportedSQL, used, len(args))
+	}
+	return b, nil
+}
+
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlIdent
+	sqlString
+	sqlNumber
+	sqlPlaceholder
+	sqlSymbol
+)
+
+type sqlToken struct {
+	kind       sqlTokenKind
+	text       string
+	start, end int
+}
+
+func sqlTokens(sql string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case c == '\'':
+			var s strings.Builder
+			for i++; ; i++ {
+				if i >= len(sql) {
+					return nil, fmt.Errorf("%w: unterminated string", ErrUnsupportedSQL)
+				}
+				if sql[i] == '\'' {
+					if i+1 < len(sql) && sql[i+1] == '\'' {
+						s.WriteByte('\'')
+						i++
+						continue
+					}
+					break
+				}
+				s.WriteByte(sql[i])
+			}
+			i++
+			tokens = append(tokens, sqlToken{kind: sqlString, text: s.String(), start: start, end: i})
+			continue
+		case c == '"' || c == '`' || c == '[':
+			closing := map[byte]byte{'"': '"', '`': '`', '[': ']'}[c]
+			end := strings.IndexByte(sql[i+1:], closing)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated identifier", ErrUnsupportedSQL)
+			}
+			i += end + 2
+			tokens = append(tokens, sqlToken{kind: sqlIdent, text: sql[start+1 : i-1], start: start, end: i})
+			continue
+		case c == '?':
+			i++
+			tokens = append(tokens, sqlToken{kind: sqlPlaceholder, text: "?", start: start, end: i})
+			continue
+		case c == '$' && i+1 < len(sql) && isDigit(sql[i+1]):
+			for i++; i < len(sql) && isDigit(sql[i]); i++ {
+			}
+			tokens = append(tokens, sqlToken{kind: sqlPlaceholder, text: sql[start:i], start: start, end: i})
+			continue
+		case isDigit(c) || (c == '.' && i+1 < len(sql) && isDigit(sql[i+1])):
+			for i < len(sql) && (isDigit(sql[i]) || sql[i] == '.' || sql[i] == 'e' || sql[i] == 'E' ||
+				((sql[i] == '+' || sql[i] == '-') && (sql[i-1] == 'e' || sql[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlNumber, text: sql[start:i], start: start, end: i})
+			continue
+		case isWordByte(c):
+			for i < len(sql) && (isWordByte(sql[i]) || isDigit(sql[i]) || sql[i] == '.' ||
+				(sql[i] == '*' && sql[i-1] == '.')) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlWord, text: sql[start:i], start: start, end: i})
+			continue
+		}
+
+		for _, symbol := range []string{"<>", "!=", "<=", ">=", "::", "||"} {
+			if strings.HasPrefix(sql[i:], symbol) {
+				i += len(symbol)
+				break
+			}
+		}
+		if i == start {
+			i++
+		}
+		tokens = append(tokens, sqlToken{kind: sqlSymbol, text: sql[start:i], start: start, end: i})
+	}
+	return tokens, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+type sqlParser struct {
+	sql    string
+	tokens []sqlToken
+	pos    int
+
+	args       []interface{}
+	next       int // next ? placeholder
+	maxDollar  int // highest $n placeholder
+	style      ParameterStyle
+	styleFixed bool
+}
+
+func (p *sqlParser) peek() (sqlToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return sqlToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// is reports whether the next token is the keyword or symbol
+func (p *sqlParser) is(text string) bool {
+	t, ok := p.peek()
+	return ok && (t.kind == sqlWord || t.kind == sqlSymbol) && strings.EqualFold(t.text, text)
+}
+
+// accept consumes the next token if it is the keyword or symbol
+func (p *sqlParser) accept(text string) bool {
+	if p.is(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expect(texts ...string) error {
+	for _, text := range texts {
+		if !p.accept(text) {
+			return p.unexpected(strconv.Quote(text))
+		}
+	}
+	return nil
+}
+
+func (p *sqlParser) unexpected(want string) error {
+	if t, ok := p.peek(); ok {
+		return fmt.Errorf("%w: expected %s at offset %d, got %q", ErrUnsupportedSQL, want, t.start, t.text)
+	}
+	return fmt.Errorf("%w: expected %s at end of statement", ErrUnsupportedSQL, want)
+}
+
+// identifier consumes a table, column or alias name
+func (p *sqlParser) identifier() (string, error) {
+	t, ok := p.peek()
+	if !ok || (t.kind != sqlWord && t.kind != sqlIdent) || (t.kind == sqlWord && sqlReserved[strings.ToLower(t.text)]) {
+		return "", p.unexpected("identifier")
+	}
+	p.pos++
+	return t.text, nil
+}
+
+var sqlReserved = map[string]bool{
+	"select": true, "from": true, "where": true, "group": true, "order": true,
+	"limit": true, "offset": true, "join": true, "inner": true, "left": true,
+	"right": true, "full": true, "cross": true, "on": true, "and": true,
+	"or": true, "not": true, "set": true, "values": true, "as": true,
+	"having": true, "union": true, "for": true, "by": true, "in": true,
+	"is": true, "like": true, "ilike": true, "null": true, "returning": true,
+}
+
+func (p *sqlParser) statement() (*QueryBuilder, error) {
+	switch {
+	case p.accept("select"):
+		return p.selectStatement()
+	case p.accept("insert"):
+		return p.insertStatement()
+	case p.accept("update"):
+		return p.updateStatement()
+	case p.accept("delete"):
+		return p.deleteStatement()
+	default:
+		return nil, p.unexpected("select, insert, update or delete")
+	}
+}
+
+// finish applies the placeholder style seen while parsing
+func (p *sqlParser) finish(b *QueryBuilder) *QueryBuilder {
+	if p.styleFixed {
+		b.paramStyle = p.style
+	}
+	return b
+}
+
+func (p *sqlParser) selectStatement() (*QueryBuilder, error) {
+	b := NewQueryBuilder()
+
+	var items []string
+	simple := true
+	for {
+		start := p.pos
+		depth := 0
+		for p.pos < len(p.tokens) {
+			if depth == 0 && (p.is(",") || p.is("from")) {
+				break
+			}
+			switch {
+			case p.is("("):
+				depth++
+			case p.is(")"):
+				depth--
+			}
+			if p.tokens[p.pos].kind == sqlPlaceholder {
+				return nil, fmt.Errorf("%w: placeholder in select list", ErrUnsupportedSQL)
+			}
+			p.pos++
+		}
+		if p.pos == start {
+			return nil, p.unexpected("select expression")
+		}
+		item := p.text(start, p.pos)
+		items = append(items, item)
+		simple = simple && isSelectColumn(item)
+		if !p.accept(",") {
+			break
+		}
+	}
+	// Expressions keep the whole list raw so its order is preserved
+	if simple {
+		b.Select(items...)
+	} else {
+		for _, item := range items {
+			b.SelectRaw(strings.ReplaceAll(item, "?", "??"))
+		}
+	}
+
+	if err := p.expect("from"); err != nil {
+		return nil, err
+	}
+	table, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	b.Table(table)
+	if alias, ok, err := p.alias(); err != nil {
+		return nil, err
+	} else if ok {
+		b.As(alias)
+	}
+
+	if err := p.joins(b); err != nil {
+		return nil, err
+	}
+	if err := p.where(b); err != nil {
+		return nil, err
+	}
+
+	if p.accept("group") {
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		var columns []string
+		for {
+			column, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, column)
+			if !p.accept(",") {
+				break
+			}
+		}
+		b.GroupBy(columns...)
+	}
+
+	if p.accept("order") {
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		var terms []string
+		for {
+			column, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			if p.accept("asc") {
+				column += " asc"
+			} else if p.accept("desc") {
+				column += " desc"
+			}
+			terms = append(terms, column)
+			if !p.accept(",") {
+				break
+			}
+		}
+		b.OrderBy(strings.Join(terms, ", "))
+	}
+
+	if p.accept("limit") {
+		n, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		b.Limit(n)
+	}
+	if p.accept("offset") {
+		n, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		b.Offset(n)
+	}
+	return p.finish(b), nil
+}
+
+// text returns the source of tokens [from, to)
+func (p *sqlParser) text(from, to int) string {
+	return p.sql[p.tokens[from].start:p.tokens[to-1].end]
+}
+
+// alias consumes an optional "[as] alias"
+func (p *sqlParser) alias() (string, bool, error) {
+	if p.accept("as") {
+		alias, err := p.identifier()
+		return alias, true, err
+	}
+	if t, ok := p.peek(); ok && (t.kind == sqlIdent || (t.kind == sqlWord && !sqlReserved[strings.ToLower(t.text)])) {
+		p.pos++
+		return t.text, true, nil
+	}
+	return "", false, nil
+}
+
+func (p *sqlParser) joins(b *QueryBuilder) error {
+	for {
+		var joinType string
+		switch {
+		case p.accept("join"):
+			joinType = "JOIN"
+		case p.is("inner"), p.is("left"), p.is("right"), p.is("full"):
+			joinType = strings.ToUpper(p.tokens[p.pos].text) + " JOIN"
+			p.pos++
+			p.accept("outer")
+			if err := p.expect("join"); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+
+		table, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		alias, _, err := p.alias()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("on"); err != nil {
+			return err
+		}
+
+		start := p.pos
+		depth := 0
+		for p.pos < len(p.tokens) {
+			if depth == 0 && p.joinEnd() {
+				break
+			}
+			switch {
+			case p.is("("):
+				depth++
+			case p.is(")"):
+				depth--
+			}
+			if p.tokens[p.pos].kind == sqlPlaceholder {
+				return fmt.Errorf("%w: placeholder in join condition", ErrUnsupportedSQL)
+			}
+			p.pos++
+		}
+		if p.pos == start {
+			return p.unexpected("join condition")
+		}
+		var condition interface{} = p.text(start, p.pos)
+		if _, err := parseJoinCondition(p.text(start, p.pos)); err != nil {
+			condition = Raw(p.text(start, p.pos))
+		}
+		b.join(joinType, table, alias, condition)
+	}
+}
+
+// joinEnd reports whether the next token starts the clause after a join
+// condition
+func (p *sqlParser) joinEnd() bool {
+	for _, keyword := range []string{"join", "inner", "left", "right", "full", "where", "group", "order", "limit", "offset", ";"} {
+		if p.is(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *sqlParser) where(b *QueryBuilder) error {
+	if !p.accept("where") {
+		return nil
+	}
+	clauses, err := p.conditions(0)
+	if err != nil {
+		return err
+	}
+	// A top-level and chain needs no parentheses
+	if len(clauses) == 1 && clauses[0].Group != nil && !hasOr(clauses[0].Group) {
+		clauses = clauses[0].Group
+	}
+	clauses[0].JoinType = "and"
+	b.whereClauses = append(b.whereClauses, clauses...)
+	return nil
+}
+
+// hasOr reports whether any clause after the first is joined with or
+func hasOr(clauses []*WhereClause) bool {
+	for _, clause := range clauses[1:] {
+		if clause.JoinType == "or" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *sqlParser) conditions(depth int) ([]*WhereClause, error) {
+	return parseJoined(p.accept, "or", func() ([]*WhereClause, error) {
+		return parseJoined(p.accept, "and", func() ([]*WhereClause, error) {
+			return p.condition(depth)
+		})
+	})
+}
+
+func (p *sqlParser) condition(depth int) ([]*WhereClause, error) {
+	if p.accept("(") {
+		if depth >= maxDocumentDepth {
+			return nil, fmt.Errorf("%w: conditions nested deeper than %d", ErrUnsupportedSQL, maxDocumentDepth)
+		}
+		clauses, err := p.conditions(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return clauses, p.expect(")")
+	}
+
+	start := p.pos
+	column, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept("is") {
+		p.accept("not")
+		if err := p.expect("null"); err != nil {
+			return nil, err
+		}
+		return []*WhereClause{{Expr: &Expr{SQL: p.text(start, p.pos)}, JoinType: "and"}}, nil
+	}
+
+	negated := p.accept("not")
+	if p.accept("in") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var values []interface{}
+		for {
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if negated {
+			clauses := make([]*WhereClause, len(values))
+			for i, value := range values {
+				clauses[i] = &WhereClause{Column: column, Operator: "<>", Value: value, JoinType: "and"}
+			}
+			return clauses, nil
+		}
+		return []*WhereClause{{Column: column, Operator: "in", Value: values, JoinType: "and"}}, nil
+	}
+
+	t, ok := p.peek()
+	if !ok {
+		return nil, p.unexpected("comparison operator")
+	}
+	operator := strings.ToLower(t.text)
+	switch operator {
+	case "like", "ilike":
+		if negated {
+			operator = "not " + operator
+		}
+	case "=", "<>", "!=", "<", "<=", ">", ">=":
+		if negated {
+			return nil, p.unexpected("in, like or ilike")
+		}
+	default:
+		return nil, p.unexpected("comparison operator")
+	}
+	p.pos++
+
+	// A comparison between two columns stays raw
+	if next, ok := p.peek(); ok && (next.kind == sqlIdent || (next.kind == sqlWord && !isLiteralWord(next.text))) {
+		if _, err := p.identifier(); err != nil {
+			return nil, err
+		}
+		return []*WhereClause{{Expr: &Expr{SQL: p.text(start, p.pos)}, JoinType: "and"}}, nil
+	}
+
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	return []*WhereClause{{Column: column, Operator: operator, Value: value, JoinType: "and"}}, nil
+}
+
+func isLiteralWord(word string) bool {
+	switch strings.ToLower(word) {
+	case "true", "false", "null":
+		return true
+	}
+	return false
+}
+
+// value consumes a literal or placeholder
+func (p *sqlParser) value() (interface{}, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, p.unexpected("value")
+	}
+	negative := false
+	if t.kind == sqlSymbol && t.text == "-" && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].kind == sqlNumber {
+		negative = true
+		p.pos++
+		t = p.tokens[p.pos]
+	}
+
+	switch t.kind {
+	case sqlString:
+		p.pos++
+		return t.text, nil
+	case sqlNumber:
+		text := t.text
+		if negative {
+			text = "-" + text
+		}
+		n, ok := parseNumber(text)
+		if !ok {
+			return nil, p.unexpected("number")
+		}
+		p.pos++
+		return n, nil
+	case sqlPlaceholder:
+		value, err := p.placeholder(t)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+		return value, nil
+	case sqlWord:
+		switch strings.ToLower(t.text) {
+		case "true":
+			p.pos++
+			return true, nil
+		case "false":
+			p.pos++
+			return false, nil
+		case "null":
+			p.pos++
+			return nil, nil
+		}
+	}
+	return nil, p.unexpected("literal or placeholder")
+}
+
+// placeholder resolves a ? or $n placeholder to its argument
+func (p *sqlParser) placeholder(t sqlToken) (interface{}, error) {
+	style := QuestionMark
+	if t.text != "?" {
+		style = DollarNumber
+	}
+	if p.styleFixed && p.style != style {
+		return nil, fmt.Errorf("%w: mixed placeholder styles", ErrUnsupportedSQL)
+	}
+	p.style, p.styleFixed = style, true
+
+	index := p.next
+	if style == QuestionMark {
+		p.next++
+	} else {
+		n, err := strconv.Atoi(t.text[1:])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: invalid placeholder %s", ErrUnsupportedSQL, t.text)
+		}
+		index = n - 1
+		p.maxDollar = max(p.maxDollar, n)
+	}
+	if index >= len(p.args) {
+		return nil, fmt.Errorf("%w: placeholder %s has no arg", ErrUnsupportedSQL, t.text)
+	}
+	return p.args[index], nil
+}
+
+// used returns how many args the placeholders referenced
+func (p *sqlParser) used() int {
+	if p.style == DollarNumber {
+		return p.maxDollar
+	}
+	return p.next
+}
+
+func (p *sqlParser) integer() (int, error) {
+	t, ok := p.peek()
+	if !ok || t.kind != sqlNumber {
+		return 0, p.unexpected("integer")
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil {
+		return 0, p.unexpected("integer")
+	}
+	p.pos++
+	return n, nil
+}
+
+func (p *sqlParser) insertStatement() (*QueryBuilder, error) {
+	if err := p.expect("into"); err != nil {
+		return nil, err
+	}
+	table, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var columns []string
+	for {
+		column, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")", "values"); err != nil {
+		return nil, err
+	}
+
+	b := NewQueryBuilder().Table(table).InsertColumns(columns...)
+	for first := true; first || p.accept(","); first = false {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var row []interface{}
+		for {
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if first {
+			b.Values(row...)
+		} else {
+			b.AddRow(row...)
+		}
+	}
+	return p.finish(b), nil
+}
+
+func (p *sqlParser) updateStatement() (*QueryBuilder, error) {
+	table, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("set"); err != nil {
+		return nil, err
+	}
+	b := NewQueryBuilder().Table(table)
+	for {
+		column, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		b.Set(column, value)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.where(b); err != nil {
+		return nil, err
+	}
+	return p.finish(b), nil
+}
+
+func (p *sqlParser) deleteStatement() (*QueryBuilder, error) {
+	if err := p.expect("from"); err != nil {
+		return nil, err
+	}
+	table, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	b := NewQueryBuilder().Table(table).Delete()
+	if err := p.where(b); err != nil {
+		return nil, err
+	}
+	return p.finish(b), nil
+}
@@ -0,0 +1,78 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	builders := []*QueryBuilder{
+		NewQueryBuilder().Table("users").Select("id", "name as label").Where("age", ">", 18).OrWhere("vip", "=", true),
+		NewQueryBuilder().Table("users").As("u").
+			LeftJoinAs("orders", "o", "o.user_id = u.id").
+			Join("teams", "teams.id = u.team_id").
+			WhereIn("u.status", "active", "trial").
+			WhereGroup(func(q *QueryBuilder) {
+				q.Where("o.total", ">=", 10.5).OrWhere("o.note", "like", "it's%")
+			}).
+			OrderBy("u.created_at desc, u.id").
+			Limit(10).
+			Offset(20),
+		NewQueryBuilder().Table("orders").SelectRaw("status").SelectRaw("count(*)").GroupBy("status"),
+		NewQueryBuilder().Table("tags").InsertColumns("name", "color").Values("go", "blue").AddRow("sql", nil),
+		NewQueryBuilder().Table("users").Set("name", "Ann").Set("age", 30).Where("id", "=", 7),
+		NewQueryBuilder().Table("users").Delete().Where("id", "<>", 7),
+		NewQueryBuilder().ParameterPlaceholder(QuestionMark).Table("users").Where("id", "=", 7).Where("name", "ilike", "a%"),
+	}
+
+	for _, qb := range builders {
+		want := qb.Build()
+		parsed, err := Parse(want.SQL, want.Params...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", want.SQL, err)
+			continue
+		}
+		got := parsed.Build()
+		if got.SQL != want.SQL {
+			t.Errorf("Expected SQL: %s, got: %s", want.SQL, got.SQL)
+		}
+		if !reflect.DeepEqual(got.Params, want.Params) {
+			t.Errorf("%s: expected params %v, got %v", want.SQL, want.Params, got.Params)
+		}
+	}
+}
+
+func TestParseLiterals(t *testing.T) {
+	qb, err := Parse(`SELECT * FROM "users" u WHERE u.age >= -2 AND u.name = 'O''Brien' AND u.deleted_at IS NULL AND u.team_id = u.owner_id;`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	query := qb.Build()
+
+	expectedSQL := "select * from users as u where u.age >= $1 and u.name = $2 and u.deleted_at IS NULL and u.team_id = u.owner_id"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if !reflect.DeepEqual(query.Params, []interface{}{int64(-2), "O'Brien"}) {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	statements := map[string][]interface{}{
+		"select * from users where id = $1":            nil,
+		"select * from users where id = ?":             {1, 2},
+		"select * from users where id = ? or x = $2":   {1, 2},
+		"select * from users union select * from x":    nil,
+		"with x as (select 1) select * from x":         nil,
+		"select * from users where id between 1 and 2": nil,
+		"update users set n = n + 1":                   nil,
+		"select * from users where name = 'open":       nil,
+	}
+	for sql, args := range statements {
+		if _, err := Parse(sql, args...); !errors.Is(err, ErrUnsupportedSQL) {
+			t.Errorf("%s: expected ErrUnsupportedSQL, got: %v", sql, err)
+		}
+	}
+}
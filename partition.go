@@ -0,0 +1,30 @@
+package query
+
+import "errors"
+
+// ErrUnsupportedFeature is returned by TryBuild when the query uses a
+// feature the target database does not have
+var ErrUnsupportedFeature = errors.New("feature not supported by this database")
+
+// FromPartition restricts a MySQL select, update or delete to the named
+// partitions: "from orders partition (p2024)". MySQL is selected with
+// QuestionMark placeholders; other databases fail with ErrUnsupportedFeature,
+// since in Postgres a partition is queried as a table of its own.
+func (b *QueryBuilder) FromPartition(partitions ...string) *QueryBuilder {
+	b.partitions = append(b.partitions, partitions...)
+	return b
+}
+
+func (b *QueryBuilder) writePartitions(w *sqlWriter) {
+	if len(b.partitions) == 0 {
+		return
+	}
+	w.write(" partition (")
+	for i, partition := range b.partitions {
+		if i > 0 {
+			w.write(", ")
+		}
+		w.ident(partition)
+	}
+	w.write(")")
+}
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFromPartition(t *testing.T) {
+	query := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("orders").
+		FromPartition("p2024").
+		As("o").
+		Where("o.customer_id", "=", 7).
+		Build()
+
+	expectedSQL := "select * from orders partition (p2024) as o where o.customer_id = ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestFromPartitionDelete(t *testing.T) {
+	query := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		QuoteStyle(Backtick).
+		Table("orders").
+		FromPartition("p2023", "p2024").
+		Delete().
+		Where("status", "=", "void").
+		Build()
+
+	expectedSQL := "delete from `orders` partition (`p2023`, `p2024`) where `status` = ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestFromPartitionRequiresMySQL(t *testing.T) {
+	_, err := NewQueryBuilder().Table("orders").FromPartition("p2024").TryBuild()
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrMixedRoutes is returned by Pipeline.Run when its queries are routed
+// to different databases, see RouteTag
+var ErrMixedRoutes = errors.New("pipeline queries route to different databases")
+
+// Pipeline runs several queries for one request and returns every result
+// in order. Create one with Runner.Pipeline.
+type Pipeline struct {
+	runner         *Runner
+	builders       []Builder
+	multiStatement bool
+}
+
+// PipelineResult holds the rows of one pipelined query
+type PipelineResult struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Pipeline starts an empty pipeline on r
+func (r *Runner) Pipeline() *Pipeline {
+	return &Pipeline{runner: r}
+}
+
+// Add appends a query to the pipeline
+func (p *Pipeline) Add(qb Builder) *Pipeline {
+	p.builders = append(p.builders, qb)
+	return p
+}
+
+// MultiStatement sends every query in one round trip, joined with ";", and
+// reads one result set per query. It needs a driver with multi-statement
+// support enabled (MySQL's multiStatements=true) and ? placeholders.
+func (p *Pipeline) MultiStatement() *Pipeline {
+	p.multiStatement = true
+	return p
+}
+
+// Run executes the queries and reads their rows into memory. Every query
+// must be routed to the same database (see RouteTag), or Run fails with
+// ErrMixedRoutes. Without
+// MultiStatement, queries on a *sql.DB run concurrently on separate pool
+// connections, so the total wait is the slowest query rather than the sum;
+// on a *sql.Tx or *sql.Conn they run one after another.
+func (p *Pipeline) Run(ctx context.Context) ([]PipelineResult, error) {
+	queries := make([]Query, len(p.builders))
+	for i, qb := range p.builders {
+		if b, ok := qb.(*QueryBuilder); ok && p.multiStatement && b.paramStyle != QuestionMark {
+			return nil, fmt.Errorf("%w: multi-statement pipelines need ? placeholders", ErrUnsupportedFeature)
+		}
+		q, err := p.runner.prepare(ctx, qb)
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = q
+	}
+
+	db, err := p.route()
+	if err != nil {
+		return nil, err
+	}
+	if p.multiStatement {
+		return p.runMultiStatement(ctx, db, queries)
+	}
+
+	results := make([]PipelineResult, len(queries))
+	if _, ok := db.(*sql.DB); !ok {
+		for i, q := range queries {
+			var err error
+			if results[i], err = p.query(ctx, db, p.builders[i], q); err != nil {
+				return nil, err
+			}
+		}
+		return results, nil
+	}
+
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = p.query(ctx, db, p.builders[i], q)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// route returns the DB every query of the pipeline is routed to
+func (p *Pipeline) route() (DB, error) {
+	db := p.runner.db
+	for i, qb := range p.builders {
+		if i == 0 {
+			db = p.runner.route(qb)
+		} else if p.runner.route(qb) != db {
+			return nil, fmt.Errorf("%w: query %d", ErrMixedRoutes, i)
+		}
+	}
+	return db, nil
+}
+
+func (p *Pipeline) query(ctx context.Context, db DB, qb Builder, q Query) (PipelineResult, error) {
+	defer p.runner.track(ctx, qb, q)()
+	rows, err := db.QueryContext(ctx, q.SQL, q.Params...)
+	if err != nil {
+		return PipelineResult{}, err
+	}
+	defer rows.Close()
+	result, err := readResult(rows)
+	if err != nil {
+		return PipelineResult{}, err
+	}
+	return result, rows.Err()
+}
+
+func (p *Pipeline) runMultiStatement(ctx context.Context, db DB, queries []Query) ([]PipelineResult, error) {
+	var statements []string
+	var params []interface{}
+	for i, q := range queries {
+		statements = append(statements, q.SQL)
+		params = append(params, q.Params...)
+		defer p.runner.track(ctx, p.builders[i], q)()
+	}
+
+	rows, err := db.QueryContext(ctx, strings.Join(statements, "; "), params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]PipelineResult, len(queries))
+	for i := range results {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("query: pipeline returned %d result sets for %d queries", i, len(queries))
+		}
+		if results[i], err = readResult(rows); err != nil {
+			return nil, err
+		}
+	}
+	return results, rows.Err()
+}
+
+// readResult reads the current result set of rows
+func readResult(rows *sql.Rows) (PipelineResult, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return PipelineResult{}, err
+	}
+	result := PipelineResult{Columns: columns}
+	for rows.Next() {
+		values, scanArgs := scanTargets(len(columns))
+		if err := rows.Scan(scanArgs...); err != nil {
+			return PipelineResult{}, err
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select count(*) from users", []string{"count"}, []driver.Value{int64(42)})
+	fake.On("select id, name from teams where owner_id = $1", []string{"id", "name"},
+		[]driver.Value{int64(1), "core"},
+		[]driver.Value{int64(2), "infra"},
+	)
+
+	results, err := NewRunner(db).Pipeline().
+		Add(NewQueryBuilder().Table("users").Select("count(*)")).
+		Add(NewQueryBuilder().Table("teams").Select("id", "name").Where("owner_id", "=", 7)).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got: %d", len(results))
+	}
+	if results[0].Rows[0][0] != int64(42) {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if len(results[1].Rows) != 2 || results[1].Columns[1] != "name" || results[1].Rows[1][1] != "infra" {
+		t.Errorf("Unexpected second result: %+v", results[1])
+	}
+}
+
+func TestPipelineStopsOnBuildError(t *testing.T) {
+	db, fake := newFakeDB(t)
+	_, err := NewRunner(db).Pipeline().
+		Add(NewQueryBuilder().Table("users")).
+		Add(NewQueryBuilder().Table("users; drop table users").StrictIdentifiers()).
+		Run(context.Background())
+	if !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+	if len(fake.Calls()) != 0 {
+		t.Errorf("Expected nothing to run, got: %+v", fake.Calls())
+	}
+}
+
+func TestPipelineMultiStatementNeedsQuestionMarks(t *testing.T) {
+	db, _ := newFakeDB(t)
+	_, err := NewRunner(db).Pipeline().
+		Add(NewQueryBuilder().Table("users").Where("id", "=", 1)).
+		MultiStatement().
+		Run(context.Background())
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+	}
+}
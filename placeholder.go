@@ -0,0 +1,112 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPlaceholderNumbering is returned by TryBuild when the placeholders of
+// a statement do not bind its params one by one
+var ErrPlaceholderNumbering = errors.New("placeholder numbering mismatch")
+
+// ParamCount returns the number of params the placeholders of the
+// statement refer to: the highest $n, or the number of ? when there is no
+// $n placeholder (a ? is then taken for the Postgres operator). Quoted
+// text and comments are skipped.
+func (q Query) ParamCount() int {
+	highest := -1
+	questions := scanPlaceholders(q.SQL, func(n int) {
+		highest = max(highest, n)
+	})
+	if highest < 0 {
+		return questions
+	}
+	return highest
+}
+
+// CheckPlaceholders reports, as ErrPlaceholderNumbering, a statement whose
+// placeholders do not bind Params in order: $1 to $n, each once and in
+// sequence, or one ? per param for a statement without $n. TryBuild runs
+// the same check before returning; it is exported for code composing
+// statements from several built queries, such as hand-written unions.
+func (q Query) CheckPlaceholders() error {
+	style := QuestionMark
+	scanPlaceholders(q.SQL, func(int) {
+		style = DollarNumber
+	})
+	return checkPlaceholders(q.SQL, len(q.Params), style)
+}
+
+// checkPlaceholders verifies the placeholders of sql in the given style
+// against the number of params
+func checkPlaceholders(sql string, params int, style ParameterStyle) error {
+	var err error
+	numbers := 0
+	questions := scanPlaceholders(sql, func(n int) {
+		switch {
+		case err != nil:
+		case n <= numbers:
+			err = fmt.Errorf("%w: $%d used again after $%d", ErrPlaceholderNumbering, n, numbers)
+		case n > numbers+1:
+			err = fmt.Errorf("%w: $%d follows $%d", ErrPlaceholderNumbering, n, numbers)
+		}
+		numbers++
+	})
+	if style == QuestionMark {
+		if questions != params {
+			return fmt.Errorf("%w: %d placeholders for %d params", ErrPlaceholderNumbering, questions, params)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if numbers != params {
+		return fmt.Errorf("%w: %d placeholders for %d params", ErrPlaceholderNumbering, numbers, params)
+	}
+	return nil
+}
+
+// scanPlaceholders calls number with each $n placeholder of sql in order
+// of appearance and returns the count of ? outside quoted text and comments
+func scanPlaceholders(sql string, number func(n int)) int {
+	questions := 0
+	for i := 0; i < len(sql); i++ {
+		next := strings.IndexAny(sql[i:], "'\"`/?$")
+		if next < 0 {
+			return questions
+		}
+		i += next
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			end := closingQuote(sql, i)
+			if end < 0 {
+				return questions
+			}
+			i = end
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return questions
+			}
+			i += end + 3
+		case c == '?':
+			questions++
+		case c == '$' && i+1 < len(sql) && isDigit(sql[i+1]) && (i == 0 || !isIdentifierByte(sql[i-1])):
+			j := i + 1
+			for j < len(sql) && isDigit(sql[j]) {
+				j++
+			}
+			n, _ := strconv.Atoi(sql[i+1 : j])
+			number(n)
+			i = j - 1
+		}
+	}
+	return questions
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
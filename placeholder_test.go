@@ -0,0 +1,47 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParamCount(t *testing.T) {
+	active := NewQueryBuilder().Table("users").Where("status", "=", "active").WhereRaw("note <> '$9 ?'")
+	admins := NewQueryBuilder().Table("admins").Where("level", ">", 2).Where("team_id", "=", 7)
+	query, err := Union(active, admins).TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := query.ParamCount(); got != 3 || got != len(query.Params) {
+		t.Errorf("Expected 3 params, got: %d", got)
+	}
+	if err := query.CheckPlaceholders(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	mysql := NewQueryBuilder().Dialect(MySQL).Table("users").WhereIn("id", 1, 2, 3).Build()
+	if got := mysql.ParamCount(); got != 3 {
+		t.Errorf("Expected 3 params, got: %d", got)
+	}
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	tests := []Query{
+		{SQL: "select * from a where x = $1 and y = $1", Params: []interface{}{1, 2}},
+		{SQL: "select * from a where x = $1 and y = $3", Params: []interface{}{1, 2}},
+		{SQL: "select * from a where x = $2 and y = $1", Params: []interface{}{1, 2}},
+		{SQL: "select * from a where x = $1", Params: []interface{}{1, 2}},
+		{SQL: "select * from a where x = ? and y = ?", Params: []interface{}{1}},
+	}
+	for _, q := range tests {
+		if err := q.CheckPlaceholders(); !errors.Is(err, ErrPlaceholderNumbering) {
+			t.Errorf("Expected ErrPlaceholderNumbering for %s, got: %v", q.SQL, err)
+		}
+	}
+
+	// a raw fragment reusing a placeholder is caught before the query is returned
+	_, err := NewQueryBuilder().Table("users").Where("id", "=", 1).WhereRaw("parent_id = $1").TryBuild()
+	if !errors.Is(err, ErrPlaceholderNumbering) {
+		t.Errorf("Expected ErrPlaceholderNumbering, got: %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// PlanNode is one node of a query execution plan
+type PlanNode struct {
+	NodeType    string
+	Relation    string
+	Index       string
+	StartupCost float64
+	TotalCost   float64
+	Rows        float64
+	Children    []*PlanNode
+}
+
+// Plan runs EXPLAIN for the built query and returns the parsed plan tree.
+// Postgres queries are explained with EXPLAIN (FORMAT JSON); MySQL queries
+// use the tabular EXPLAIN, with one child node per row of output and the
+// root's Rows estimated as a nested loop join: the product of the rows the
+// outer query reads from each table, scaled by their filtered percentage.
+// Other dialects fail with ErrUnsupportedFeature.
+func (b *QueryBuilder) Plan(ctx context.Context, db Querier) (*PlanNode, error) {
+	q, err := b.TryBuild()
+	if err != nil {
+		return nil, err
+	}
+	switch b.target() {
+	case Postgres:
+		return explainPostgres(ctx, db, q)
+	case MySQL:
+		return explainMySQL(ctx, db, q)
+	default:
+		return nil, fmt.Errorf("%w: plan parsing on %s", ErrUnsupportedFeature, b.target())
+	}
+}
+
+type postgresPlan struct {
+	NodeType    string         `json:"Node Type"`
+	Relation    string         `json:"Relation Name"`
+	Index       string         `json:"Index Name"`
+	StartupCost float64        `json:"Startup Cost"`
+	TotalCost   float64        `json:"Total Cost"`
+	Rows        float64        `json:"Plan Rows"`
+	Plans       []postgresPlan `json:"Plans"`
+}
+
+func (p postgresPlan) node() *PlanNode {
+	node := &PlanNode{
+		NodeType:    p.NodeType,
+		Relation:    p.Relation,
+		Index:       p.Index,
+		StartupCost: p.StartupCost,
+		TotalCost:   p.TotalCost,
+		Rows:        p.Rows,
+	}
+	for _, child := range p.Plans {
+		node.Children = append(node.Children, child.node())
+	}
+	return node
+}
+
+func explainPostgres(ctx context.Context, db Querier, q Query) (*PlanNode, error) {
+	rows, err := db.QueryContext(ctx, "explain (format json) "+q.SQL, q.Params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var output []byte
+	for rows.Next() {
+		var line []byte
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		output = append(output, line...)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var plans []struct {
+		Plan postgresPlan `json:"Plan"`
+	}
+	if err := json.Unmarshal(output, &plans); err != nil {
+		return nil, fmt.Errorf("parse explain output: %w", err)
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("parse explain output: empty plan")
+	}
+
+	return plans[0].Plan.node(), nil
+}
+
+func explainMySQL(ctx context.Context, db Querier, q Query) (*PlanNode, error) {
+	rows, err := db.QueryContext(ctx, "explain "+q.SQL, q.Params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	root := &PlanNode{NodeType: "Query"}
+	var outerID string
+	values := make([]sql.NullString, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+
+		node := &PlanNode{}
+		id, filtered := "", 100.0
+		for i, column := range columns {
+			switch column {
+			case "id":
+				id = values[i].String
+			case "filtered":
+				if values[i].Valid {
+					filtered, _ = strconv.ParseFloat(values[i].String, 64)
+				}
+			case "type":
+				node.NodeType = values[i].String
+			case "table":
+				node.Relation = values[i].String
+			case "key":
+				node.Index = values[i].String
+			case "rows":
+				node.Rows, _ = strconv.ParseFloat(values[i].String, 64)
+			}
+		}
+		// Rows with another id belong to subqueries, not the outer join
+		if len(root.Children) == 0 {
+			outerID, root.Rows = id, 1
+		}
+		if id == outerID {
+			root.Rows *= node.Rows * filtered / 100
+		}
+		root.Children = append(root.Children, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
@@ -0,0 +1,88 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestPlanPostgres(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("explain (format json) select * from users where id = $1",
+		[]string{"QUERY PLAN"},
+		[]driver.Value{[]byte(`[{"Plan": {"Node Type": "Limit", "Startup Cost": 0.29, "Total Cost": 8.3, "Plan Rows": 1,
+			"Plans": [{"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey",
+			"Startup Cost": 0.29, "Total Cost": 8.3, "Plan Rows": 1}]}}]`)},
+	)
+
+	plan, err := NewQueryBuilder().
+		Table("users").
+		Where("id", "=", 1).
+		Plan(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if plan.NodeType != "Limit" || plan.TotalCost != 8.3 || len(plan.Children) != 1 {
+		t.Fatalf("Unexpected root node: %+v", plan)
+	}
+
+	child := plan.Children[0]
+	if child.NodeType != "Index Scan" || child.Relation != "users" || child.Index != "users_pkey" || child.Rows != 1 {
+		t.Errorf("Unexpected child node: %+v", child)
+	}
+}
+
+func TestPlanMySQL(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("explain select * from users where email = ?",
+		[]string{"id", "select_type", "table", "type", "possible_keys", "key", "rows", "Extra"},
+		[]driver.Value{int64(1), "SIMPLE", "users", "ref", "users_email", "users_email", int64(1), nil},
+	)
+
+	plan, err := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("users").
+		Where("email", "=", "a@example.com").
+		Plan(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(plan.Children) != 1 {
+		t.Fatalf("Expected one plan row, got: %+v", plan)
+	}
+
+	child := plan.Children[0]
+	if child.NodeType != "ref" || child.Relation != "users" || child.Index != "users_email" || child.Rows != 1 {
+		t.Errorf("Unexpected plan row: %+v", child)
+	}
+}
+
+func TestPlanMySQLJoinRows(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("explain select * from users JOIN orders on orders.user_id = users.id where users.active = ? and users.id in (select user_id from refunds)",
+		[]string{"id", "select_type", "table", "type", "key", "rows", "filtered"},
+		[]driver.Value{int64(1), "PRIMARY", "users", "ALL", nil, int64(1000), "10.00"},
+		[]driver.Value{int64(1), "PRIMARY", "orders", "ref", "orders_user_id", int64(5), "100.00"},
+		[]driver.Value{int64(2), "SUBQUERY", "refunds", "ALL", nil, int64(300), "100.00"},
+	)
+
+	plan, err := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("users").
+		Join("orders", "orders.user_id = users.id").
+		Where("users.active", "=", true).
+		WhereInQuery("users.id", NewQueryBuilder().Table("refunds").Select("user_id")).
+		Plan(context.Background(), db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(plan.Children) != 3 || plan.Children[2].Rows != 300 {
+		t.Fatalf("Expected the per-table estimates as children, got: %+v", plan)
+	}
+	if plan.Rows != 500 {
+		t.Errorf("Expected 1000 * 10%% * 5 = 500 rows, got: %v", plan.Rows)
+	}
+}
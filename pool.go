@@ -0,0 +1,48 @@
+package query
+
+import "sync"
+
+// maxPooledBuffer caps the SQL buffers kept for reuse, so one huge query
+// does not pin its memory in the pool
+const maxPooledBuffer = 64 << 10
+
+var builderPool = sync.Pool{
+	New: func() interface{} {
+		return NewQueryBuilder()
+	},
+}
+
+// AcquireBuilder returns an empty builder from a shared pool. Return it
+// with ReleaseBuilder once the built Query has been taken; the Query stays
+// valid after release.
+func AcquireBuilder() *QueryBuilder {
+	return builderPool.Get().(*QueryBuilder)
+}
+
+// ReleaseBuilder resets b and returns it to the pool. b must not be used
+// afterwards.
+func ReleaseBuilder(b *QueryBuilder) {
+	b.Reset()
+	builderPool.Put(b)
+}
+
+// Reset returns b to the state of NewQueryBuilder while keeping the
+// capacity of its internal slices
+func (b *QueryBuilder) Reset() *QueryBuilder {
+	clear(b.whereClauses)
+	clear(b.joinClauses)
+	clear(b.updateValues)
+
+	style, dialect := defaults()
+	*b = QueryBuilder{
+		queryType:     SelectQuery,
+		columns:       []string{"*"},
+		whereClauses:  b.whereClauses[:0],
+		joinClauses:   b.joinClauses[:0],
+		paramStyle:    style,
+		dialect:       dialect,
+		updateColumns: b.updateColumns[:0],
+		updateValues:  b.updateValues[:0],
+	}
+	return b
+}
@@ -0,0 +1,135 @@
+package query
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestResetRestoresDefaults(t *testing.T) {
+	qb := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("users").
+		As("u").
+		Select("id").
+		LeftJoin("accounts", "accounts.id = u.account_id").
+		Where("id", "=", 1).
+		OrderBy("id").
+		Limit(5)
+	first := qb.Build()
+
+	qb.Reset().Table("posts").Where("published", "=", true)
+	query := qb.Build()
+
+	expectedSQL := "select * from posts where published = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	expectedFirst := "select id from users as u LEFT JOIN accounts on accounts.id = u.account_id where id = ? order by id limit 5"
+	if first.SQL != expectedFirst || first.Params[0] != 1 {
+		t.Errorf("Expected earlier query to be unchanged, got: %s %v", first.SQL, first.Params)
+	}
+}
+
+func TestAcquireReleaseBuilder(t *testing.T) {
+	qb := AcquireBuilder().Table("users").Where("id", "=", 1)
+	query := qb.Build()
+	ReleaseBuilder(qb)
+
+	reused := AcquireBuilder()
+	defer ReleaseBuilder(reused)
+	if sql := reused.Table("orders").Build().SQL; sql != "select * from orders" {
+		t.Errorf("Expected a clean builder, got: %s", sql)
+	}
+
+	if query.SQL != "select * from users where id = $1" || query.Params[0] != 1 {
+		t.Errorf("Expected released query to stay valid, got: %s %v", query.SQL, query.Params)
+	}
+}
+
+func TestWalkAfterResetKeepsOtherBuilders(t *testing.T) {
+	qb := AcquireBuilder().Table("x")
+	qb.Walk(func(node Node) bool {
+		if column, ok := node.(*ColumnNode); ok && column.Name == "*" {
+			column.Name = "secret"
+		}
+		return true
+	})
+	ReleaseBuilder(qb)
+
+	other := NewQueryBuilder().Reset().Table("y")
+	if sql := other.Build().SQL; sql != "select * from y" {
+		t.Errorf("Expected select * from y, got: %s", sql)
+	}
+	pooled := AcquireBuilder()
+	defer ReleaseBuilder(pooled)
+	if sql := pooled.Table("y").Build().SQL; sql != "select * from y" {
+		t.Errorf("Expected select * from y, got: %s", sql)
+	}
+}
+
+// buildAllocs is the allocation budget of rebuilding a plain select: the
+// SQL string and the params slice, which must outlive the builder
+const buildAllocs = 2
+
+func skipUnderRace(t *testing.T) {
+	t.Helper()
+	if raceEnabled {
+		t.Skip("allocation counts differ under the race detector")
+	}
+}
+
+func TestBuildAllocs(t *testing.T) {
+	skipUnderRace(t)
+	qb := NewQueryBuilder().
+		Table("users").
+		Select("id", "name", "email").
+		Where("age", ">", 18).
+		Where("active", "=", true).
+		OrderBy("name").
+		Limit(10)
+	qb.Build()
+
+	if allocs := testing.AllocsPerRun(100, func() { qb.Build() }); allocs > buildAllocs {
+		t.Errorf("Expected at most %d allocations per rebuild, got: %.0f", buildAllocs, allocs)
+	}
+
+	pooled := AcquireBuilder().Table("users").Select("id", "name").Where("age", ">", 18)
+	defer ReleaseBuilder(pooled)
+	pooled.Build()
+	if allocs := testing.AllocsPerRun(100, func() { pooled.Build() }); allocs > buildAllocs {
+		t.Errorf("Expected at most %d allocations per pooled build, got: %.0f", buildAllocs, allocs)
+	}
+}
+
+func TestBuildAllocsWithColumnTypes(t *testing.T) {
+	skipUnderRace(t)
+	registerDocTypes(t)
+
+	qb := NewQueryBuilder().Table("users").Select("id", "name").Where("age", ">", 18)
+	qb.Build()
+	if allocs := testing.AllocsPerRun(100, func() { qb.Build() }); allocs > buildAllocs {
+		t.Errorf("Expected at most %d allocations per rebuild, got: %.0f", buildAllocs, allocs)
+	}
+}
+
+func TestConcurrentBuild(t *testing.T) {
+	base := NewQueryBuilder().Table("users").Select("id", "name").Where("active", "=", true).OrderBy("name")
+	expected := base.Build()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if query := base.Build(); query.SQL != expected.SQL {
+					t.Errorf("Expected SQL: %s, got: %s", expected.SQL, query.SQL)
+					return
+				}
+				base.Clone().Where("id", "=", 1).Build()
+			}
+		}()
+	}
+	wg.Wait()
+}
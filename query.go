@@ -1,8 +1,11 @@
 package query
 
 import (
-	"fmt"
+	"context"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type ParameterStyle int
@@ -30,32 +33,156 @@ func (q Query) Sql() string {
 	return q.SQL
 }
 
+// Expr is a raw SQL expression used in place of a bound parameter value.
+// Each ? in SQL is replaced by a placeholder bound to the matching entry
+// of Args; write ?? for a literal question mark.
+type Expr struct {
+	SQL  string
+	Args []interface{}
+
+	nextVal  bool   // set by NextVal, rendered per dialect
+	sequence string // the sequence of NextVal
+}
+
+// Raw wraps a SQL fragment so it is rendered inline instead of bound.
+func Raw(sql string, args ...interface{}) Expr {
+	return Expr{SQL: sql, Args: args}
+}
+
+// QueryBuilder builds a single SQL statement. Builder methods mutate the
+// receiver and must not run concurrently with other uses of it; Build,
+// TryBuild and Clone only read it, so a shared base query can be built and
+// cloned from several goroutines at once. Clone it before adding to it.
 type QueryBuilder struct {
 	queryType    QueryType
 	table        string
+	fromExpr     *Expr
+	fromSub      *QueryBuilder
+	partitions   []string
 	tableAlias   string
 	columns      []string
+	selectExprs  []Expr
+	selectSubs   []selectSubquery
 	whereClauses []*WhereClause
+	groupBy      []string
+	having       []*WhereClause
+	qualify      []*WhereClause
+	exists       bool
+	starExclude  []string
+	starReplace  []starReplacement
 	joinClauses  []*JoinClause
 	order        string
+	orderExprs   []Expr
+	randomOrder  bool
+	samplePct    float64
 	limit        int
 	offset       int
+	lockStrength string
+	lockOf       []string
+	lockWait     string
 	paramStyle   ParameterStyle
+	dialect      Dialect
+
+	// CQL options, see cql.go
+	allowFiltering bool
+	ttl            int
+	writeTime      int64
+
+	// Select list rewrites, see RewriteColumns
+	columnRewrites []ColumnRewrite
+
+	// Validation settings, see TryBuild
+	strictIdentifiers bool
+	readOnly          bool
+
+	// First error recorded by a builder method, returned by TryBuild
+	err error
+
+	// Location time.Time params are converted to, see TimeZone
+	timeLocation *time.Location
+
+	// Bind UUIDs as bytes, see BinaryUUIDs
+	binaryUUIDs bool
+
+	// Runner routing label, see Tag
+	tag string
+
+	// Report changed rows to the Runner's hook, see CaptureChanges
+	captureChanges bool
+	captureKey     string
+
+	// Set by the unnest helpers, which are Postgres only
+	unnest     bool
+	updateFrom *Expr // Source of UpdateFromUnnest, after set
 
 	// For INSERT operations
 	insertColumns []string
 	insertValues  []interface{}
+	insertRows    [][]interface{} // Rows added with AddRow, after insertValues
+	insertSelect  *QueryBuilder   // Source of InsertFromSelect, instead of values
+
+	// Defaults from ConfigureTable: the order used when none is set, and
+	// how many leading where clauses are base filters. Factory scopes are
+	// base filters added again for every table.
+	defaultOrder string
+	baseFilters  int
+	scopes       []func(q *QueryBuilder)
+
+	// Outer query columns referenced by CorrelateOn
+	outerRefs []string
+
+	// Qualified column checks, see ValidateAliases
+	checkAliases bool
+
+	// Conflict handling, see IdempotencyKey and Upsert
+	idempotencyKey   string
+	upsertKey        []string
+	returningChanged bool
+	returnID         string // Column returned for InsertIDs
+	returnKey        string // Column InsertIDs matches ids to rows by
+
+	// Context and hooks applied at build time, see BuildHook
+	ctx        context.Context
+	buildHooks []BuildHook
+	hooked     bool
+	comments   []string
 
 	// For UPDATE operations
 	updateColumns []string
 	updateValues  []interface{}
+
+	// Identifier quoting and keyword casing, see QuoteStyle and KeywordCase
+	quoteStyle  QuoteStyle
+	keywordCase KeywordCasing
 }
 
 type WhereClause struct {
 	Column   string
 	Operator string
 	Value    interface{}
-	JoinType string // AND/OR
+	JoinType string         // AND/OR
+	Expr     *Expr          // Raw condition rendered instead of Column/Operator/Value
+	Group    []*WhereClause // Parenthesized conditions rendered instead of Column/Operator/Value
+	Subquery *QueryBuilder  // Parenthesized select rendered instead of Value, see WhereExists
+}
+
+// exprArgs returns the args of a raw condition or of an expression value
+func (where *WhereClause) exprArgs() []interface{} {
+	if where.Expr != nil {
+		return where.Expr.Args
+	}
+	if expr, ok := where.Value.(Expr); ok {
+		return expr.Args
+	}
+	return nil
+}
+
+// walkWheres calls fn for every clause, descending into groups
+func walkWheres(clauses []*WhereClause, fn func(where *WhereClause)) {
+	for _, where := range clauses {
+		fn(where)
+		walkWheres(where.Group, fn)
+	}
 }
 
 // JoinClause represents a JOIN operation in a query
@@ -64,14 +191,18 @@ type JoinClause struct {
 	Table     string
 	Alias     string
 	Condition string
+	Source    *Expr // Set-returning function joined instead of Table, see JoinFunction
+	On        *Expr // Raw condition rendered instead of Condition, see Join
 }
 
 func NewQueryBuilder() *QueryBuilder {
+	style, dialect := defaults()
 	return &QueryBuilder{
 		queryType:   SelectQuery,
 		columns:     []string{"*"},
 		joinClauses: []*JoinClause{},
-		paramStyle:  DollarNumber, // Default to DollarNumber
+		paramStyle:  style, // DollarNumber unless SetDefaultParameterStyle
+		dialect:     dialect,
 	}
 }
 
@@ -82,6 +213,7 @@ func (b *QueryBuilder) ParameterPlaceholder(style ParameterStyle) *QueryBuilder
 
 func (b *QueryBuilder) Table(table string) *QueryBuilder {
 	b.table = table
+	b.applyTableConfig()
 	return b
 }
 
@@ -94,6 +226,17 @@ func (b *QueryBuilder) Select(columns ...string) *QueryBuilder {
 	return b
 }
 
+// SelectRaw adds a select expression with bound args. It replaces the
+// default "*" unless columns were chosen with Select.
+func (b *QueryBuilder) SelectRaw(sql string, args ...interface{}) *QueryBuilder {
+	b.queryType = SelectQuery
+	if len(b.selectExprs) == 0 && len(b.columns) == 1 && b.columns[0] == "*" {
+		b.columns = nil
+	}
+	b.selectExprs = append(b.selectExprs, Raw(sql, args...))
+	return b
+}
+
 // INSERT operations
 func (b *QueryBuilder) Insert(data map[string]interface{}) *QueryBuilder {
 	b.queryType = InsertQuery
@@ -144,7 +287,11 @@ func (b *QueryBuilder) Delete() *QueryBuilder {
 	return b
 }
 
-// WHERE clauses (common to all query types)
+// WHERE clauses (common to all query types). The value is bound as a
+// parameter unless it is an Expr, which is written in its place and binds
+// its own args between the neighboring placeholders:
+//
+//	Where("expires_at", "<", Raw("now() - ?::interval", "7 days"))
 func (b *QueryBuilder) Where(column string, operator string, value interface{}) *QueryBuilder {
 	b.whereClauses = append(b.whereClauses, &WhereClause{
 		Column:   column,
@@ -165,12 +312,75 @@ func (b *QueryBuilder) OrWhere(column string, operator string, value interface{}
 	return b
 }
 
+// WhereRaw adds a raw condition with bound args, joined with AND
+func (b *QueryBuilder) WhereRaw(sql string, args ...interface{}) *QueryBuilder {
+	expr := Raw(sql, args...)
+	b.whereClauses = append(b.whereClauses, &WhereClause{
+		Expr:     &expr,
+		JoinType: "and",
+	})
+	return b
+}
+
+// OrWhereRaw adds a raw condition with bound args, joined with OR
+func (b *QueryBuilder) OrWhereRaw(sql string, args ...interface{}) *QueryBuilder {
+	expr := Raw(sql, args...)
+	b.whereClauses = append(b.whereClauses, &WhereClause{
+		Expr:     &expr,
+		JoinType: "or",
+	})
+	return b
+}
+
+// WhereGroup adds the conditions added by fn as one parenthesized group,
+// joined with AND
+func (b *QueryBuilder) WhereGroup(fn func(q *QueryBuilder)) *QueryBuilder {
+	return b.whereGroup("and", fn)
+}
+
+// OrWhereGroup adds the conditions added by fn as one parenthesized group,
+// joined with OR
+func (b *QueryBuilder) OrWhereGroup(fn func(q *QueryBuilder)) *QueryBuilder {
+	return b.whereGroup("or", fn)
+}
+
+func (b *QueryBuilder) whereGroup(joinType string, fn func(q *QueryBuilder)) *QueryBuilder {
+	group := NewQueryBuilder()
+	fn(group)
+	if len(group.whereClauses) == 0 {
+		return b
+	}
+	b.whereClauses = append(b.whereClauses, &WhereClause{
+		Group:    group.whereClauses,
+		JoinType: joinType,
+	})
+	return b
+}
+
+// WhereIn adds a "column in (...)" condition binding one placeholder per
+// value. An empty list matches no rows.
+func (b *QueryBuilder) WhereIn(column string, values ...interface{}) *QueryBuilder {
+	b.whereClauses = append(b.whereClauses, &WhereClause{
+		Column:   column,
+		Operator: "in",
+		Value:    values,
+		JoinType: "and",
+	})
+	return b
+}
+
 // ORDER BY (for SELECT and UPDATE/DELETE with LIMIT support in some databases)
 func (b *QueryBuilder) OrderBy(order string) *QueryBuilder {
 	b.order = order
 	return b
 }
 
+// OrderByRaw appends an ordering expression with bound args after OrderBy
+func (b *QueryBuilder) OrderByRaw(sql string, args ...interface{}) *QueryBuilder {
+	b.orderExprs = append(b.orderExprs, Raw(sql, args...))
+	return b
+}
+
 // LIMIT and OFFSET (primarily for SELECT, but some databases support for UPDATE/DELETE)
 func (b *QueryBuilder) Limit(limit int) *QueryBuilder {
 	b.limit = limit
@@ -182,101 +392,51 @@ func (b *QueryBuilder) Offset(offset int) *QueryBuilder {
 	return b
 }
 
-// JOIN operations
-func (b *QueryBuilder) Join(table, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "JOIN",
-		Table:     table,
-		Condition: condition,
-	})
-	return b
+// JOIN operations. The condition is a string comparing columns of the
+// joined tables, "o.user_id = u.id [and ...]" with =, <>, !=, <, <=, > or
+// >=, whose names are quoted like any other identifier. Anything else must
+// be wrapped in Raw, or TryBuild fails with ErrInvalidJoin:
+//
+//	Join("orders o", Raw("o.user_id = u.id and o.total > ?", 100))
+func (b *QueryBuilder) Join(table string, condition interface{}) *QueryBuilder {
+	return b.join("JOIN", table, "", condition)
 }
 
-func (b *QueryBuilder) LeftJoin(table, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "LEFT JOIN",
-		Table:     table,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) LeftJoin(table string, condition interface{}) *QueryBuilder {
+	return b.join("LEFT JOIN", table, "", condition)
 }
 
-func (b *QueryBuilder) RightJoin(table, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "RIGHT JOIN",
-		Table:     table,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) RightJoin(table string, condition interface{}) *QueryBuilder {
+	return b.join("RIGHT JOIN", table, "", condition)
 }
 
-func (b *QueryBuilder) InnerJoin(table, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "INNER JOIN",
-		Table:     table,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) InnerJoin(table string, condition interface{}) *QueryBuilder {
+	return b.join("INNER JOIN", table, "", condition)
 }
 
-func (b *QueryBuilder) FullJoin(table, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "FULL JOIN",
-		Table:     table,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) FullJoin(table string, condition interface{}) *QueryBuilder {
+	return b.join("FULL JOIN", table, "", condition)
 }
 
 // JOIN operations with alias support
-func (b *QueryBuilder) JoinAs(table, alias, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "JOIN",
-		Table:     table,
-		Alias:     alias,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) JoinAs(table, alias string, condition interface{}) *QueryBuilder {
+	return b.join("JOIN", table, alias, condition)
 }
 
-func (b *QueryBuilder) LeftJoinAs(table, alias, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "LEFT JOIN",
-		Table:     table,
-		Alias:     alias,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) LeftJoinAs(table, alias string, condition interface{}) *QueryBuilder {
+	return b.join("LEFT JOIN", table, alias, condition)
 }
 
-func (b *QueryBuilder) RightJoinAs(table, alias, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "RIGHT JOIN",
-		Table:     table,
-		Alias:     alias,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) RightJoinAs(table, alias string, condition interface{}) *QueryBuilder {
+	return b.join("RIGHT JOIN", table, alias, condition)
 }
 
-func (b *QueryBuilder) InnerJoinAs(table, alias, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "INNER JOIN",
-		Table:     table,
-		Alias:     alias,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) InnerJoinAs(table, alias string, condition interface{}) *QueryBuilder {
+	return b.join("INNER JOIN", table, alias, condition)
 }
 
-func (b *QueryBuilder) FullJoinAs(table, alias, condition string) *QueryBuilder {
-	b.joinClauses = append(b.joinClauses, &JoinClause{
-		Type:      "FULL JOIN",
-		Table:     table,
-		Alias:     alias,
-		Condition: condition,
-	})
-	return b
+func (b *QueryBuilder) FullJoinAs(table, alias string, condition interface{}) *QueryBuilder {
+	return b.join("FULL JOIN", table, alias, condition)
 }
 
 // Table alias support
@@ -285,216 +445,638 @@ func (b *QueryBuilder) As(alias string) *QueryBuilder {
 	return b
 }
 
-func (b *QueryBuilder) getPlaceholder(index int) string {
-	switch b.paramStyle {
-	case QuestionMark:
-		return "?"
-	case DollarNumber:
-		return fmt.Sprintf("$%d", index)
-	default:
-		return fmt.Sprintf("$%d", index) // Default to DollarNumber
+// sqlWriter accumulates the SQL text and parameters of a build. The byte
+// buffer is borrowed from the builder and handed back afterwards, so
+// repeated builds (and pooled builders) reuse its capacity.
+type sqlWriter struct {
+	style    ParameterStyle
+	quote    QuoteStyle
+	keywords KeywordCasing
+	buf      []byte
+	params   []interface{}
+	count    int
+
+	// Inlined SQL literals written instead of placeholders, see BuildInterpolated
+	literals []string
+
+	// Registered column types and whether to cast to them, see RegisterColumnTypes
+	types map[string]ColumnType
+	casts bool
+
+	// Target dialect, for dialect-specific expressions such as NewUUID
+	dialect Dialect
+
+	// Buffer taken from sqlBuffers, given back by finish
+	pooled *[]byte
+}
+
+// sqlBuffers holds the SQL buffers of finished builds, so rebuilding does
+// not grow a new buffer and never writes to the builder
+var sqlBuffers = sync.Pool{
+	New: func() interface{} {
+		return new([]byte)
+	},
+}
+
+// newWriter returns a writer for b by value, so a build can keep it on
+// the stack
+func (b *QueryBuilder) newWriter() sqlWriter {
+	pooled := sqlBuffers.Get().(*[]byte)
+	return sqlWriter{
+		style:    b.paramStyle,
+		quote:    b.quoteStyle,
+		keywords: b.keywordCase,
+		types:    b.columnTypes(),
+		casts:    b.castsParams(),
+		dialect:  b.target(),
+		buf:      (*pooled)[:0],
+		params:   make([]interface{}, 0, b.paramCapacity()),
+		pooled:   pooled,
+	}
+}
+
+// finish returns the built query and gives the buffer back to sqlBuffers
+func (w *sqlWriter) finish() Query {
+	if len(w.params) == 0 {
+		w.params = nil
+	}
+	var sql string
+	if w.literals != nil {
+		sql = string(w.buf)
+	} else {
+		sql = cachedSQL(w.buf)
+	}
+	if w.pooled != nil && cap(w.buf) <= maxPooledBuffer {
+		*w.pooled = w.buf[:0]
+		sqlBuffers.Put(w.pooled)
+	}
+	w.buf, w.pooled = nil, nil
+	return Query{
+		SQL:    sql,
+		Params: w.params,
 	}
 }
 
+// write writes SQL text, applying the keyword casing
+func (w *sqlWriter) write(s string) {
+	w.buf = append(w.buf, w.keywords.apply(s)...)
+}
+
+// raw writes s unchanged, for identifiers, literals and raw fragments
+func (w *sqlWriter) raw(s string) {
+	w.buf = append(w.buf, s...)
+}
+
+func (w *sqlWriter) writeInt(n int) {
+	w.buf = strconv.AppendInt(w.buf, int64(n), 10)
+}
+
+// bind writes the next placeholder and records its value
+func (w *sqlWriter) bind(value interface{}) {
+	w.count++
+	if w.literals != nil {
+		w.raw(w.literals[w.count-1])
+		return
+	}
+	if w.style == QuestionMark {
+		w.buf = append(w.buf, '?')
+	} else {
+		w.buf = append(w.buf, '$')
+		w.buf = strconv.AppendInt(w.buf, int64(w.count), 10)
+	}
+	w.params = append(w.params, value)
+}
+
+// expr writes expr, replacing each ? with the next placeholder
+func (w *sqlWriter) expr(expr Expr) {
+	arg := 0
+	for i := 0; i < len(expr.SQL); i++ {
+		c := expr.SQL[i]
+		if c != '?' {
+			w.buf = append(w.buf, c)
+			continue
+		}
+		if i+1 < len(expr.SQL) && expr.SQL[i+1] == '?' {
+			w.buf = append(w.buf, '?')
+			i++
+			continue
+		}
+		if arg < len(expr.Args) {
+			w.bind(expr.Args[arg])
+			arg++
+			continue
+		}
+		w.buf = append(w.buf, c)
+	}
+}
+
+// value writes a placeholder for value, or the expression itself
+func (w *sqlWriter) value(value interface{}) {
+	if expr, ok := value.(Expr); ok && expr.SQL == uuidFunc && w.dialect == MySQL {
+		w.write("uuid()")
+		return
+	}
+	if expr, ok := value.(Expr); ok && expr.nextVal {
+		w.nextVal(expr.sequence)
+		return
+	}
+	if expr, ok := value.(Expr); ok {
+		w.expr(expr)
+		return
+	}
+	w.bind(value)
+}
+
+// paramCapacity estimates the number of params, so the slice is allocated once
+func (b *QueryBuilder) paramCapacity() int {
+	n := len(b.whereClauses) + len(b.insertValues) + len(b.updateValues)
+	for _, row := range b.insertRows {
+		n += len(row)
+	}
+	for _, expr := range b.selectExprs {
+		n += len(expr.Args)
+	}
+	for _, expr := range b.orderExprs {
+		n += len(expr.Args)
+	}
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		n += len(where.exprArgs())
+	})
+	if b.fromExpr != nil {
+		n += len(b.fromExpr.Args)
+	}
+	for _, join := range b.joinClauses {
+		if join.Source != nil {
+			n += len(join.Source.Args)
+		}
+		if join.On != nil {
+			n += len(join.On.Args)
+		}
+	}
+	if b.updateFrom != nil {
+		n += len(b.updateFrom.Args)
+	}
+	for _, replace := range b.starReplace {
+		n += len(replace.Expr.Args)
+	}
+	walkWheres(b.qualify, func(where *WhereClause) {
+		n += len(where.exprArgs())
+	})
+	walkWheres(b.having, func(where *WhereClause) {
+		n += 1 + len(where.exprArgs())
+	})
+	return n
+}
+
+// Build generates the SQL and parameters. If validation fails (see TryBuild)
+// it returns an empty Query rather than a partially valid statement.
+//
+// Deprecated: Build drops the validation error; use TryBuild.
 func (b *QueryBuilder) Build() Query {
+	query, err := b.TryBuild()
+	if err != nil {
+		return Query{}
+	}
+	return query
+}
+
+// TryBuild validates the builder and generates the SQL and parameters,
+// returning the validation error instead of a query when one fails.
+func (b *QueryBuilder) TryBuild() (Query, error) {
+	if hooked, err := b.withBuildHooks(); err != nil || hooked != b {
+		if err != nil {
+			return Query{}, err
+		}
+		return hooked.TryBuild()
+	}
+	if err := b.validate(); err != nil {
+		return Query{}, err
+	}
+	return b.paramSettings().finishParams(b.build())
+}
+
+// paramSettings are the builder settings the params of a built query are
+// checked and converted with
+type paramSettings struct {
+	style    ParameterStyle
+	binary   bool // BinaryUUIDs
+	location *time.Location
+}
+
+func (b *QueryBuilder) paramSettings() paramSettings {
+	return paramSettings{style: b.paramStyle, binary: b.binaryUUIDs, location: b.timeLocation}
+}
+
+// finishParams checks the placeholders of query against its params and
+// converts the params for the driver. TryBuild, Union, Feed and
+// Template.Bind all finish their queries here.
+func (s paramSettings) finishParams(query Query) (Query, error) {
+	if err := checkPlaceholders(query.SQL, len(query.Params), s.style); err != nil {
+		return Query{}, err
+	}
+	if s.plainParams(query.Params) {
+		return query, nil
+	}
+	if err := convertBigNumbers(query.Params); err != nil {
+		return Query{}, err
+	}
+	convertUUIDs(query.Params, s.binary)
+	convertHstores(query.Params)
+	if err := validateParams(query.Params); err != nil {
+		return Query{}, err
+	}
+	convertTimes(query.Params, s.location)
+	return query, nil
+}
+
+// plainParams reports whether every param is a builtin scalar, which the
+// conversions and checks of finishParams would leave as it is
+func (s paramSettings) plainParams(params []interface{}) bool {
+	for _, param := range params {
+		switch param.(type) {
+		case nil, string, bool, []byte, float32, float64,
+			int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		case time.Time:
+			if s.location != nil {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (b *QueryBuilder) build() Query {
+	w := b.newWriter()
+	b.render(&w)
+	b.writeComments(&w)
+	return w.finish()
+}
+
+// render writes the statement for the builder's query type
+func (b *QueryBuilder) render(w *sqlWriter) {
 	switch b.queryType {
-	case SelectQuery:
-		return b.buildSelect()
 	case InsertQuery:
-		return b.buildInsert()
+		b.writeInsert(w)
 	case UpdateQuery:
-		return b.buildUpdate()
+		b.writeUpdate(w)
 	case DeleteQuery:
-		return b.buildDelete()
+		b.writeDelete(w)
 	default:
-		return b.buildSelect()
+		b.writeSelect(w)
 	}
 }
 
-func (b *QueryBuilder) buildSelect() Query {
-	var query strings.Builder
-	var params []interface{}
-	paramCount := 0
-
+func (b *QueryBuilder) writeSelect(w *sqlWriter) {
 	// Build SELECT clause
-	query.WriteString("select ")
-	query.WriteString(strings.Join(b.columns, ", "))
+	w.write("select ")
+	if b.exists {
+		w.write("exists(select 1")
+	} else {
+		b.writeSelectList(w)
+	}
+	b.writeSelectFrom(w)
+}
+
+// writeSelectFrom writes everything after the select list, from the FROM
+// clause onwards
+func (b *QueryBuilder) writeSelectFrom(w *sqlWriter) {
+	if resolved, err := b.withRelationPaths(); err == nil && resolved != b {
+		resolved.writeSelectFrom(w)
+		return
+	}
 
 	// Build FROM clause
-	query.WriteString(" from ")
-	query.WriteString(b.table)
+	w.write(" from ")
+	if b.fromSub != nil {
+		w.write("(")
+		types, casts := w.types, w.casts
+		w.types, w.casts = b.fromSub.columnTypes(), b.fromSub.castsParams()
+		b.fromSub.writeSelect(w)
+		w.types, w.casts = types, casts
+		w.write(")")
+	} else if b.fromExpr != nil {
+		w.expr(*b.fromExpr)
+	} else {
+		w.ident(b.table)
+	}
+	b.writePartitions(w)
 	if b.tableAlias != "" {
-		query.WriteString(" as ")
-		query.WriteString(b.tableAlias)
+		w.write(" as ")
+		w.ident(b.tableAlias)
 	}
+	b.writeTableSample(w)
 
 	// Build JOIN clauses
 	for _, join := range b.joinClauses {
-		query.WriteString(" ")
-		query.WriteString(join.Type)
-		query.WriteString(" ")
-		query.WriteString(join.Table)
+		w.write(" ")
+		w.write(join.Type)
+		w.write(" ")
+		if join.Source != nil {
+			w.expr(*join.Source)
+		} else {
+			w.ident(join.Table)
+		}
 		if join.Alias != "" {
-			query.WriteString(" as ")
-			query.WriteString(join.Alias)
+			w.write(" as ")
+			w.ident(join.Alias)
 		}
-		query.WriteString(" on ")
-		query.WriteString(join.Condition)
+		w.write(" on ")
+		w.joinCondition(join)
 	}
 
 	// Build WHERE clause
-	if len(b.whereClauses) > 0 {
-		whereSQL, whereParams, count := b.buildWhereClause(paramCount)
-		query.WriteString(whereSQL)
-		params = append(params, whereParams...)
-		paramCount = count
+	b.writeWhere(w)
+
+	// Build GROUP BY, HAVING and QUALIFY clauses
+	b.writeGroupBy(w)
+	if len(b.qualify) > 0 {
+		w.write(" qualify ")
+		w.conditions(b.qualify)
 	}
 
-	// Build ORDER BY clause
-	if b.order != "" {
-		query.WriteString(" order by ")
-		query.WriteString(b.order)
+	// Build ORDER BY clause, which only matters to EXISTS when paginated
+	if !b.exists || b.limit > 0 || b.offset > 0 {
+		b.writeOrderBy(w)
 	}
 
 	// Build LIMIT clause
 	if b.limit > 0 {
-		query.WriteString(fmt.Sprintf(" limit %d", b.limit))
+		w.write(" limit ")
+		w.writeInt(b.limit)
 	}
 
 	// Build OFFSET clause
 	if b.offset > 0 {
-		query.WriteString(fmt.Sprintf(" offset %d", b.offset))
+		w.write(" offset ")
+		w.writeInt(b.offset)
 	}
 
-	return Query{
-		SQL:    query.String(),
-		Params: params,
+	// Build locking clause
+	b.writeLock(w)
+
+	if b.allowFiltering {
+		w.write(" allow filtering")
+	}
+
+	if b.exists {
+		w.write(")")
 	}
 }
 
-func (b *QueryBuilder) buildInsert() Query {
-	var query strings.Builder
-	var params []interface{}
+// writeSelectList writes the selected columns and expressions
+func (b *QueryBuilder) writeSelectList(w *sqlWriter) {
+	written := 0
+	if len(b.columnRewrites) > 0 {
+		written = b.writeRewrittenSelectList(w)
+	} else {
+		for _, column := range b.columns {
+			if written > 0 {
+				w.write(", ")
+			}
+			written++
+			w.column(column)
+			b.writeStarModifiers(w, column)
+		}
+		for _, expr := range b.selectExprs {
+			if written > 0 {
+				w.write(", ")
+			}
+			written++
+			w.expr(expr)
+		}
+	}
+	for _, sel := range b.selectSubs {
+		if written > 0 {
+			w.write(", ")
+		}
+		written++
+		w.write("(")
+		types, casts := w.types, w.casts
+		w.types, w.casts = sel.sub.columnTypes(), sel.sub.castsParams()
+		sel.sub.writeSelect(w)
+		w.types, w.casts = types, casts
+		w.write(") as ")
+		w.ident(sel.alias)
+	}
+}
 
+func (b *QueryBuilder) writeInsert(w *sqlWriter) {
 	// Build INSERT clause
-	query.WriteString("insert into ")
-	query.WriteString(b.table)
+	w.write("insert into ")
+	w.ident(b.table)
 
+	// Build columns
 	if len(b.insertColumns) > 0 {
-		// Build columns
-		query.WriteString(" (")
-		query.WriteString(strings.Join(b.insertColumns, ", "))
-		query.WriteString(") values (")
-
-		// Build placeholders
-		placeholders := make([]string, len(b.insertValues))
-		for i := range b.insertValues {
-			placeholders[i] = b.getPlaceholder(i + 1)
+		w.write(" (")
+		for i, column := range b.insertColumns {
+			if i > 0 {
+				w.write(", ")
+			}
+			w.ident(column)
 		}
-		query.WriteString(strings.Join(placeholders, ", "))
-		query.WriteString(")")
-
-		params = append(params, b.insertValues...)
+		w.write(")")
 	}
 
-	return Query{
-		SQL:    query.String(),
-		Params: params,
+	if b.insertSelect != nil {
+		w.write(" ")
+		b.writeInsertSelect(w)
+	} else if len(b.insertColumns) > 0 {
+		w.write(" values ")
+
+		// Build placeholders, one parenthesized list per row
+		for r, row := range b.rows() {
+			if r > 0 {
+				w.write(", ")
+			}
+			w.write("(")
+			for i, value := range row {
+				if i > 0 {
+					w.write(", ")
+				}
+				if i < len(b.insertColumns) {
+					w.typed(b.insertColumns[i], value)
+				} else {
+					w.value(value)
+				}
+			}
+			w.write(")")
+		}
 	}
+	b.writeIdempotency(w)
+	b.writeUpsert(w)
+	b.writeUsing(w)
+	b.writeReturnID(w)
 }
 
-func (b *QueryBuilder) buildUpdate() Query {
-	var query strings.Builder
-	var params []interface{}
-	paramCount := 0
-
+func (b *QueryBuilder) writeUpdate(w *sqlWriter) {
 	// Build UPDATE clause
-	query.WriteString("update ")
-	query.WriteString(b.table)
-	query.WriteString(" set ")
+	w.write("update ")
+	w.ident(b.table)
+	b.writePartitions(w)
+	b.writeUsing(w)
+	w.write(" set ")
 
 	// Build SET clause
-	setClauses := make([]string, len(b.updateColumns))
 	for i, column := range b.updateColumns {
-		paramCount++
-		setClauses[i] = fmt.Sprintf("%s = %s", column, b.getPlaceholder(paramCount))
+		if i > 0 {
+			w.write(", ")
+		}
+		w.ident(column)
+		w.write(" = ")
+		w.typed(column, b.updateValues[i])
+	}
+	if b.updateFrom != nil {
+		w.write(" from ")
+		w.expr(*b.updateFrom)
 	}
-	query.WriteString(strings.Join(setClauses, ", "))
-	params = append(params, b.updateValues...)
 
 	// Build WHERE clause
-	if len(b.whereClauses) > 0 {
-		whereSQL, whereParams, count := b.buildWhereClause(paramCount)
-		query.WriteString(whereSQL)
-		params = append(params, whereParams...)
-		paramCount = count
-	}
+	b.writeWhere(w)
 
 	// Build ORDER BY clause (supported in some databases like MySQL)
-	if b.order != "" {
-		query.WriteString(" order by ")
-		query.WriteString(b.order)
-	}
+	b.writeOrderBy(w)
 
 	// Build LIMIT clause (supported in some databases like MySQL)
 	if b.limit > 0 {
-		query.WriteString(fmt.Sprintf(" limit %d", b.limit))
+		w.write(" limit ")
+		w.writeInt(b.limit)
 	}
 
-	return Query{
-		SQL:    query.String(),
-		Params: params,
-	}
+	// Build RETURNING clause for CaptureChanges
+	b.writeReturningChanges(w)
 }
 
-func (b *QueryBuilder) buildDelete() Query {
-	var query strings.Builder
-	var params []interface{}
-	paramCount := 0
-
+func (b *QueryBuilder) writeDelete(w *sqlWriter) {
 	// Build DELETE clause
-	query.WriteString("delete from ")
-	query.WriteString(b.table)
+	w.write("delete from ")
+	w.ident(b.table)
+	b.writePartitions(w)
+	b.writeUsing(w)
 
 	// Build WHERE clause
-	if len(b.whereClauses) > 0 {
-		whereSQL, whereParams, count := b.buildWhereClause(paramCount)
-		query.WriteString(whereSQL)
-		params = append(params, whereParams...)
-		paramCount = count
-	}
+	b.writeWhere(w)
 
 	// Build ORDER BY clause (supported in some databases like MySQL)
-	if b.order != "" {
-		query.WriteString(" order by ")
-		query.WriteString(b.order)
-	}
+	b.writeOrderBy(w)
 
 	// Build LIMIT clause (supported in some databases like MySQL)
 	if b.limit > 0 {
-		query.WriteString(fmt.Sprintf(" limit %d", b.limit))
+		w.write(" limit ")
+		w.writeInt(b.limit)
 	}
 
-	return Query{
-		SQL:    query.String(),
-		Params: params,
+	// Build RETURNING clause for CaptureChanges
+	b.writeReturningChanges(w)
+}
+
+func (b *QueryBuilder) writeWhere(w *sqlWriter) {
+	sampled := b.sampleFilter()
+	if len(b.whereClauses) == 0 {
+		if sampled {
+			w.write(" where ")
+			b.writeSampleFilter(w)
+		}
+		return
 	}
+
+	clauses := b.scopedWheres()
+	w.write(" where ")
+	if !sampled {
+		w.conditions(clauses)
+		return
+	}
+	w.write("(")
+	w.conditions(clauses)
+	w.write(") and ")
+	b.writeSampleFilter(w)
 }
 
-func (b *QueryBuilder) buildWhereClause(paramCount int) (string, []interface{}, int) {
-	var query strings.Builder
-	var params []interface{}
+// scopedWheres returns the where clauses with the builder's own conditions
+// grouped behind its base filters, so an OrWhere cannot get past them
+func (b *QueryBuilder) scopedWheres() []*WhereClause {
+	if b.baseFilters == 0 || len(b.whereClauses) == b.baseFilters {
+		return b.whereClauses
+	}
+	base := b.whereClauses[:b.baseFilters:b.baseFilters]
+	return append(base, documentGroup(b.whereClauses[b.baseFilters:], "and"))
+}
 
-	query.WriteString(" where ")
-	for i, where := range b.whereClauses {
+// conditions writes a list of where clauses joined by their AND/OR
+func (w *sqlWriter) conditions(clauses []*WhereClause) {
+	for i, where := range clauses {
 		if i > 0 {
-			query.WriteString(" " + where.JoinType + " ")
+			w.write(" ")
+			w.write(where.JoinType)
+			w.write(" ")
+		}
+		if where.Expr != nil {
+			w.expr(*where.Expr)
+			continue
+		}
+		if where.Group != nil {
+			w.write("(")
+			w.conditions(where.Group)
+			w.write(")")
+			continue
 		}
-		paramCount++
-		query.WriteString(where.Column)
-		query.WriteString(" " + where.Operator + " " + b.getPlaceholder(paramCount))
-		params = append(params, where.Value)
+		if where.Subquery != nil {
+			if where.Column != "" {
+				w.ident(where.Column)
+				w.write(" ")
+			}
+			w.write(where.Operator)
+			w.write(" (")
+			types, casts := w.types, w.casts
+			w.types, w.casts = where.Subquery.columnTypes(), where.Subquery.castsParams()
+			where.Subquery.writeSelect(w)
+			w.types, w.casts = types, casts
+			w.write(")")
+			continue
+		}
+		if ids, ok := where.Value.(inlineInts); ok {
+			w.intIn(where.Column, ids)
+			continue
+		}
+		if values, ok := where.Value.([]interface{}); ok && strings.EqualFold(where.Operator, "in") {
+			w.whereIn(where.Column, values)
+			continue
+		}
+		w.ident(where.Column)
+		w.write(" ")
+		w.write(where.Operator)
+		w.write(" ")
+		if column, ok := where.Value.(outerColumn); ok {
+			w.ident(string(column))
+			continue
+		}
+		w.typed(where.Column, where.Value)
+	}
+}
+
+func (b *QueryBuilder) writeOrderBy(w *sqlWriter) {
+	random := b.randomOrder || b.sampleFilter()
+	order := b.order
+	if order == "" && len(b.orderExprs) == 0 && !random && b.queryType == SelectQuery {
+		order = b.defaultOrder
+	}
+	if order == "" && len(b.orderExprs) == 0 && !random {
+		return
 	}
 
-	return query.String(), params, paramCount
+	w.write(" order by ")
+	w.orderBy(order)
+	for i, expr := range b.orderExprs {
+		if i > 0 || order != "" {
+			w.write(", ")
+		}
+		w.expr(expr)
+	}
+	if random {
+		if order != "" || len(b.orderExprs) > 0 {
+			w.write(", ")
+		}
+		w.write(b.randomFunc())
+	}
 }
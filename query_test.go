@@ -59,6 +59,29 @@ func TestSelectQueryWithOrWhereClause(t *testing.T) {
 	}
 }
 
+func TestSelectQueryWithWhereIn(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		Where("active", "=", true).
+		WhereIn("id", 1, 2, 3)
+
+	query := qb.Build()
+	expectedSQL := "select * from users where active = $1 and id in ($2, $3, $4)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 4 || query.Params[1] != 1 || query.Params[3] != 3 {
+		t.Errorf("Expected params: [true, 1, 2, 3], got: %v", query.Params)
+	}
+
+	query = NewQueryBuilder().Table("users").WhereIn("id").Build()
+	expectedSQL = "select * from users where 1 = 0"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
 func TestSelectQueryWithOrderByLimitOffset(t *testing.T) {
 	qb := NewQueryBuilder().
 		Table("users").
@@ -114,6 +137,59 @@ func TestSelectQueryWithWildcard(t *testing.T) {
 	}
 }
 
+func TestSelectQueryWithRawFragments(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		SelectRaw("coalesce(nickname, ?) as display_name", "anonymous").
+		Where("active", "=", true).
+		OrWhereRaw("created_at > now() - ?::interval", "7 days").
+		OrderBy("name").
+		OrderByRaw("attrs ?? ?", "vip")
+
+	query := qb.Build()
+	expectedSQL := "select coalesce(nickname, $1) as display_name from users where active = $2 or created_at > now() - $3::interval order by name, attrs ? $4"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 4 || query.Params[0] != "anonymous" || query.Params[1] != true || query.Params[2] != "7 days" || query.Params[3] != "vip" {
+		t.Errorf("Expected params: [anonymous, true, 7 days, vip], got: %v", query.Params)
+	}
+}
+
+func TestWhereExpressionValue(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("sessions").
+		Where("user_id", "=", 7).
+		Where("expires_at", "<", Raw("now() - interval '7 days'")).
+		OrWhere("refreshed_at", "<", Raw("now() - ?::interval", "1 day")).
+		Where("id", ">", 3)
+
+	query := qb.Build()
+	expectedSQL := "select * from sessions where user_id = $1 and expires_at < now() - interval '7 days' or refreshed_at < now() - $2::interval and id > $3"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 3 || query.Params[0] != 7 || query.Params[1] != "1 day" || query.Params[2] != 3 {
+		t.Errorf("Expected params: [7, 1 day, 3], got: %v", query.Params)
+	}
+}
+
+func TestSelectRawKeepsExplicitColumns(t *testing.T) {
+	qb := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("users").
+		Select("id").
+		SelectRaw("age > ? as adult", 18)
+
+	query := qb.Build()
+	expectedSQL := "select id, age > ? as adult from users"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
 // INSERT Query Tests
 
 func TestBasicInsertQuery(t *testing.T) {
@@ -176,6 +252,23 @@ func TestInsertQueryWithSpecificColumns(t *testing.T) {
 	}
 }
 
+func TestInsertQueryWithNextVal(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("orders").
+		InsertColumns("id", "customer_id", "total").
+		Values(NextVal("order_seq"), 7, 99.5)
+
+	query := qb.Build()
+	expectedSQL := "insert into orders (id, customer_id, total) values (nextval('order_seq'), $1, $2)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 2 || query.Params[0] != 7 || query.Params[1] != 99.5 {
+		t.Errorf("Expected params: [7, 99.5], got: %v", query.Params)
+	}
+}
+
 // UPDATE Query Tests
 
 func TestBasicUpdateQuery(t *testing.T) {
@@ -271,6 +364,24 @@ func TestUpdateQueryWithSetMethod(t *testing.T) {
 	}
 }
 
+func TestUpdateQueryWithRawExpression(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		Set("updated_at", Raw("now()")).
+		Set("name", "Jane Doe").
+		Where("id", "=", 1)
+
+	query := qb.Build()
+	expectedSQL := "update users set updated_at = now(), name = $1 where id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 2 || query.Params[0] != "Jane Doe" || query.Params[1] != 1 {
+		t.Errorf("Expected params: ['Jane Doe', 1], got: %v", query.Params)
+	}
+}
+
 // DELETE Query Tests
 
 func TestBasicDeleteQuery(t *testing.T) {
@@ -540,4 +651,58 @@ func BenchmarkJoinQuery(b *testing.B) {
 
 		_ = qb.Build()
 	}
-}
\ No newline at end of file
+}
+
+func BenchmarkPooledSelectQuery(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		qb := AcquireBuilder().
+			Table("users").
+			Select("id", "name", "email").
+			Where("age", ">", 18).
+			Where("active", "=", true).
+			OrderBy("name").
+			Limit(10)
+
+		_ = qb.Build()
+		ReleaseBuilder(qb)
+	}
+}
+
+func BenchmarkRebuildSelectQuery(b *testing.B) {
+	qb := NewQueryBuilder().
+		Table("users").
+		Select("id", "name", "email").
+		Where("age", ">", 18).
+		Where("active", "=", true).
+		OrderBy("name").
+		Limit(10)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = qb.Build()
+	}
+}
+
+func TestWhereGroups(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		Where("active", "=", true).
+		WhereGroup(func(q *QueryBuilder) {
+			q.Where("role", "=", "admin").OrWhere("role", "=", "owner")
+		}).
+		OrWhereGroup(func(q *QueryBuilder) {
+			q.Where("id", "=", 1)
+		}).
+		WhereGroup(func(q *QueryBuilder) {})
+
+	query := qb.Build()
+	expectedSQL := "select * from users where active = $1 and (role = $2 or role = $3) or (id = $4)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 4 || query.Params[1] != "admin" || query.Params[3] != 1 {
+		t.Errorf("Expected params: [true, admin, owner, 1], got: %v", query.Params)
+	}
+}
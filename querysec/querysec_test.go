@@ -0,0 +1,37 @@
+package querysec_test
+
+import (
+	"testing"
+
+	"github.com/scape-labs/query"
+	"github.com/scape-labs/query/querysec"
+)
+
+func FuzzWhere(f *testing.F) {
+	querysec.FuzzBuilder(f, func(value string) query.Builder {
+		return query.NewQueryBuilder().
+			Table("users").
+			Where("name", "=", value).
+			OrWhere("email", "like", value+"%").
+			WhereIn("role", value, "admin")
+	})
+}
+
+func FuzzInsertUpdate(f *testing.F) {
+	querysec.FuzzBuilder(f, func(value string) query.Builder {
+		return query.NewQueryBuilder().
+			Dialect(query.MySQL).
+			Table("notes").
+			Set("body", value).
+			Where("title", "<>", value)
+	})
+}
+
+func FuzzInterpolatedLiterals(f *testing.F) {
+	querysec.FuzzInterpolated(f, func(value string) *query.QueryBuilder {
+		return query.NewQueryBuilder().
+			Table("users").
+			Where("name", "=", value).
+			Where("bio", "=", []byte(value))
+	})
+}
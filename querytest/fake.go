@@ -0,0 +1,96 @@
+// Package querytest provides test doubles for code built on the query
+// package.
+package querytest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/scape-labs/query"
+	"github.com/scape-labs/query/internal/fakedb"
+)
+
+// Fake is an in-memory database behind a real query.Runner. Results are
+// registered per builder and every executed statement is recorded.
+type Fake struct {
+	Runner *query.Runner
+
+	t   testing.TB
+	db  *fakedb.DB
+	sql *sql.DB
+}
+
+// New creates a Fake whose Runner is configured with opts. The database is
+// closed when the test finishes.
+func New(t testing.TB, opts ...query.RunnerOption) *Fake {
+	t.Helper()
+	db := fakedb.New()
+	sqlDB := db.Open()
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return &Fake{
+		Runner: query.NewRunner(sqlDB, opts...),
+		t:      t,
+		db:     db,
+		sql:    sqlDB,
+	}
+}
+
+// DB returns the *sql.DB backing the fake, for helpers that take a Querier
+func (f *Fake) DB() *sql.DB {
+	return f.sql
+}
+
+// Returns registers the rows returned when the SQL built by qb is queried
+func (f *Fake) Returns(qb query.Builder, columns []string, rows ...[]interface{}) {
+	values := make([][]driver.Value, len(rows))
+	for i, row := range rows {
+		values[i] = make([]driver.Value, len(row))
+		for j, v := range row {
+			values[i][j] = v
+		}
+	}
+	f.db.On(f.build(qb).SQL, columns, values...)
+}
+
+// Affects registers the rows affected reported when qb is executed
+func (f *Fake) Affects(qb query.Builder, rows int64) {
+	f.db.OnExec(f.build(qb).SQL, rows)
+}
+
+// Fails registers the error returned when qb is queried or executed
+func (f *Fake) Fails(qb query.Builder, err error) {
+	f.db.OnError(f.build(qb).SQL, err)
+}
+
+// build fails the test when qb does not build
+func (f *Fake) build(qb query.Builder) query.Query {
+	f.t.Helper()
+	q, err := qb.TryBuild()
+	if err != nil {
+		f.t.Fatalf("querytest: %v", err)
+	}
+	return q
+}
+
+// Calls returns every statement run against the fake, in order
+func (f *Fake) Calls() []query.Query {
+	calls := f.db.Calls()
+	queries := make([]query.Query, len(calls))
+	for i, call := range calls {
+		queries[i] = query.Query{SQL: call.SQL, Params: call.Args}
+	}
+	return queries
+}
+
+// Static is a query.Builder that always returns the same Query
+type Static query.Query
+
+func (s Static) Build() query.Query {
+	return query.Query(s)
+}
+
+func (s Static) TryBuild() (query.Query, error) {
+	return query.Query(s), nil
+}
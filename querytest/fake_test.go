@@ -0,0 +1,84 @@
+package querytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scape-labs/query"
+)
+
+// activeUserNames stands in for a service depending on query.Executor
+func activeUserNames(ctx context.Context, exec query.Executor) ([]string, error) {
+	rows, err := exec.Query(ctx, query.NewQueryBuilder().
+		Table("users").
+		Select("name").
+		Where("active", "=", true))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func TestFakeReturnsCannedRows(t *testing.T) {
+	fake := New(t)
+	fake.Returns(query.NewQueryBuilder().Table("users").Select("name").Where("active", "=", true),
+		[]string{"name"},
+		[]interface{}{"John"},
+		[]interface{}{"Jane"},
+	)
+
+	names, err := activeUserNames(context.Background(), fake.Runner)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "John" || names[1] != "Jane" {
+		t.Errorf("Expected [John Jane], got: %v", names)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 || calls[0].SQL != "select name from users where active = $1" || calls[0].Params[0] != true {
+		t.Errorf("Expected one recorded select, got: %v", calls)
+	}
+}
+
+func TestFakeFailsAndAffects(t *testing.T) {
+	fake := New(t)
+	boom := errors.New("boom")
+	fake.Fails(query.NewQueryBuilder().Table("users").Select("name").Where("active", "=", true), boom)
+
+	if _, err := activeUserNames(context.Background(), fake.Runner); !errors.Is(err, boom) {
+		t.Errorf("Expected boom, got: %v", err)
+	}
+
+	del := query.NewQueryBuilder().Table("users").Delete().Where("id", "=", 1)
+	fake.Affects(del, 0)
+	result, err := fake.Runner.Exec(context.Background(), del)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n != 0 {
+		t.Errorf("Expected 0 rows affected, got: %d", n)
+	}
+}
+
+func TestStaticBuilder(t *testing.T) {
+	fake := New(t)
+	if _, err := fake.Runner.Exec(context.Background(), Static{SQL: "delete from sessions"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if calls := fake.Calls(); len(calls) != 1 || calls[0].SQL != "delete from sessions" {
+		t.Errorf("Expected static SQL to run, got: %v", calls)
+	}
+}
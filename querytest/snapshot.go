@@ -0,0 +1,66 @@
+package querytest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scape-labs/query"
+)
+
+// updating reports whether snapshots should be rewritten: QUERYTEST_UPDATE
+// is set, or the test binary defines its own -update flag and it is on.
+// querytest registers no flag itself, so it never clashes with one.
+func updating() bool {
+	if v := os.Getenv("QUERYTEST_UPDATE"); v != "" && v != "0" && v != "false" {
+		return true
+	}
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// Snapshot compares the SQL and params built by qb with
+// testdata/<name>.golden, failing the test on any difference. Run the
+// tests with QUERYTEST_UPDATE=1 (or a -update flag the test package
+// defines) to write the current output instead.
+func Snapshot(t testing.TB, name string, qb query.Builder) {
+	t.Helper()
+
+	q, err := qb.TryBuild()
+	if err != nil {
+		t.Fatalf("querytest: build %s: %v", name, err)
+	}
+	got := formatSnapshot(q)
+	path := filepath.Join("testdata", name+".golden")
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("querytest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("querytest: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("querytest: read snapshot %s (run with QUERYTEST_UPDATE=1 to create it): %v", path, err)
+	}
+	if string(want) != got {
+		t.Errorf("querytest: snapshot %s differs\n--- want\n%s--- got\n%s", path, want, got)
+	}
+}
+
+func formatSnapshot(q query.Query) string {
+	var out strings.Builder
+	out.WriteString(q.SQL)
+	out.WriteString("\n")
+	for i, param := range q.Params {
+		out.WriteString(fmt.Sprintf("-- %d: %#v\n", i+1, param))
+	}
+	return out.String()
+}
@@ -0,0 +1,48 @@
+package querytest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scape-labs/query"
+)
+
+// A test package defining its own -update flag must not clash with querytest
+var _ = flag.Bool("update", false, "rewrite golden files")
+
+func TestSnapshot(t *testing.T) {
+	Snapshot(t, "adult_users", query.NewQueryBuilder().
+		Table("users").
+		Select("id", "name").
+		Where("age", ">", 18).
+		Where("name", "=", "John").
+		OrderBy("name").
+		Limit(10))
+}
+
+func TestFormatSnapshot(t *testing.T) {
+	got := formatSnapshot(query.Query{SQL: "delete from users where id = $1", Params: []interface{}{int64(7)}})
+	expected := "delete from users where id = $1\n-- 1: 7\n"
+	if got != expected {
+		t.Errorf("Expected snapshot: %q, got: %q", expected, got)
+	}
+}
+
+func TestSnapshotUpdateEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("QUERYTEST_UPDATE", "1")
+
+	Snapshot(t, "by_id", query.NewQueryBuilder().Table("users").Where("id", "=", 1))
+
+	got, err := os.ReadFile(filepath.Join(dir, "testdata", "by_id.golden"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "select * from users where id = $1\n-- 1: 1\n"
+	if string(got) != expected {
+		t.Errorf("Expected snapshot: %q, got: %q", expected, got)
+	}
+}
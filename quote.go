@@ -0,0 +1,120 @@
+package query
+
+import "strings"
+
+// QuoteStyle selects how table, column and alias names are quoted
+type QuoteStyle int
+
+const (
+	None        QuoteStyle = iota // name
+	DoubleQuote                   // "name"
+	Backtick                      // `name`
+	Bracket                       // [name]
+)
+
+// QuoteStyle quotes every plain identifier the builder renders: tables,
+// aliases, and columns in select, where, join conditions, insert, update
+// and order by. Qualified names are quoted per part ("u"."id"); expressions
+// such as count(*) and raw fragments are left untouched.
+func (b *QueryBuilder) QuoteStyle(style QuoteStyle) *QueryBuilder {
+	b.quoteStyle = style
+	return b
+}
+
+func (s QuoteStyle) quote(part string) string {
+	switch s {
+	case DoubleQuote:
+		return `"` + part + `"`
+	case Backtick:
+		return "`" + part + "`"
+	case Bracket:
+		return "[" + part + "]"
+	default:
+		return part
+	}
+}
+
+// quoteIdentifier quotes each part of a plain, optionally qualified name
+// and returns anything else unchanged
+func (s QuoteStyle) quoteIdentifier(name string) string {
+	if s == None || !isIdentifier(name) {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part != "*" {
+			parts[i] = s.quote(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// ident writes a table, alias or column name
+func (w *sqlWriter) ident(name string) {
+	w.raw(w.quote.quoteIdentifier(name))
+}
+
+// column writes a select column, which may carry an "as alias" suffix
+func (w *sqlWriter) column(column string) {
+	if w.quote != None || w.keywords != AsWritten {
+		if fields := strings.Fields(column); len(fields) == 3 && strings.EqualFold(fields[1], "as") {
+			w.ident(fields[0])
+			w.write(" " + fields[1] + " ")
+			w.ident(fields[2])
+			return
+		}
+	}
+	w.ident(column)
+}
+
+// orderBy writes an order by list, quoting the column of each term
+func (w *sqlWriter) orderBy(order string) {
+	if w.quote == None && w.keywords == AsWritten {
+		w.raw(order)
+		return
+	}
+	for i, term := range strings.Split(order, ",") {
+		if i > 0 {
+			w.write(", ")
+		}
+		fields := strings.Fields(term)
+		if len(fields) == 0 {
+			continue
+		}
+		w.ident(fields[0])
+		for _, field := range fields[1:] {
+			w.write(" " + field)
+		}
+	}
+}
+
+// whereIn writes "column in (...)" with one placeholder per value, or an
+// always-false condition for an empty list
+func (w *sqlWriter) whereIn(column string, values []interface{}) {
+	if len(values) == 0 {
+		w.write("1 = 0")
+		return
+	}
+	w.ident(column)
+	w.write(" in (")
+	for i, value := range values {
+		if i > 0 {
+			w.write(", ")
+		}
+		w.typed(column, value)
+	}
+	w.write(")")
+}
+
+// QuoteIdentifier quotes a plain, optionally qualified name the way d does,
+// with backticks on MySQL and double quotes elsewhere. It fails with
+// ErrUnsafeIdentifier for anything that is not a plain name.
+func (d Dialect) QuoteIdentifier(name string) (string, error) {
+	if !isIdentifier(name) || strings.Contains(name, "*") {
+		return "", unsafeIdentifier(name)
+	}
+	if d == MySQL {
+		return Backtick.quoteIdentifier(name), nil
+	}
+	return DoubleQuote.quoteIdentifier(name), nil
+}
@@ -0,0 +1,70 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuoteStyleSelect(t *testing.T) {
+	query := NewQueryBuilder().
+		QuoteStyle(DoubleQuote).
+		Table("users").
+		As("u").
+		Select("u.id", "u.name as display_name", "count(*) as total", "a.*").
+		LeftJoinAs("accounts", "a", "a.id = u.account_id").
+		Where("u.order", "=", 1).
+		WhereIn("u.status", "active", "invited").
+		OrderBy("u.created_at desc, u.id").
+		Build()
+
+	expectedSQL := `select "u"."id", "u"."name" as "display_name", count(*) as "total", "a".* from "users" as "u"` +
+		` LEFT JOIN "accounts" as "a" on "a"."id" = "u"."account_id"` +
+		` where "u"."order" = $1 and "u"."status" in ($2, $3) order by "u"."created_at" desc, "u"."id"`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestQuoteStyleWriteQueries(t *testing.T) {
+	tests := []struct {
+		style    QuoteStyle
+		builder  *QueryBuilder
+		expected string
+	}{
+		{
+			Backtick,
+			NewQueryBuilder().Table("order").InsertColumns("key", "value").Values(1, 2),
+			"insert into `order` (`key`, `value`) values ($1, $2)",
+		},
+		{
+			Bracket,
+			NewQueryBuilder().Table("order").Set("key", 1).Where("id", "=", 2),
+			"update [order] set [key] = $1 where [id] = $2",
+		},
+		{
+			None,
+			NewQueryBuilder().Table("order").Delete().Where("id", "=", 2),
+			"delete from order where id = $1",
+		},
+	}
+
+	for _, tt := range tests {
+		if sql := tt.builder.QuoteStyle(tt.style).Build().SQL; sql != tt.expected {
+			t.Errorf("Expected SQL: %s, got: %s", tt.expected, sql)
+		}
+	}
+}
+
+func TestDialectQuoteIdentifier(t *testing.T) {
+	if name, err := Postgres.QuoteIdentifier("public.users"); err != nil || name != `"public"."users"` {
+		t.Errorf(`Expected "public"."users", got: %s (%v)`, name, err)
+	}
+	if name, err := MySQL.QuoteIdentifier("users"); err != nil || name != "`users`" {
+		t.Errorf("Expected `users`, got: %s (%v)", name, err)
+	}
+	for _, name := range []string{"", "users.*", "users; drop table users"} {
+		if _, err := Postgres.QuoteIdentifier(name); !errors.Is(err, ErrUnsafeIdentifier) {
+			t.Errorf("Expected ErrUnsafeIdentifier for %q, got: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package query
+
+// raceEnabled skips allocation budgets, since sync.Pool drops items at
+// random under the race detector
+const raceEnabled = true
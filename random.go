@@ -0,0 +1,54 @@
+package query
+
+import "strconv"
+
+// OrderByRandom orders rows randomly, after any other ordering: rand() on
+// MySQL and random() elsewhere
+func (b *QueryBuilder) OrderByRandom() *QueryBuilder {
+	b.randomOrder = true
+	return b
+}
+
+// Sample selects roughly percent (0-100) of the table's rows. Postgres and
+// DuckDB use "tablesample system", which picks whole pages or vectors and is
+// cheap on large tables. MySQL has no TABLESAMPLE, so each row is
+// kept with probability percent/100 via rand() and the result is ordered by
+// rand(), letting a Limit take a random subset. A percent of 100 or more
+// disables sampling.
+func (b *QueryBuilder) Sample(percent float64) *QueryBuilder {
+	b.samplePct = percent
+	return b
+}
+
+func (b *QueryBuilder) sampling() bool {
+	return b.samplePct > 0 && b.samplePct < 100
+}
+
+// sampleFilter reports whether sampling falls back to a rand() filter
+func (b *QueryBuilder) sampleFilter() bool {
+	return b.sampling() && b.target() == MySQL
+}
+
+func (b *QueryBuilder) randomFunc() string {
+	if b.target() == MySQL {
+		return "rand()"
+	}
+	return "random()"
+}
+
+func (b *QueryBuilder) writeTableSample(w *sqlWriter) {
+	if !b.sampling() || b.sampleFilter() {
+		return
+	}
+	w.write(" tablesample system (")
+	w.write(strconv.FormatFloat(b.samplePct, 'f', -1, 64))
+	if b.target() == DuckDB {
+		w.write("%")
+	}
+	w.write(")")
+}
+
+func (b *QueryBuilder) writeSampleFilter(w *sqlWriter) {
+	w.write("rand() < ")
+	w.write(strconv.FormatFloat(b.samplePct/100, 'f', -1, 64))
+}
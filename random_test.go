@@ -0,0 +1,69 @@
+package query
+
+import "testing"
+
+func TestOrderByRandom(t *testing.T) {
+	tests := []struct {
+		style       ParameterStyle
+		expectedSQL string
+	}{
+		{DollarNumber, "select * from quotes order by featured desc, random() limit 1"},
+		{QuestionMark, "select * from quotes order by featured desc, rand() limit 1"},
+	}
+
+	for _, tt := range tests {
+		query := NewQueryBuilder().
+			Table("quotes").
+			ParameterPlaceholder(tt.style).
+			OrderBy("featured desc").
+			OrderByRandom().
+			Limit(1).
+			Build()
+		if query.SQL != tt.expectedSQL {
+			t.Errorf("Expected SQL: %s, got: %s", tt.expectedSQL, query.SQL)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("events").
+		As("e").
+		Sample(2.5).
+		Where("e.kind", "=", "click").
+		Build()
+
+	expectedSQL := "select * from events as e tablesample system (2.5) where e.kind = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestSampleMySQLFallback(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("events").
+		ParameterPlaceholder(QuestionMark).
+		Where("kind", "=", "click").
+		OrWhere("kind", "=", "view").
+		Sample(10).
+		Limit(100).
+		Build()
+
+	expectedSQL := "select * from events where (kind = ? or kind = ?) and rand() < 0.1 order by rand() limit 100"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().Table("events").ParameterPlaceholder(QuestionMark).Sample(10).Build()
+	expectedSQL = "select * from events where rand() < 0.1 order by rand()"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestSampleFullTable(t *testing.T) {
+	query := NewQueryBuilder().Table("events").Sample(100).Build()
+	if query.SQL != "select * from events" {
+		t.Errorf("Expected no sampling, got: %s", query.SQL)
+	}
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrReadOnly is returned when a read-only builder or Runner is asked for a
+// statement that could write
+var ErrReadOnly = errors.New("statement not allowed in read-only mode")
+
+// ReadOnly marks qb as read-only: TryBuild fails with ErrReadOnly unless it
+// is a SELECT, even if Insert, Update or Delete is called later. Returns qb
+// for chaining.
+func ReadOnly(qb *QueryBuilder) *QueryBuilder {
+	qb.readOnly = true
+	return qb
+}
+
+// RejectWrites makes the Runner refuse, before touching the database, any
+// statement that is not a read. Builders are checked by query type; other
+// Builders by their SQL, which must start with select, explain or show, or
+// be a with query containing no data-modifying keyword.
+func RejectWrites() RunnerOption {
+	return func(r *Runner) {
+		r.readOnly = true
+	}
+}
+
+var writeKeywords = map[string]bool{
+	"insert": true, "update": true, "delete": true, "merge": true,
+	"truncate": true, "copy": true, "call": true, "into": true,
+}
+
+// readOnlySQL conservatively reports whether sql can only read. Keywords in
+// string literals make it reject reads, never accept writes.
+func readOnlySQL(sql string) bool {
+	words := strings.FieldsFunc(strings.ToLower(stripComments(sql)), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	})
+	if len(words) == 0 {
+		return false
+	}
+	switch words[0] {
+	case "select", "with", "explain", "show", "values":
+	default:
+		return false
+	}
+	for _, word := range words {
+		if writeKeywords[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// stripComments removes /* ... */ comments, so words in an audit Comment
+// are not taken for statements
+func stripComments(sql string) string {
+	for {
+		start := strings.Index(sql, "/*")
+		if start < 0 {
+			return sql
+		}
+		end := strings.Index(sql[start+2:], "*/")
+		if end < 0 {
+			return sql[:start]
+		}
+		sql = sql[:start] + " " + sql[start+2+end+2:]
+	}
+}
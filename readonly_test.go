@@ -0,0 +1,57 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	if _, err := ReadOnly(NewQueryBuilder()).Table("users").TryBuild(); err != nil {
+		t.Errorf("Expected select to build, got: %v", err)
+	}
+
+	qb := ReadOnly(NewQueryBuilder()).Table("users").Delete().Where("id", "=", 1)
+	if _, err := qb.TryBuild(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got: %v", err)
+	}
+	if query := qb.Build(); query.SQL != "" {
+		t.Errorf("Expected empty query, got: %s", query.SQL)
+	}
+}
+
+func TestRunnerRejectWrites(t *testing.T) {
+	db, fake := newFakeDB(t)
+	runner := NewRunner(db, RejectWrites())
+	ctx := context.Background()
+
+	update := NewQueryBuilder().Table("users").Set("name", "x")
+	if _, err := runner.Exec(ctx, update); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly for update, got: %v", err)
+	}
+
+	if _, err := runner.Exec(ctx, staticQuery{SQL: "with gone as (delete from users returning id) select * from gone"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly for data-modifying CTE, got: %v", err)
+	}
+
+	if len(fake.Calls()) != 0 {
+		t.Errorf("Expected no statements to reach the database, got: %+v", fake.Calls())
+	}
+}
+
+func TestReadOnlySQL(t *testing.T) {
+	tests := map[string]bool{
+		"select * from users":                            true,
+		"  SELECT id FROM users WHERE id = $1":           true,
+		"with recursive t as (select 1) select * from t": true,
+		"explain select 1":                               true,
+		"select * into backup from users":                false,
+		"update users set name = $1":                     false,
+		"":                                               false,
+	}
+	for sql, expected := range tests {
+		if got := readOnlySQL(sql); got != expected {
+			t.Errorf("readOnlySQL(%q) = %v, expected %v", sql, got, expected)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownRelation is returned when a relation has not been defined for
+// a table with DefineRelations
+var ErrUnknownRelation = errors.New("unknown relation")
+
+type RelationKind int
+
+const (
+	HasOneRelation RelationKind = iota
+	HasManyRelation
+	BelongsToRelation
+	MorphManyRelation
+	MorphToRelation
+	BelongsToManyRelation
+)
+
+// Relation describes how rows of one table relate to rows of another
+type Relation struct {
+	Kind       RelationKind
+	Name       string // Relation name, defaults to the related table
+	Related    string // Related table, empty for MorphTo
+	ForeignKey string
+	LocalKey   string
+	MorphType  string // Polymorphic type column, e.g. commentable_type
+	MorphID    string // Polymorphic id column, e.g. commentable_id
+
+	// For BelongsToMany relations
+	Pivot           string
+	RelatedPivotKey string
+	PivotColumns    []string
+	PivotWheres     []PivotCondition
+}
+
+// PivotCondition restricts a BelongsToMany relation to pivot rows where
+// Column equals Value
+type PivotCondition struct {
+	Column string
+	Value  interface{}
+}
+
+var (
+	relationsMu sync.RWMutex
+	relations   = map[string]map[string]*Relation{}
+)
+
+// HasMany relates table rows to related rows whose foreignKey holds the
+// parent's localKey
+func HasMany(related, foreignKey, localKey string) *Relation {
+	return &Relation{
+		Kind:       HasManyRelation,
+		Name:       related,
+		Related:    related,
+		ForeignKey: foreignKey,
+		LocalKey:   localKey,
+	}
+}
+
+// HasOne is HasMany for a single related row
+func HasOne(related, foreignKey, localKey string) *Relation {
+	r := HasMany(related, foreignKey, localKey)
+	r.Kind = HasOneRelation
+	return r
+}
+
+// BelongsTo relates table rows to the related row whose ownerKey matches
+// the child's foreignKey
+func BelongsTo(related, foreignKey, ownerKey string) *Relation {
+	return &Relation{
+		Kind:       BelongsToRelation,
+		Name:       related,
+		Related:    related,
+		ForeignKey: foreignKey,
+		LocalKey:   ownerKey,
+	}
+}
+
+// MorphMany relates table rows to related rows pointing at them through
+// the <name>_type and <name>_id columns. The type column holds the parent
+// table name.
+func MorphMany(related, name string) *Relation {
+	return &Relation{
+		Kind:      MorphManyRelation,
+		Name:      related,
+		Related:   related,
+		LocalKey:  "id",
+		MorphType: name + "_type",
+		MorphID:   name + "_id",
+	}
+}
+
+// MorphTo is the inverse of MorphMany: each row points at a parent in the
+// table named by its <name>_type column
+func MorphTo(name string) *Relation {
+	return &Relation{
+		Kind:      MorphToRelation,
+		Name:      name,
+		LocalKey:  "id",
+		MorphType: name + "_type",
+		MorphID:   name + "_id",
+	}
+}
+
+// BelongsToMany relates table rows to related rows through pivot, where
+// foreignPivotKey references the parent's id and relatedPivotKey the
+// related row's id
+func BelongsToMany(related, pivot, foreignPivotKey, relatedPivotKey string) *Relation {
+	return &Relation{
+		Kind:            BelongsToManyRelation,
+		Name:            related,
+		Related:         related,
+		ForeignKey:      foreignPivotKey,
+		LocalKey:        "id",
+		Pivot:           pivot,
+		RelatedPivotKey: relatedPivotKey,
+	}
+}
+
+// WithPivot selects extra pivot columns, aliased as pivot_<column>
+func (r *Relation) WithPivot(columns ...string) *Relation {
+	r.PivotColumns = append(r.PivotColumns, columns...)
+	return r
+}
+
+// WherePivot restricts the relation to pivot rows where column equals
+// value. Attach and Sync write the same value into new pivot rows.
+func (r *Relation) WherePivot(column string, value interface{}) *Relation {
+	r.PivotWheres = append(r.PivotWheres, PivotCondition{Column: column, Value: value})
+	return r
+}
+
+// As renames the relation
+func (r *Relation) As(name string) *Relation {
+	r.Name = name
+	return r
+}
+
+// DefineRelations registers relations for table
+func DefineRelations(table string, rels ...*Relation) {
+	relationsMu.Lock()
+	defer relationsMu.Unlock()
+
+	if relations[table] == nil {
+		relations[table] = map[string]*Relation{}
+	}
+	for _, r := range rels {
+		relations[table][r.Name] = r
+	}
+}
+
+// LookupRelation returns the relation registered for table under name
+func LookupRelation(table, name string) (*Relation, error) {
+	relationsMu.RLock()
+	defer relationsMu.RUnlock()
+
+	r, ok := relations[table][name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s.%s", ErrUnknownRelation, table, name)
+	}
+	return r, nil
+}
+
+// RelatedQuery builds the query loading relation rows for a batch of
+// parents of table, identified by keys: the parents' local keys for HasOne,
+// HasMany and MorphMany, their foreign key values for BelongsTo. Use
+// MorphToQueries for MorphTo relations.
+func RelatedQuery(table, relation string, keys ...interface{}) (*QueryBuilder, error) {
+	r, err := LookupRelation(table, relation)
+	if err != nil {
+		return nil, err
+	}
+
+	qb := NewQueryBuilder().Table(r.Related)
+	switch r.Kind {
+	case HasOneRelation, HasManyRelation:
+		qb.WhereIn(r.ForeignKey, keys...)
+	case BelongsToRelation:
+		qb.WhereIn(r.LocalKey, keys...)
+	case MorphManyRelation:
+		qb.Where(r.MorphType, "=", table).WhereIn(r.MorphID, keys...)
+	case BelongsToManyRelation:
+		columns := []string{
+			r.Related + ".*",
+			r.Pivot + "." + r.ForeignKey + " as pivot_" + r.ForeignKey,
+		}
+		for _, column := range r.PivotColumns {
+			columns = append(columns, r.Pivot+"."+column+" as pivot_"+column)
+		}
+		qb.Select(columns...).
+			Join(r.Pivot, r.Pivot+"."+r.RelatedPivotKey+" = "+r.Related+"."+r.LocalKey).
+			WhereIn(r.Pivot+"."+r.ForeignKey, keys...)
+		for _, where := range r.PivotWheres {
+			qb.Where(r.Pivot+"."+where.Column, "=", where.Value)
+		}
+	default:
+		return nil, fmt.Errorf("relation %s.%s is polymorphic, use MorphToQueries", table, relation)
+	}
+	return qb, nil
+}
+
+// MorphToQueries builds one query per parent table for a MorphTo relation.
+// refs maps each parent table (the values of the type column) to the ids
+// referenced from it; queries are returned in table name order.
+func MorphToQueries(table, relation string, refs map[string][]interface{}) ([]*QueryBuilder, error) {
+	r, err := LookupRelation(table, relation)
+	if err != nil {
+		return nil, err
+	}
+	if r.Kind != MorphToRelation {
+		return nil, fmt.Errorf("relation %s.%s is not a MorphTo relation", table, relation)
+	}
+
+	parents := make([]string, 0, len(refs))
+	for parent := range refs {
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+
+	queries := make([]*QueryBuilder, 0, len(parents))
+	for _, parent := range parents {
+		queries = append(queries, NewQueryBuilder().
+			Table(parent).
+			WhereIn(r.LocalKey, refs[parent]...))
+	}
+	return queries, nil
+}
+
+func lookupBelongsToMany(table, relation string) (*Relation, error) {
+	r, err := LookupRelation(table, relation)
+	if err != nil {
+		return nil, err
+	}
+	if r.Kind != BelongsToManyRelation {
+		return nil, fmt.Errorf("relation %s.%s is not a BelongsToMany relation", table, relation)
+	}
+	return r, nil
+}
+
+// Attach builds one pivot insert per related key, linking them to the
+// parent identified by parentKey
+func Attach(table, relation string, parentKey interface{}, relatedKeys ...interface{}) ([]*QueryBuilder, error) {
+	r, err := lookupBelongsToMany(table, relation)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]*QueryBuilder, 0, len(relatedKeys))
+	for _, key := range relatedKeys {
+		columns := []string{r.ForeignKey, r.RelatedPivotKey}
+		values := []interface{}{parentKey, key}
+		for _, where := range r.PivotWheres {
+			columns = append(columns, where.Column)
+			values = append(values, where.Value)
+		}
+		queries = append(queries, NewQueryBuilder().
+			Table(r.Pivot).
+			InsertColumns(columns...).
+			Values(values...))
+	}
+	return queries, nil
+}
+
+// Detach builds the pivot delete unlinking relatedKeys from the parent, or
+// every related row of the relation when no keys are given
+func Detach(table, relation string, parentKey interface{}, relatedKeys ...interface{}) (*QueryBuilder, error) {
+	r, err := lookupBelongsToMany(table, relation)
+	if err != nil {
+		return nil, err
+	}
+
+	qb := NewQueryBuilder().
+		Table(r.Pivot).
+		Delete().
+		Where(r.ForeignKey, "=", parentKey)
+	if len(relatedKeys) > 0 {
+		qb.WhereIn(r.RelatedPivotKey, relatedKeys...)
+	}
+	for _, where := range r.PivotWheres {
+		qb.Where(where.Column, "=", where.Value)
+	}
+	return qb, nil
+}
+
+// Sync builds the statements making relatedKeys the exact set of related
+// rows for the parent: a Detach of the current links followed by an
+// Attach of every key. Run them in one transaction.
+func Sync(table, relation string, parentKey interface{}, relatedKeys ...interface{}) ([]*QueryBuilder, error) {
+	detach, err := Detach(table, relation, parentKey)
+	if err != nil {
+		return nil, err
+	}
+	attach, err := Attach(table, relation, parentKey, relatedKeys...)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*QueryBuilder{detach}, attach...), nil
+}
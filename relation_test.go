@@ -0,0 +1,176 @@
+package query
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+// defineTestRelations defines rels on table for the test and restores the
+// table's earlier relations when it ends
+func defineTestRelations(t *testing.T, table string, rels ...*Relation) {
+	t.Helper()
+	relationsMu.RLock()
+	previous, ok := relations[table]
+	previous = maps.Clone(previous)
+	relationsMu.RUnlock()
+
+	DefineRelations(table, rels...)
+	t.Cleanup(func() {
+		relationsMu.Lock()
+		defer relationsMu.Unlock()
+		if ok {
+			relations[table] = previous
+		} else {
+			delete(relations, table)
+		}
+	})
+}
+
+func TestRelatedQueryHasManyAndBelongsTo(t *testing.T) {
+	defineTestRelations(t, "users", HasMany("posts", "user_id", "id"))
+	defineTestRelations(t, "posts", BelongsTo("users", "user_id", "id").As("author"))
+
+	qb, err := RelatedQuery("users", "posts", 1, 2, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	query := qb.Build()
+	expectedSQL := "select * from posts where user_id in ($1, $2, $3)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	qb, err = RelatedQuery("posts", "author", 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	query = qb.Build()
+	expectedSQL = "select * from users where id in ($1)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestRelatedQueryMorphMany(t *testing.T) {
+	defineTestRelations(t, "videos", MorphMany("comments", "commentable"))
+
+	qb, err := RelatedQuery("videos", "comments", 10, 11)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	query := qb.Build()
+	expectedSQL := "select * from comments where commentable_type = $1 and commentable_id in ($2, $3)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 3 || query.Params[0] != "videos" || query.Params[1] != 10 || query.Params[2] != 11 {
+		t.Errorf("Expected params: [videos, 10, 11], got: %v", query.Params)
+	}
+}
+
+func TestMorphToQueries(t *testing.T) {
+	defineTestRelations(t, "comments", MorphTo("commentable"))
+
+	queries, err := MorphToQueries("comments", "commentable", map[string][]interface{}{
+		"videos": {10},
+		"posts":  {1, 2},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"select * from posts where id in ($1, $2)",
+		"select * from videos where id in ($1)",
+	}
+	if len(queries) != len(expected) {
+		t.Fatalf("Expected %d queries, got: %d", len(expected), len(queries))
+	}
+	for i, qb := range queries {
+		if sql := qb.Build().SQL; sql != expected[i] {
+			t.Errorf("Expected SQL: %s, got: %s", expected[i], sql)
+		}
+	}
+}
+
+func TestUnknownRelation(t *testing.T) {
+	if _, err := RelatedQuery("users", "missing"); !errors.Is(err, ErrUnknownRelation) {
+		t.Errorf("Expected ErrUnknownRelation, got: %v", err)
+	}
+}
+
+func TestRelatedQueryBelongsToMany(t *testing.T) {
+	defineTestRelations(t, "members",
+		BelongsToMany("teams", "team_members", "member_id", "team_id").
+			WithPivot("joined_at").
+			WherePivot("role", "admin").
+			As("admin_teams"))
+
+	qb, err := RelatedQuery("members", "admin_teams", 1, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	query := qb.Build()
+	expectedSQL := "select teams.*, team_members.member_id as pivot_member_id, team_members.joined_at as pivot_joined_at" +
+		" from teams JOIN team_members on team_members.team_id = teams.id" +
+		" where team_members.member_id in ($1, $2) and team_members.role = $3"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 3 || query.Params[2] != "admin" {
+		t.Errorf("Expected params: [1, 2, admin], got: %v", query.Params)
+	}
+}
+
+func TestAttachDetachSync(t *testing.T) {
+	defineTestRelations(t, "accounts",
+		BelongsToMany("tags", "account_tags", "account_id", "tag_id").WherePivot("source", "manual"))
+
+	attach, err := Attach("accounts", "tags", 5, 10, 11)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(attach) != 2 {
+		t.Fatalf("Expected 2 inserts, got: %d", len(attach))
+	}
+	query := attach[1].Build()
+	expectedSQL := "insert into account_tags (account_id, tag_id, source) values ($1, $2, $3)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 3 || query.Params[0] != 5 || query.Params[1] != 11 || query.Params[2] != "manual" {
+		t.Errorf("Expected params: [5, 11, manual], got: %v", query.Params)
+	}
+
+	detach, err := Detach("accounts", "tags", 5, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	query = detach.Build()
+	expectedSQL = "delete from account_tags where account_id = $1 and tag_id in ($2) and source = $3"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	sync, err := Sync("accounts", "tags", 5, 12)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{
+		"delete from account_tags where account_id = $1 and source = $2",
+		"insert into account_tags (account_id, tag_id, source) values ($1, $2, $3)",
+	}
+	if len(sync) != len(expected) {
+		t.Fatalf("Expected %d statements, got: %d", len(expected), len(sync))
+	}
+	for i, qb := range sync {
+		if sql := qb.Build().SQL; sql != expected[i] {
+			t.Errorf("Expected SQL: %s, got: %s", expected[i], sql)
+		}
+	}
+}
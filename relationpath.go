@@ -0,0 +1,114 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// withRelationPaths resolves dotted relation paths in where conditions.
+// Given relations registered with DefineRelations, Where("account.plan",
+// "=", "pro") on users left joins the account relation - "LEFT JOIN
+// accounts as account on account.id = users.account_id" - and longer paths
+// such as "account.owner.email" join each hop, aliased "account__owner".
+// Paths follow BelongsTo and HasOne relations only. A column qualified
+// with a table or alias of the query, or with no relation of its table,
+// is left as written; joins are added once and reused by later paths.
+//
+// It returns b when no condition uses a path, and otherwise a resolved
+// clone, so the builder itself keeps its conditions as written. Paths are
+// only supported on selects.
+func (b *QueryBuilder) withRelationPaths() (*QueryBuilder, error) {
+	path := b.relationPathIn(b.whereClauses)
+	if path == "" {
+		return b, nil
+	}
+	if b.queryType != SelectQuery {
+		return nil, fmt.Errorf("%w: relation path %q on %s statement", ErrNotSelect, path, queryTypeName(b.queryType))
+	}
+	resolved := b.Clone()
+	if err := resolved.joinRelationPaths(resolved.whereClauses); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// relationPathIn returns the first column of clauses that starts with a
+// relation of the table rather than a table or alias of the query
+func (b *QueryBuilder) relationPathIn(clauses []*WhereClause) string {
+	if b.table == "" {
+		return ""
+	}
+	for _, where := range clauses {
+		if path := b.relationPathIn(where.Group); path != "" {
+			return path
+		}
+		first, rest, ok := strings.Cut(where.Column, ".")
+		if !ok || !strings.Contains(rest, ".") && b.hasRelation(first) {
+			continue
+		}
+		if _, err := LookupRelation(strings.Fields(b.table)[0], first); !errors.Is(err, ErrUnknownRelation) {
+			return where.Column
+		}
+	}
+	return ""
+}
+
+// joinRelationPaths resolves the paths of clauses in place, see
+// withRelationPaths
+func (b *QueryBuilder) joinRelationPaths(clauses []*WhereClause) error {
+	for _, where := range clauses {
+		if err := b.joinRelationPaths(where.Group); err != nil {
+			return err
+		}
+		if where.Column == "" {
+			continue
+		}
+		column, err := b.relationPath(where.Column)
+		if err != nil {
+			return err
+		}
+		where.Column = column
+	}
+	return nil
+}
+
+// relationPath joins the relations of a dotted path and returns the
+// column qualified with the last one
+func (b *QueryBuilder) relationPath(column string) (string, error) {
+	parts := strings.Split(column, ".")
+	if len(parts) < 2 || b.table == "" || len(parts) == 2 && b.hasRelation(parts[0]) {
+		return column, nil
+	}
+
+	table, qualifier := strings.Fields(b.table)[0], b.relationNames()[0]
+	for i, name := range parts[:len(parts)-1] {
+		r, err := LookupRelation(table, name)
+		if errors.Is(err, ErrUnknownRelation) && i == 0 {
+			return column, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("%w in path %q", err, column)
+		}
+
+		var on string
+		alias := strings.Join(parts[:i+1], "__")
+		switch r.Kind {
+		case BelongsToRelation:
+			on = alias + "." + r.LocalKey + " = " + qualifier + "." + r.ForeignKey
+		case HasOneRelation:
+			on = alias + "." + r.ForeignKey + " = " + qualifier + "." + r.LocalKey
+		default:
+			return "", fmt.Errorf("relation %s.%s in path %q is not a BelongsTo or HasOne relation", table, name, column)
+		}
+		if !b.hasRelation(alias) {
+			if alias == r.Related {
+				b.LeftJoin(r.Related, on)
+			} else {
+				b.LeftJoinAs(r.Related, alias, on)
+			}
+		}
+		table, qualifier = r.Related, alias
+	}
+	return qualifier + "." + parts[len(parts)-1], nil
+}
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func defineWorkspaceRelations(t *testing.T) {
+	defineTestRelations(t, "subscribers", BelongsTo("workspaces", "workspace_id", "id").As("workspace"))
+	defineTestRelations(t, "workspaces",
+		BelongsTo("people", "owner_id", "id").As("owner"),
+		HasOne("billing_profiles", "workspace_id", "id").As("billing"),
+		HasMany("invites", "workspace_id", "id"))
+}
+
+func TestRelationPathJoins(t *testing.T) {
+	defineWorkspaceRelations(t)
+
+	query := NewQueryBuilder().
+		Table("subscribers").
+		Where("workspace.plan", "=", "pro").
+		WhereGroup(func(q *QueryBuilder) {
+			q.Where("workspace.owner.email", "like", "%@example.com").
+				OrWhere("workspace.billing.country", "=", "NL")
+		}).
+		Where("subscribers.active", "=", true).
+		Build()
+
+	expectedSQL := "select * from subscribers" +
+		" LEFT JOIN workspaces as workspace on workspace.id = subscribers.workspace_id" +
+		" LEFT JOIN people as workspace__owner on workspace__owner.id = workspace.owner_id" +
+		" LEFT JOIN billing_profiles as workspace__billing on workspace__billing.workspace_id = workspace.id" +
+		" where workspace.plan = $1 and (workspace__owner.email like $2 or workspace__billing.country = $3) and subscribers.active = $4"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestRelationPathKeepsJoinedNames(t *testing.T) {
+	defineWorkspaceRelations(t)
+
+	query := NewQueryBuilder().
+		Table("subscribers").
+		Where("workspace.plan", "=", "pro").
+		Join("workspaces workspace", "workspace.id = subscribers.workspace_id").
+		Where("public.flags.on", "=", true).
+		Build()
+
+	expectedSQL := "select * from subscribers JOIN workspaces workspace on workspace.id = subscribers.workspace_id where workspace.plan = $1 and public.flags.on = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestRelationPathErrors(t *testing.T) {
+	defineWorkspaceRelations(t)
+
+	if _, err := NewQueryBuilder().Table("subscribers").Where("workspace.invites.code", "=", "x").TryBuild(); err == nil {
+		t.Error("Expected an error for a HasMany relation in a path")
+	}
+	if _, err := NewQueryBuilder().Table("subscribers").Where("workspace.missing.id", "=", 1).TryBuild(); err == nil {
+		t.Error("Expected an error for an unknown relation in a path")
+	}
+}
+
+func TestRelationPathLeavesBuilderUnchanged(t *testing.T) {
+	defineWorkspaceRelations(t)
+
+	qb := NewQueryBuilder().Table("subscribers").Where("workspace.plan", "=", "pro")
+	first, err := qb.TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := qb.TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.SQL != second.SQL {
+		t.Errorf("Expected the same SQL twice, got: %s and %s", first.SQL, second.SQL)
+	}
+	if joins := qb.GetJoins(); len(joins) != 0 {
+		t.Errorf("Expected no joins on the builder, got: %v", joins)
+	}
+	if wheres := qb.GetWheres(); wheres[0].Column != "workspace.plan" {
+		t.Errorf("Expected the condition as written, got: %s", wheres[0].Column)
+	}
+}
+
+func TestRelationPathWritesUnsupported(t *testing.T) {
+	defineWorkspaceRelations(t)
+
+	builders := []*QueryBuilder{
+		NewQueryBuilder().Table("subscribers").Update(map[string]interface{}{"active": false}).Where("workspace.plan", "=", "free"),
+		NewQueryBuilder().Table("subscribers").Delete().Where("workspace.plan", "=", "free"),
+	}
+	for _, qb := range builders {
+		if _, err := qb.TryBuild(); !errors.Is(err, ErrNotSelect) {
+			t.Errorf("Expected ErrNotSelect, got: %v", err)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrNoColumns is returned by TryBuild when column rewrites drop every
+// selected column
+var ErrNoColumns = errors.New("no columns left to select")
+
+// ColumnRewrite is consulted for each column of a select list, with the
+// table the column is read from (resolved through aliases and joins) and
+// the column name without any "as" alias. Return keep false to drop the
+// column, or a non-empty replacement to select that expression under the
+// column's name, for example Raw("mask(email)").
+//
+// While rewrites are present, "*" and "t.*" are expanded into the columns
+// registered with RegisterColumns, so no column escapes them; a star whose
+// table has none fails the build with ErrUnknownTable. SelectRaw
+// expressions are offered with their SQL, less any "as" alias, as the
+// column: dropping one removes it, a replacement is selected under the
+// expression's alias.
+type ColumnRewrite func(table, column string) (replacement Expr, keep bool)
+
+// RewriteColumns adds a rewrite applied to the select list every time the
+// query is built. Rewrites run in the order added; a column dropped by one
+// is not offered to the next.
+func (b *QueryBuilder) RewriteColumns(fn ColumnRewrite) *QueryBuilder {
+	b.columnRewrites = append(b.columnRewrites, fn)
+	return b
+}
+
+// WithColumnRewrite applies fn to every builder the Runner executes, after
+// the builder's own rewrites: a *QueryBuilder, or each branch of a Union
+// or UnionFeed. Other builders, such as Tree, fail rather than run
+// unmasked. A Runner per role, or per request, keeps masking out of
+// handler code. The caller's builder is not modified.
+func WithColumnRewrite(fn ColumnRewrite) RunnerOption {
+	return func(r *Runner) {
+		r.columnRewrites = append(r.columnRewrites, fn)
+	}
+}
+
+// rewritten returns qb with the Runner's column rewrites added
+func (r *Runner) rewritten(qb Builder) (Builder, error) {
+	switch qb := qb.(type) {
+	case *QueryBuilder:
+		b := qb.Clone()
+		b.columnRewrites = append(b.columnRewrites, r.columnRewrites...)
+		return b, nil
+	case *Compound:
+		c := *qb
+		c.branches = r.rewrittenBranches(qb.branches)
+		return &c, nil
+	case *Feed:
+		f := *qb
+		f.branches = r.rewrittenBranches(qb.branches)
+		return &f, nil
+	case notifyQuery:
+		return qb, nil
+	}
+	return nil, fmt.Errorf("query: column rewrites cannot be applied to %T", qb)
+}
+
+func (r *Runner) rewrittenBranches(branches []*QueryBuilder) []*QueryBuilder {
+	rewritten := make([]*QueryBuilder, len(branches))
+	for i, branch := range branches {
+		rewritten[i] = branch.Clone()
+		rewritten[i].columnRewrites = append(rewritten[i].columnRewrites, r.columnRewrites...)
+	}
+	return rewritten
+}
+
+// rewriteTarget is one select column as the rewrites see it
+type rewriteTarget struct {
+	column  string // as selected, e.g. "u.email" or "name as display_name"
+	table   string // the table the column is read from
+	replace *Expr  // ReplaceStar expression selected under the column's name
+}
+
+// selectSource is a table columns can be selected from
+type selectSource struct {
+	name  string // alias, or the table when it has none
+	table string
+	known bool // false for derived tables and functions, whose columns are not registered
+}
+
+func (b *QueryBuilder) selectSources() []selectSource {
+	var sources []selectSource
+	add := func(table, alias string, known bool) {
+		if fields := strings.Fields(table); len(fields) == 2 {
+			table, alias = fields[0], fields[1]
+		}
+		name := table
+		if alias != "" {
+			name = aliasName(alias)
+		}
+		sources = append(sources, selectSource{name: name, table: table, known: known})
+	}
+	add(b.table, b.tableAlias, b.fromExpr == nil && b.fromSub == nil)
+	for _, join := range b.joinClauses {
+		add(join.Table, join.Alias, join.Source == nil)
+	}
+	return sources
+}
+
+// columnTable returns the table a select column is read from: the one its
+// qualifier names, or the builder's table
+func (b *QueryBuilder) columnTable(sources []selectSource, column string) string {
+	if i := strings.LastIndexByte(column, '.'); i > 0 {
+		qualifier := column[:i]
+		for _, source := range sources {
+			if source.name == qualifier || strings.HasSuffix(source.name, "."+qualifier) {
+				return source.table
+			}
+		}
+	}
+	return sources[0].table
+}
+
+// rewriteTargets returns the select columns with each "*" and "t.*"
+// expanded into the registered columns of its tables
+func (b *QueryBuilder) rewriteTargets() ([]rewriteTarget, error) {
+	sources := b.selectSources()
+	var targets []rewriteTarget
+	for _, column := range b.columns {
+		if column != "*" && !hasSuffixStar(column) {
+			targets = append(targets, rewriteTarget{column: column, table: b.columnTable(sources, columnName(column))})
+			continue
+		}
+
+		qualifier := strings.TrimSuffix(strings.TrimSuffix(column, "*"), ".")
+		qualify := qualifier != "" || len(sources) > 1
+		matched := false
+		for _, source := range sources {
+			if qualifier != "" && source.name != qualifier {
+				continue
+			}
+			matched = true
+			columns, ok := LookupColumns(source.table)
+			if !source.known || !ok {
+				return nil, fmt.Errorf("%w: no registered columns of %s to expand %s for column rewrites", ErrUnknownTable, source.table, column)
+			}
+			for _, name := range columns {
+				if slices.Contains(b.starExclude, name) {
+					continue
+				}
+				target := rewriteTarget{column: name, table: source.table}
+				if qualify {
+					target.column = source.name + "." + name
+				}
+				for _, replace := range b.starReplace {
+					if replace.Column == name {
+						target.replace = &replace.Expr
+					}
+				}
+				targets = append(targets, target)
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("%w: %s selects from no table of the query", ErrUnknownTable, column)
+		}
+	}
+	return targets, nil
+}
+
+// rewriteColumn applies the rewrites to one select column of table
+func (b *QueryBuilder) rewriteColumn(table, column string) (Expr, bool) {
+	var replacement Expr
+	name := columnName(column)
+	for _, rewrite := range b.columnRewrites {
+		expr, keep := rewrite(table, name)
+		if !keep {
+			return Expr{}, false
+		}
+		if expr.SQL != "" {
+			replacement = expr
+		}
+	}
+	return replacement, true
+}
+
+// writeRewrittenSelectList writes the select list of a builder with column
+// rewrites, see ColumnRewrite
+func (b *QueryBuilder) writeRewrittenSelectList(w *sqlWriter) int {
+	targets, err := b.rewriteTargets()
+	if err != nil {
+		// unreachable after validateRewrites
+		return 0
+	}
+	written := 0
+	for _, target := range targets {
+		replacement, keep := b.rewriteColumn(target.table, target.column)
+		if !keep {
+			continue
+		}
+		if written > 0 {
+			w.write(", ")
+		}
+		written++
+		switch {
+		case replacement.SQL != "":
+			w.expr(replacement)
+		case target.replace != nil:
+			w.expr(*target.replace)
+		default:
+			w.column(target.column)
+			continue
+		}
+		w.write(" as ")
+		w.ident(columnAlias(target.column))
+	}
+	for _, expr := range b.selectExprs {
+		replacement, keep := b.rewriteColumn(b.table, expr.SQL)
+		if !keep {
+			continue
+		}
+		if written > 0 {
+			w.write(", ")
+		}
+		written++
+		if replacement.SQL == "" {
+			w.expr(expr)
+			continue
+		}
+		w.expr(replacement)
+		if alias := exprAlias(expr.SQL); alias != "" {
+			w.write(" as ")
+			w.ident(alias)
+		}
+	}
+	return written
+}
+
+func (b *QueryBuilder) validateRewrites() error {
+	if b.queryType != SelectQuery || len(b.columnRewrites) == 0 {
+		return nil
+	}
+	targets, err := b.rewriteTargets()
+	if err != nil {
+		return err
+	}
+	if len(b.selectSubs) > 0 {
+		return nil
+	}
+	for _, target := range targets {
+		if _, keep := b.rewriteColumn(target.table, target.column); keep {
+			return nil
+		}
+	}
+	for _, expr := range b.selectExprs {
+		if _, keep := b.rewriteColumn(b.table, expr.SQL); keep {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: from %s", ErrNoColumns, b.table)
+}
+
+// columnName strips an "as alias" suffix from a select column
+func columnName(column string) string {
+	if fields := strings.Fields(column); len(fields) == 3 && strings.EqualFold(fields[1], "as") {
+		return fields[0]
+	}
+	return column
+}
+
+// columnAlias is the name a select column appears under in the result
+func columnAlias(column string) string {
+	if fields := strings.Fields(column); len(fields) == 3 && strings.EqualFold(fields[1], "as") {
+		return fields[2]
+	}
+	if i := strings.LastIndexByte(column, '.'); i >= 0 {
+		return column[i+1:]
+	}
+	return column
+}
@@ -0,0 +1,196 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// supportRole masks email and hides password hashes
+func supportRole(table, column string) (Expr, bool) {
+	switch column {
+	case "password_hash", "u.password_hash":
+		return Expr{}, false
+	case "email", "u.email":
+		return Raw("mask(" + column + ")"), true
+	}
+	return Expr{}, true
+}
+
+func TestRewriteColumns(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users u").
+		Select("u.id", "u.email", "u.password_hash", "u.name as display_name").
+		SelectRaw("count(*) over () as total").
+		RewriteColumns(supportRole).
+		Build()
+
+	expectedSQL := "select u.id, mask(u.email) as email, u.name as display_name, count(*) over () as total from users u"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestRewriteColumnsWithArgs(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		Select("id", "phone").
+		Where("id", "=", 7).
+		RewriteColumns(func(table, column string) (Expr, bool) {
+			if column == "phone" {
+				return Raw("overlay(phone placing ? from 1 for 6)", "******"), true
+			}
+			return Expr{}, true
+		}).
+		Build()
+
+	expectedSQL := "select id, overlay(phone placing $1 from 1 for 6) as phone from users where id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[0] != "******" || query.Params[1] != 7 {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestRewriteColumnsDropsEverything(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("users").
+		Select("password_hash").
+		RewriteColumns(supportRole).
+		TryBuild()
+	if !errors.Is(err, ErrNoColumns) {
+		t.Errorf("Expected ErrNoColumns, got: %v", err)
+	}
+}
+
+func TestRunnerWithColumnRewrite(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select id, mask(email) as email from users", []string{"id", "email"})
+	runner := NewRunner(db, WithColumnRewrite(supportRole))
+
+	qb := NewQueryBuilder().Table("users").Select("id", "email")
+	rows, err := runner.Query(context.Background(), qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows.Close()
+
+	calls := fake.Calls()
+	expectedSQL := "select id, mask(email) as email from users"
+	if len(calls) != 1 || calls[0].SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %+v", expectedSQL, calls)
+	}
+	if query := qb.Build(); query.SQL != "select id, email from users" {
+		t.Errorf("Expected caller's builder to be untouched, got: %s", query.SQL)
+	}
+}
+
+func TestRewriteColumnsExpandsStar(t *testing.T) {
+	RegisterColumns("rewrite_users", "id", "email", "password_hash")
+	RegisterColumns("rewrite_orders", "id", "user_id", "email")
+
+	var tables []string
+	record := func(table, column string) (Expr, bool) {
+		tables = append(tables, table+":"+column)
+		return supportRole(table, column)
+	}
+
+	query, err := NewQueryBuilder().Table("rewrite_users").RewriteColumns(supportRole).TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := "select id, mask(email) as email from rewrite_users"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query, err = NewQueryBuilder().Table("rewrite_users u").
+		Select("u.id", "o.*").
+		JoinAs("rewrite_orders", "o", "o.user_id = u.id").
+		RewriteColumns(record).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL = "select u.id, o.id, o.user_id, o.email from rewrite_users u JOIN rewrite_orders as o on o.user_id = u.id"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if !slices.Contains(tables, "rewrite_users:u.id") || !slices.Contains(tables, "rewrite_orders:o.email") {
+		t.Errorf("Expected columns with their resolved tables, got: %v", tables)
+	}
+}
+
+func TestRewriteColumnsUnexpandableStar(t *testing.T) {
+	_, err := NewQueryBuilder().Table("rewrite_unregistered").RewriteColumns(supportRole).TryBuild()
+	if !errors.Is(err, ErrUnknownTable) {
+		t.Errorf("Expected ErrUnknownTable, got: %v", err)
+	}
+}
+
+func TestRewriteColumnsSelectRaw(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		Select("id").
+		SelectRaw("lower(email) as email").
+		SelectRaw("password_hash").
+		RewriteColumns(func(table, column string) (Expr, bool) {
+			switch column {
+			case "password_hash":
+				return Expr{}, false
+			case "lower(email)":
+				return Raw("mask(email)"), true
+			}
+			return Expr{}, true
+		}).
+		Build()
+
+	expectedSQL := "select id, mask(email) as email from users"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestRunnerWithColumnRewriteBranches(t *testing.T) {
+	db, fake := newFakeDB(t)
+	runner := NewRunner(db, WithColumnRewrite(supportRole))
+	ctx := context.Background()
+
+	union := Union(
+		NewQueryBuilder().Table("users").Select("id", "email"),
+		NewQueryBuilder().Table("admins").Select("id", "email"),
+	)
+	expectedSQL := "select id, mask(email) as email from users union select id, mask(email) as email from admins"
+	fake.On(expectedSQL, []string{"id", "email"})
+	rows, err := runner.Query(ctx, union)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows.Close()
+
+	feed := UnionFeed(
+		NewQueryBuilder().Table("users").Select("id", "email", "password_hash"),
+		NewQueryBuilder().Table("admins").Select("id", "email"),
+	)
+	expectedFeedSQL := "(select id, mask(email) as email, 'users' as source from users) union all (select id, mask(email) as email, 'admins' as source from admins)"
+	fake.On(expectedFeedSQL, []string{"id", "email", "source"})
+	rows, err = runner.Query(ctx, feed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows.Close()
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0].SQL != expectedSQL || calls[1].SQL != expectedFeedSQL {
+		t.Errorf("Expected SQL: %s and %s, got: %+v", expectedSQL, expectedFeedSQL, calls)
+	}
+	if query := union.Build(); query.SQL != "select id, email from users union select id, email from admins" {
+		t.Errorf("Expected caller's branches to be untouched, got: %s", query.SQL)
+	}
+
+	if _, err := runner.Query(ctx, Tree("categories").ConnectBy("parent_id", "id")); err == nil {
+		t.Error("Expected a builder the rewrites cannot reach to fail")
+	}
+}
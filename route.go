@@ -0,0 +1,46 @@
+package query
+
+// Tag labels the query for routing: a Runner configured with RouteTag runs
+// tagged queries on the pool registered for the tag
+func (b *QueryBuilder) Tag(tag string) *QueryBuilder {
+	b.tag = tag
+	return b
+}
+
+// RouteTag makes the Runner run builders tagged with tag on db instead of
+// its own DB - e.g. a pool with its own connection limit, a read replica,
+// or a user mapped to a resource group - so heavy reporting queries cannot
+// exhaust the pool serving requests. Untagged builders, and tags without a
+// route, use the Runner's DB.
+func RouteTag(tag string, db DB) RunnerOption {
+	return func(r *Runner) {
+		if r.routes == nil {
+			r.routes = map[string]DB{}
+		}
+		r.routes[tag] = db
+	}
+}
+
+// route returns the DB qb runs on
+func (r *Runner) route(qb Builder) DB {
+	if b, ok := qb.(*QueryBuilder); ok && b.tag != "" {
+		if db, ok := r.routes[b.tag]; ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+// routed returns r, or a copy of r running everything on the DB qb is
+// routed to, for helpers that run several statements for one builder -
+// such as in one transaction - and must stay on its route
+func (r *Runner) routed(qb Builder) *Runner {
+	db := r.route(qb)
+	if db == r.db {
+		return r
+	}
+	routed := *r
+	routed.db = db
+	routed.routes = nil
+	return &routed
+}
@@ -0,0 +1,110 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestRouteTag(t *testing.T) {
+	oltp, oltpFake := newFakeDB(t)
+	analytics, analyticsFake := newFakeDB(t)
+	oltpFake.On("select * from orders where id = $1", []string{"id"}, []driver.Value{int64(1)})
+	analyticsFake.On("select sum(total) from orders", []string{"sum"}, []driver.Value{int64(10)})
+	oltpFake.On("select * from users", []string{"id"})
+
+	runner := NewRunner(oltp, RouteTag("analytics", analytics))
+	ctx := context.Background()
+
+	rows, err := runner.Query(ctx, NewQueryBuilder().Table("orders").Where("id", "=", 1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows.Close()
+
+	report := NewQueryBuilder().Table("orders").SelectRaw("sum(total)").Tag("analytics")
+	if report.GetTag() != "analytics" {
+		t.Errorf("Expected tag analytics, got: %s", report.GetTag())
+	}
+	row, err := runner.QueryRow(ctx, report)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var sum int64
+	if err := row.Scan(&sum); err != nil || sum != 10 {
+		t.Errorf("Expected 10 from the analytics pool, got: %d (%v)", sum, err)
+	}
+
+	rows, err = runner.Query(ctx, NewQueryBuilder().Table("users").Tag("unrouted"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows.Close()
+
+	if calls := oltpFake.Calls(); len(calls) != 2 {
+		t.Errorf("Expected two statements on the default pool, got: %v", calls)
+	}
+	if calls := analyticsFake.Calls(); len(calls) != 1 || calls[0].SQL != "select sum(total) from orders" {
+		t.Errorf("Expected the tagged statement on the analytics pool, got: %v", calls)
+	}
+}
+
+func TestRouteTagPipeline(t *testing.T) {
+	oltp, oltpFake := newFakeDB(t)
+	analytics, analyticsFake := newFakeDB(t)
+	analyticsFake.On("select count(*) from users", []string{"count"}, []driver.Value{int64(42)})
+	analyticsFake.On("select count(*) from orders", []string{"count"}, []driver.Value{int64(7)})
+
+	runner := NewRunner(oltp, RouteTag("analytics", analytics))
+	results, err := runner.Pipeline().
+		Add(NewQueryBuilder().Table("users").Select("count(*)").Tag("analytics")).
+		Add(NewQueryBuilder().Table("orders").Select("count(*)").Tag("analytics")).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[1].Rows[0][0] != int64(7) {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+	if calls := oltpFake.Calls(); len(calls) != 0 {
+		t.Errorf("Expected nothing on the default pool, got: %v", calls)
+	}
+
+	_, err = runner.Pipeline().
+		Add(NewQueryBuilder().Table("users").Select("count(*)").Tag("analytics")).
+		Add(NewQueryBuilder().Table("orders").Select("count(*)")).
+		Run(context.Background())
+	if !errors.Is(err, ErrMixedRoutes) {
+		t.Errorf("Expected ErrMixedRoutes, got: %v", err)
+	}
+}
+
+func TestRouteTagTransactions(t *testing.T) {
+	oltp, oltpFake := newFakeDB(t)
+	analytics, analyticsFake := newFakeDB(t)
+	analyticsFake.OnExec("update reports set status = $1 where id = $2", 1)
+	analyticsFake.On("insert into reports (name) values ($1) returning id, name", []string{"id", "name"}, []driver.Value{int64(1), "a"}, []driver.Value{int64(2), "b"})
+	ctx := context.Background()
+
+	runner := NewRunner(oltp, RouteTag("analytics", analytics), MaxParams(1))
+	_, err := runner.WithOutbox("outbox").Exec(ctx,
+		NewQueryBuilder().Table("reports").Set("status", "done").Where("id", "=", 3).Tag("analytics"),
+		Event{Topic: "report.done", Payload: "3"},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	insert := NewQueryBuilder().Table("reports").InsertColumns("name").AddRow("a").AddRow("b").Tag("analytics")
+	if _, err := InsertIDs[int64](ctx, runner, insert, "id", "name"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if calls, txs := oltpFake.Calls(), oltpFake.Txs(); len(calls) != 0 || len(txs) != 0 {
+		t.Errorf("Expected nothing on the default pool, got: %v %+v", calls, txs)
+	}
+	if txs := analyticsFake.Txs(); len(txs) != 2 || !txs[0].Committed || !txs[1].Committed {
+		t.Errorf("Expected two committed transactions on the analytics pool, got: %+v", txs)
+	}
+}
@@ -0,0 +1,239 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+var rsqlOperators = map[string]string{
+	"==":    "=",
+	"!=":    "<>",
+	"=gt=":  ">",
+	">":     ">",
+	"=ge=":  ">=",
+	">=":    ">=",
+	"=lt=":  "<",
+	"<":     "<",
+	"=le=":  "<=",
+	"<=":    "<=",
+	"=in=":  "in",
+	"=out=": "not in",
+}
+
+// rsqlReserved are the characters that end an unquoted selector or value
+const rsqlReserved = "\"'();,=!~<> \t\r\n"
+
+// WhereRSQL adds the conditions of an RSQL/FIQL expression such as
+// "age>18;name==Jo*". ";" (or "and") binds tighter than "," (or "or"),
+// and parentheses group. Comparisons are ==, !=, =gt= (>), =ge= (>=),
+// =lt= (<), =le= (<=), =in=(a,b) and =out=(a,b); a "*" in an == or !=
+// value is a wildcard and turns the comparison into like or not like.
+// Unquoted numeric values are bound as numbers, everything else as
+// strings. Selectors are looked up in allowed (public name to column);
+// anything unrecognized is reported by TryBuild as ErrInvalidFilter.
+func (b *QueryBuilder) WhereRSQL(filter string, allowed map[string]string) *QueryBuilder {
+	if strings.TrimSpace(filter) == "" {
+		return b
+	}
+	p := &rsqlParser{input: filter, allowed: allowed}
+	clauses, err := p.or(0)
+	if err != nil {
+		return b.fail(err)
+	}
+	if p.skipSpace(); p.pos < len(p.input) {
+		return b.fail(fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidFilter, p.input[p.pos:], p.pos))
+	}
+	b.whereClauses = append(b.whereClauses, documentGroup(clauses, "and"))
+	return b
+}
+
+type rsqlParser struct {
+	input   string
+	pos     int
+	allowed map[string]string
+}
+
+func (p *rsqlParser) skipSpace() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\r\n", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// accept consumes the separator for "and" or "or", written either as its
+// symbol or as the word
+func (p *rsqlParser) accept(sep string) bool {
+	p.skipSpace()
+	symbol := map[string]string{"and": ";", "or": ","}[sep]
+	if strings.HasPrefix(p.input[p.pos:], symbol) {
+		p.pos += len(symbol)
+		return true
+	}
+	rest := p.input[p.pos:]
+	if len(rest) > len(sep) && strings.EqualFold(rest[:len(sep)], sep) && strings.IndexByte(" \t\r\n", rest[len(sep)]) >= 0 {
+		p.pos += len(sep)
+		return true
+	}
+	return false
+}
+
+func (p *rsqlParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.input) || p.input[p.pos] != c {
+		return p.unexpected(fmt.Sprintf("expected %q", c))
+	}
+	p.pos++
+	return nil
+}
+
+func (p *rsqlParser) unexpected(what string) error {
+	if p.pos >= len(p.input) {
+		return fmt.Errorf("%w: %s at end of filter", ErrInvalidFilter, what)
+	}
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidFilter, what, p.pos)
+}
+
+func (p *rsqlParser) or(depth int) ([]*WhereClause, error) {
+	return parseJoined(p.accept, "or", func() ([]*WhereClause, error) {
+		return parseJoined(p.accept, "and", func() ([]*WhereClause, error) {
+			return p.constraint(depth)
+		})
+	})
+}
+
+func (p *rsqlParser) constraint(depth int) ([]*WhereClause, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		if depth >= maxDocumentDepth {
+			return nil, fmt.Errorf("%w: filter nested deeper than %d", ErrInvalidFilter, maxDocumentDepth)
+		}
+		p.pos++
+		clauses, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return clauses, p.expect(')')
+	}
+
+	selector := p.unreserved()
+	if selector == "" {
+		return nil, p.unexpected("expected a selector")
+	}
+	column, ok := p.allowed[selector]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, selector)
+	}
+
+	p.skipSpace()
+	op := p.comparison()
+	operator, ok := rsqlOperators[op]
+	if !ok {
+		return nil, p.unexpected("expected a comparison operator")
+	}
+
+	if operator == "in" || operator == "not in" {
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		var values []interface{}
+		for {
+			value, _, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if p.skipSpace(); p.pos < len(p.input) && p.input[p.pos] == ',' {
+				p.pos++
+				continue
+			}
+			break
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		if operator == "in" {
+			return []*WhereClause{{Column: column, Operator: "in", Value: values, JoinType: "and"}}, nil
+		}
+		// not in, as one inequality per value
+		clauses := make([]*WhereClause, len(values))
+		for i, value := range values {
+			clauses[i] = &WhereClause{Column: column, Operator: "<>", Value: value, JoinType: "and"}
+		}
+		return clauses, nil
+	}
+
+	value, wildcard, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if wildcard && (operator == "=" || operator == "<>") {
+		parts := strings.Split(value.(string), "*")
+		for i, part := range parts {
+			parts[i] = EscapeLike(part)
+		}
+		value = strings.Join(parts, "%")
+		operator = map[string]string{"=": "like", "<>": "not like"}[operator]
+	}
+	return []*WhereClause{{Column: column, Operator: operator, Value: value, JoinType: "and"}}, nil
+}
+
+// comparison reads "==", "!=", "<", "<=", ">", ">=" or "=name="
+func (p *rsqlParser) comparison() string {
+	rest := p.input[p.pos:]
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	if strings.HasPrefix(rest, "=") {
+		if end := strings.IndexByte(rest[1:], '='); end > 0 {
+			p.pos += end + 2
+			return strings.ToLower(rest[:end+2])
+		}
+	}
+	return ""
+}
+
+func (p *rsqlParser) unreserved() string {
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte(rsqlReserved, p.input[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// value reads a quoted or unquoted argument, reporting whether it contains
+// a "*" wildcard. Unquoted numbers are returned as numbers.
+func (p *rsqlParser) value() (interface{}, bool, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && (p.input[p.pos] == '\'' || p.input[p.pos] == '"') {
+		quote := p.input[p.pos]
+		p.pos++
+		var s strings.Builder
+		for {
+			if p.pos >= len(p.input) {
+				return nil, false, fmt.Errorf("%w: unterminated string", ErrInvalidFilter)
+			}
+			c := p.input[p.pos]
+			p.pos++
+			switch {
+			case c == quote:
+				return s.String(), strings.Contains(s.String(), "*"), nil
+			case c == '\\' && p.pos < len(p.input):
+				s.WriteByte(p.input[p.pos])
+				p.pos++
+			default:
+				s.WriteByte(c)
+			}
+		}
+	}
+
+	raw := p.unreserved()
+	if raw == "" {
+		return nil, false, p.unexpected("expected a value")
+	}
+	if n, ok := parseNumber(raw); ok {
+		return n, false, nil
+	}
+	return raw, strings.Contains(raw, "*"), nil
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWhereRSQL(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		WhereRSQL("age>18;name==Jo*", odataFields).
+		Build()
+
+	expectedSQL := "select * from users where (age > $1 and name like $2)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[0] != int64(18) || query.Params[1] != "Jo%" {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestWhereRSQLGroups(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		WhereRSQL(`status=in=(active,trial),(vip==true;age=out=(1,2)) or name=="a b"`, odataFields).
+		Build()
+
+	expectedSQL := "select * from users where (status in ($1, $2) or (vip = $3 and (age <> $4 and age <> $5)) or name = $6)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[2] != "true" || query.Params[3] != int64(1) || query.Params[5] != "a b" {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestWhereRSQLErrors(t *testing.T) {
+	filters := []string{
+		"password==x",
+		"age=between=1",
+		"age>",
+		"age>1;",
+		"(age>1",
+		"age=in=(1",
+		"name=='open",
+	}
+	for _, filter := range filters {
+		_, err := NewQueryBuilder().Table("users").WhereRSQL(filter, odataFields).TryBuild()
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s: expected ErrInvalidFilter, got: %v", filter, err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// DB is the subset of *sql.DB, *sql.Conn and *sql.Tx used by Runner
+type DB interface {
+	Querier
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Builder is anything that can produce a Query. *QueryBuilder implements
+// it; services that only need the built statement can depend on Builder
+// and receive a stub in tests.
+type Builder interface {
+	Build() Query
+	TryBuild() (Query, error)
+}
+
+// Executor runs builders. *Runner implements it; see the querytest package
+// for an in-memory fake.
+type Executor interface {
+	Query(ctx context.Context, qb Builder) (*sql.Rows, error)
+	QueryRow(ctx context.Context, qb Builder) (*sql.Row, error)
+	Exec(ctx context.Context, qb Builder) (sql.Result, error)
+}
+
+var _ Executor = (*Runner)(nil)
+
+// Runner executes builders against a database, applying cross-cutting
+// options such as diagnostics to every statement
+type Runner struct {
+	db DB
+
+	// Reject anything but reads, see RejectWrites
+	readOnly bool
+
+	// Select list rewrites applied to every builder, see WithColumnRewrite
+	columnRewrites []ColumnRewrite
+
+	// Server-side timeouts from context deadlines, see PropagateDeadline
+	deadlineHints bool
+
+	// Row cap applied to selects, see MaxRows
+	maxRows int
+
+	// Complexity budget, see MaxComplexity
+	maxComplexity int
+
+	// Bind parameter limit for splitting inserts, see MaxParams
+	maxParams    int
+	maxParamsSet bool
+
+	// Fingerprint allow-list, see RequireAllowed and ReportUnallowed
+	allowList       bool
+	allowListLogger Logger
+
+	// N+1 detection, see DetectNPlusOne
+	nPlusOneThreshold int
+	nPlusOneLogger    Logger
+
+	// Pools for tagged builders, see RouteTag
+	routes map[string]DB
+
+	// Connections for LISTEN, see ListenWith
+	listen func(ctx context.Context) (Listener, error)
+
+	// Receives captured changes, see OnChange
+	changeHook ChangeHook
+
+	// Running statements, see TrackInFlight
+	inFlight *inFlightRegistry
+
+	// Index suggestions, see ReportSeqScans
+	seqScanRows   float64
+	seqScanLogger Logger
+	seqScanSeen   *sync.Map
+}
+
+// RunnerOption configures a Runner
+type RunnerOption func(*Runner)
+
+// Logger receives diagnostic messages from a Runner. *log.Logger
+// satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+func NewRunner(db DB, opts ...RunnerOption) *Runner {
+	r := &Runner{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Query builds qb and runs it, returning the rows
+func (r *Runner) Query(ctx context.Context, qb Builder) (*sql.Rows, error) {
+	q, err := r.prepare(ctx, qb)
+	if err != nil {
+		return nil, err
+	}
+	db, release, err := r.deadlineDB(ctx, qb)
+	if err != nil {
+		return nil, err
+	}
+	defer r.track(ctx, qb, q)()
+	rows, err := db.QueryContext(ctx, q.SQL, q.Params...)
+	if release != nil {
+		if err != nil {
+			release()
+		} else {
+			context.AfterFunc(ctx, release)
+		}
+	}
+	return rows, err
+}
+
+// QueryRow builds qb and runs it, returning at most one row
+func (r *Runner) QueryRow(ctx context.Context, qb Builder) (*sql.Row, error) {
+	q, err := r.prepare(ctx, qb)
+	if err != nil {
+		return nil, err
+	}
+	db, release, err := r.deadlineDB(ctx, qb)
+	if err != nil {
+		return nil, err
+	}
+	defer r.track(ctx, qb, q)()
+	row := db.QueryRowContext(ctx, q.SQL, q.Params...)
+	if release != nil {
+		context.AfterFunc(ctx, release)
+	}
+	return row, nil
+}
+
+// Exec builds qb and executes it. Multi-row inserts with more params than
+// the dialect allows (65535 on Postgres and MySQL, see MaxParams) are split
+// into several statements run in one transaction.
+func (r *Runner) Exec(ctx context.Context, qb Builder) (sql.Result, error) {
+	if b, ok := qb.(*QueryBuilder); ok && b.queryType == InsertQuery {
+		limit := b.target().maxParams()
+		if r.maxParamsSet {
+			limit = r.maxParams
+		}
+		if chunks := b.insertChunks(limit); chunks != nil {
+			return r.execChunks(ctx, chunks)
+		}
+	}
+	if b, ok := qb.(*QueryBuilder); ok && b.captureChanges && r.changeHook != nil {
+		return r.execCapture(ctx, b)
+	}
+	q, err := r.prepare(ctx, qb)
+	if err != nil {
+		return nil, err
+	}
+	db, release, err := r.deadlineDB(ctx, qb)
+	if err != nil {
+		return nil, err
+	}
+	defer r.track(ctx, qb, q)()
+	if release != nil {
+		defer release()
+	}
+	return db.ExecContext(ctx, q.SQL, q.Params...)
+}
+
+// prepare builds qb and runs the per-statement checks
+func (r *Runner) prepare(ctx context.Context, qb Builder) (Query, error) {
+	if b, ok := qb.(*QueryBuilder); ok && r.readOnly && b.queryType != SelectQuery {
+		return Query{}, fmt.Errorf("%w: %s statement", ErrReadOnly, queryTypeName(b.queryType))
+	}
+	if b, ok := qb.(*QueryBuilder); ok && b.ctx == nil && b.hasBuildHooks() {
+		b = b.Clone()
+		b.ctx = ctx
+		qb = b
+	}
+	if len(r.columnRewrites) > 0 {
+		rewritten, err := r.rewritten(qb)
+		if err != nil {
+			return Query{}, err
+		}
+		qb = rewritten
+	}
+	if r.maxRows > 0 {
+		qb = r.capRows(qb)
+	}
+	if err := r.checkComplexity(qb); err != nil {
+		return Query{}, err
+	}
+	q, err := qb.TryBuild()
+	if err != nil {
+		return Query{}, err
+	}
+	if r.readOnly && !readOnlySQL(q.SQL) {
+		return Query{}, fmt.Errorf("%w: %s", ErrReadOnly, q.SQL)
+	}
+	if err := r.checkAllowed(q); err != nil {
+		return Query{}, err
+	}
+	r.trackNPlusOne(ctx, q)
+	if b, ok := qb.(*QueryBuilder); ok {
+		r.reportSeqScans(ctx, b, q)
+		if r.deadlineHints {
+			if err := r.applyDeadline(ctx, r.route(b), b, &q); err != nil {
+				return Query{}, err
+			}
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,47 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestRunnerQueryAndExec(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select name from users where id = $1", []string{"name"}, []driver.Value{"John"})
+
+	runner := NewRunner(db)
+	ctx := context.Background()
+
+	row, err := runner.QueryRow(ctx, NewQueryBuilder().Table("users").Select("name").Where("id", "=", 1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var name string
+	if err := row.Scan(&name); err != nil || name != "John" {
+		t.Errorf("Expected John, got: %q (%v)", name, err)
+	}
+
+	if _, err := runner.Exec(ctx, NewQueryBuilder().Table("users").Delete().Where("id", "=", 1)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	queries := fake.Calls()
+	if len(queries) != 2 || queries[1].SQL != "delete from users where id = $1" {
+		t.Errorf("Expected select then delete, got: %v", queries)
+	}
+}
+
+func TestRunnerReturnsBuildErrors(t *testing.T) {
+	db, fake := newFakeDB(t)
+	runner := NewRunner(db)
+
+	_, err := runner.Exec(context.Background(), NewQueryBuilder().StrictIdentifiers().Table("users;").Delete())
+	if !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+	if len(fake.Calls()) != 0 {
+		t.Errorf("Expected nothing to run, got: %v", fake.Calls())
+	}
+}
@@ -0,0 +1,105 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsafeSQL is returned by SafetyCheck when a statement shows signs of
+// injection or does not match its parameters
+var ErrUnsafeSQL = errors.New("unsafe SQL")
+
+// SafetyCheck inspects one statement built by this package, or by code
+// extending it, for the marks a value spliced into the SQL text leaves
+// behind:
+//
+//   - an unterminated string literal, quoted identifier or /* comment
+//   - a ";" or "--" outside literals and comments, which could start a
+//     second statement or cut off the rest of this one
+//   - a NUL byte
+//   - placeholders that do not match params: one ? per param, or $1 to $n
+//     for n params, each used at least once; when $n placeholders are
+//     present a ? is taken for the Postgres operator
+//   - params no database/sql driver can bind
+//
+// String literals use standard SQL quoting, a doubled quote for a quote.
+// SafetyCheck is a testing aid for fuzzing extensions (see package
+// querysec); a passing statement is well-formed, not necessarily what the
+// caller intended.
+func SafetyCheck(sql string, params []interface{}) error {
+	if strings.IndexByte(sql, 0) >= 0 {
+		return fmt.Errorf("%w: NUL byte", ErrUnsafeSQL)
+	}
+
+	questions := 0
+	dollars := map[int]bool{}
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			end := closingQuote(sql, i)
+			if end < 0 {
+				return fmt.Errorf("%w: unterminated %c at offset %d", ErrUnsafeSQL, c, i)
+			}
+			i = end
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return fmt.Errorf("%w: unterminated comment at offset %d", ErrUnsafeSQL, i)
+			}
+			i += end + 3
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			return fmt.Errorf("%w: line comment at offset %d", ErrUnsafeSQL, i)
+		case c == ';':
+			return fmt.Errorf("%w: statement separator at offset %d", ErrUnsafeSQL, i)
+		case c == '?':
+			questions++
+		case c == '$' && i+1 < len(sql) && sql[i+1] >= '0' && sql[i+1] <= '9':
+			j := i + 1
+			for j < len(sql) && sql[j] >= '0' && sql[j] <= '9' {
+				j++
+			}
+			n, err := strconv.Atoi(sql[i+1 : j])
+			if err != nil || n == 0 {
+				return fmt.Errorf("%w: invalid placeholder %s", ErrUnsafeSQL, sql[i:j])
+			}
+			dollars[n] = true
+			i = j - 1
+		}
+	}
+
+	if len(dollars) > 0 {
+		for n := range dollars {
+			if n > len(params) {
+				return fmt.Errorf("%w: placeholder $%d with %d params", ErrUnsafeSQL, n, len(params))
+			}
+		}
+		if len(dollars) != len(params) {
+			return fmt.Errorf("%w: %d of %d params used", ErrUnsafeSQL, len(dollars), len(params))
+		}
+	} else if questions != len(params) {
+		return fmt.Errorf("%w: %d placeholders for %d params", ErrUnsafeSQL, questions, len(params))
+	}
+	if err := validateParams(params); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsafeSQL, err)
+	}
+	return nil
+}
+
+// closingQuote returns the offset of the quote closing the one at start,
+// skipping doubled quotes, or -1
+func closingQuote(sql string, start int) int {
+	quote := sql[start]
+	for i := start + 1; i < len(sql); i++ {
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSafetyCheck(t *testing.T) {
+	safe := []*QueryBuilder{
+		NewQueryBuilder().Table("users").Where("name", "=", "x'; drop table users --").Comment("audit"),
+		NewQueryBuilder().Dialect(MySQL).Table("users").Set("bio", "a -- b").Where("id", "=", 1),
+		NewQueryBuilder().Table("docs").WhereRaw("data ?? 'key' and id = ?", 2),
+		NewQueryBuilder().Table("users").Where("name", "=", "a").WhereRaw(`"odd;name" = 'it''s -- fine'`),
+	}
+	for _, qb := range safe {
+		q := qb.Build()
+		if err := SafetyCheck(q.SQL, q.Params); err != nil {
+			t.Errorf("Expected %s to pass, got: %v", q.SQL, err)
+		}
+	}
+
+	unsafe := []struct {
+		sql    string
+		params []interface{}
+	}{
+		{"select * from users where name = 'x' or '1'='1", nil},
+		{"select * from users where name = 'x''", nil},
+		{"select * from users where id = 1; drop table users", nil},
+		{"select * from users where id = 1 -- and deleted = false", nil},
+		{"select * from users /* where id = $1", []interface{}{1}},
+		{"select * from users where id = $1 and name = $3", []interface{}{1, "a"}},
+		{"select * from users where id = ?", []interface{}{1, 2}},
+		{"select * from users where id = ?", []interface{}{struct{}{}}},
+		{"select * from users where name = 'a\x00'", nil},
+	}
+	for _, test := range unsafe {
+		if err := SafetyCheck(test.sql, test.params); !errors.Is(err, ErrUnsafeSQL) {
+			t.Errorf("Expected ErrUnsafeSQL for %q, got: %v", test.sql, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scape-labs/query"
+)
+
+// SequenceBuilder builds CREATE SEQUENCE statements
+type SequenceBuilder struct {
+	name         string
+	start        int64
+	increment    int64
+	hasStart     bool
+	hasIncrement bool
+}
+
+// CreateSequence starts a CREATE SEQUENCE statement. The name is checked
+// and quoted like a NextVal sequence, so TryBuild fails with
+// query.ErrUnsafeIdentifier for anything but a plain, optionally
+// schema-qualified name.
+func CreateSequence(name string) *SequenceBuilder {
+	return &SequenceBuilder{name: name}
+}
+
+func (s *SequenceBuilder) StartWith(start int64) *SequenceBuilder {
+	s.start = start
+	s.hasStart = true
+	return s
+}
+
+func (s *SequenceBuilder) IncrementBy(increment int64) *SequenceBuilder {
+	s.increment = increment
+	s.hasIncrement = true
+	return s
+}
+
+// Build generates the SQL, or an empty Query if the name is unsafe (see
+// TryBuild)
+func (s *SequenceBuilder) Build() query.Query {
+	q, err := s.TryBuild()
+	if err != nil {
+		return query.Query{}
+	}
+	return q
+}
+
+// TryBuild generates the SQL, or query.ErrUnsafeIdentifier for a name that
+// cannot be quoted
+func (s *SequenceBuilder) TryBuild() (query.Query, error) {
+	name, err := query.Postgres.QuoteIdentifier(s.name)
+	if err != nil {
+		return query.Query{}, err
+	}
+
+	var sql strings.Builder
+
+	sql.WriteString("create sequence ")
+	sql.WriteString(name)
+
+	if s.hasStart {
+		sql.WriteString(fmt.Sprintf(" start with %d", s.start))
+	}
+
+	if s.hasIncrement {
+		sql.WriteString(fmt.Sprintf(" increment by %d", s.increment))
+	}
+
+	return query.Query{
+		SQL: sql.String(),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scape-labs/query"
+)
+
+func TestCreateSequence(t *testing.T) {
+	query := CreateSequence("order_seq").
+		StartWith(1000).
+		IncrementBy(1).
+		Build()
+
+	expectedSQL := `create sequence "order_seq" start with 1000 increment by 1`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 0 {
+		t.Errorf("Expected no params, got: %v", query.Params)
+	}
+}
+
+func TestCreateSequenceDefaults(t *testing.T) {
+	query := CreateSequence("order_seq").Build()
+
+	expectedSQL := `create sequence "order_seq"`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestCreateSequenceName(t *testing.T) {
+	q, err := CreateSequence("billing.order_seq").TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := `create sequence "billing"."order_seq"`
+	if q.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, q.SQL)
+	}
+
+	for _, name := range []string{"", "order_seq; drop table orders", `order"seq`, "*"} {
+		if _, err := CreateSequence(name).TryBuild(); !errors.Is(err, query.ErrUnsafeIdentifier) {
+			t.Errorf("Expected ErrUnsafeIdentifier for %q, got: %v", name, err)
+		}
+	}
+	if q := CreateSequence("order_seq; drop table orders").Build(); q.SQL != "" {
+		t.Errorf("Expected an empty Query for an unsafe name, got: %s", q.SQL)
+	}
+}
@@ -0,0 +1,222 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrUnknownColumn is returned by TryBuild, once column checks are enabled,
+// when a builder names a column its table does not have
+var ErrUnknownColumn = errors.New("unknown column")
+
+// checkColumns counts the callers that turned column checks on
+var checkColumns atomic.Int32
+
+// CheckColumns turns on column checks: until disable is called, TryBuild
+// rejects, with ErrUnknownColumn, any column missing from a loaded or
+// registered table. Checks stay on while any caller has them on.
+func CheckColumns() (disable func()) {
+	checkColumns.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { checkColumns.Add(-1) })
+	}
+}
+
+type schemaOptions struct {
+	dialect  Dialect
+	validate bool
+}
+
+// SchemaOption configures LoadSchema
+type SchemaOption func(*schemaOptions)
+
+// SchemaDialect reads the schema of a MySQL database instead of Postgres
+func SchemaDialect(dialect Dialect) SchemaOption {
+	return func(o *schemaOptions) {
+		o.dialect = dialect
+	}
+}
+
+// ValidateColumns turns on column checks for good once the schema is
+// loaded, see CheckColumns. Intended for development; tests should call
+// CheckColumns and disable the checks when done.
+func ValidateColumns() SchemaOption {
+	return func(o *schemaOptions) {
+		o.validate = true
+	}
+}
+
+// LoadSchema reads every table and column of the current schema from
+// information_schema and registers them with RegisterColumns, replacing
+// earlier registrations of the same tables
+func LoadSchema(ctx context.Context, db Querier, opts ...SchemaOption) error {
+	o := schemaOptions{dialect: Postgres}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	schema := "current_schema()"
+	if o.dialect == MySQL {
+		schema = "database()"
+	}
+	rows, err := db.QueryContext(ctx, "select table_name, column_name from information_schema.columns where table_schema = "+schema+" order by table_name, ordinal_position")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tables := map[string][]string{}
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return err
+		}
+		tables[table] = append(tables[table], column)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for table, columns := range tables {
+		RegisterColumns(table, columns...)
+	}
+	if o.validate {
+		CheckColumns()
+	}
+	return nil
+}
+
+// validateColumns checks the builder's plain column names against the
+// registered columns of its table and joined tables. Qualified names are
+// checked against the table or alias they name; unregistered tables and
+// expressions are not checked, nor are unqualified names when a joined
+// table is unregistered, as they may be its columns.
+func (b *QueryBuilder) validateColumns() error {
+	if checkColumns.Load() == 0 || b.fromExpr != nil || b.fromSub != nil {
+		return nil
+	}
+	for _, join := range b.joinClauses {
+		if join.Source != nil {
+			return nil
+		}
+	}
+
+	// Table or alias name -> registered columns
+	scopes := map[string][]string{}
+	unregistered := false
+	addScope := func(table, alias string) {
+		if fields := strings.Fields(table); len(fields) == 2 {
+			table, alias = fields[0], fields[1]
+		}
+		columns, ok := LookupColumns(table)
+		if !ok {
+			unregistered = true
+			return
+		}
+		scopes[table] = columns
+		if alias != "" {
+			scopes[alias] = columns
+		}
+	}
+	addScope(b.table, b.tableAlias)
+	for _, join := range b.joinClauses {
+		addScope(join.Table, join.Alias)
+	}
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	var names []string
+	if b.queryType == SelectQuery {
+		for _, column := range b.columns {
+			names = append(names, columnName(column))
+		}
+	}
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		names = append(names, where.Column)
+	})
+	names = append(names, b.groupBy...)
+	names = append(names, b.insertColumns...)
+	names = append(names, b.updateColumns...)
+
+	for _, name := range names {
+		if !isIdentifier(name) || strings.HasSuffix(name, "*") {
+			continue
+		}
+		if unregistered && !strings.Contains(name, ".") {
+			continue
+		}
+		if err := checkColumn(scopes, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkColumn(scopes map[string][]string, name string) error {
+	column := name
+	candidates := scopes
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		columns, ok := scopes[name[:i]]
+		if !ok {
+			return nil
+		}
+		column = name[i+1:]
+		candidates = map[string][]string{name[:i]: columns}
+	}
+
+	var known []string
+	for _, columns := range candidates {
+		if slices.Contains(columns, column) {
+			return nil
+		}
+		known = append(known, columns...)
+	}
+	if suggestion := closest(column, known); suggestion != "" {
+		return fmt.Errorf("%w: %s (did you mean %s?)", ErrUnknownColumn, name, suggestion)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownColumn, name)
+}
+
+// closest returns the candidate within two edits of name, if any
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if d := editDistance(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Damerau-Levenshtein (optimal string alignment)
+// distance, so swapped letters count as one edit
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,115 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func loadTestSchema(t *testing.T) {
+	t.Helper()
+	db, fake := newFakeDB(t)
+	fake.On("select table_name, column_name from information_schema.columns where table_schema = current_schema() order by table_name, ordinal_position",
+		[]string{"table_name", "column_name"},
+		[]driver.Value{"schema_users", "id"},
+		[]driver.Value{"schema_users", "email"},
+		[]driver.Value{"schema_orders", "id"},
+		[]driver.Value{"schema_orders", "user_id"},
+	)
+
+	if err := LoadSchema(context.Background(), db); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(CheckColumns())
+}
+
+func TestLoadSchema(t *testing.T) {
+	loadTestSchema(t)
+
+	columns, ok := LookupColumns("schema_users")
+	if !ok || strings.Join(columns, ",") != "id,email" {
+		t.Errorf("Expected columns id,email, got: %v", columns)
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	loadTestSchema(t)
+
+	_, err := NewQueryBuilder().
+		Table("schema_users").
+		Where("emial", "=", "a@example.com").
+		TryBuild()
+	if !errors.Is(err, ErrUnknownColumn) {
+		t.Fatalf("Expected ErrUnknownColumn, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "did you mean email") {
+		t.Errorf("Expected a suggestion, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("schema_users").
+		As("u").
+		Select("u.id", "o.id as order_id").
+		Join("schema_orders o", "o.user_id = u.id").
+		Where("o.user_id", "=", 1).
+		Where("email", "=", "a@example.com").
+		TryBuild()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("schema_users").
+		Join("schema_orders o", "o.user_id = schema_users.id").
+		Where("o.email", "=", "a@example.com").
+		TryBuild()
+	if !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected ErrUnknownColumn for a column of the wrong table, got: %v", err)
+	}
+
+	if _, err := NewQueryBuilder().Table("not_loaded").Where("anything", "=", 1).TryBuild(); err != nil {
+		t.Errorf("Expected unregistered tables to be skipped, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("schema_users").
+		Join("not_loaded n", "n.user_id = schema_users.id").
+		Where("anything", "=", 1).
+		TryBuild()
+	if err != nil {
+		t.Errorf("Expected unqualified columns to be skipped with an unregistered join, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("schema_users").
+		Join("not_loaded n", "n.user_id = schema_users.id").
+		Where("schema_users.emial", "=", "a@example.com").
+		TryBuild()
+	if !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected qualified columns of registered tables to be checked, got: %v", err)
+	}
+}
+
+func TestCheckColumnsDisable(t *testing.T) {
+	RegisterColumns("checked_users", "id", "email")
+
+	build := func() error {
+		_, err := NewQueryBuilder().Table("checked_users").Where("emial", "=", "a@example.com").TryBuild()
+		return err
+	}
+
+	disable := CheckColumns()
+	inner := CheckColumns()
+	inner()
+	inner()
+	if err := build(); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected ErrUnknownColumn while checks are on, got: %v", err)
+	}
+
+	disable()
+	if err := build(); err != nil {
+		t.Errorf("Expected no error once checks are off, got: %v", err)
+	}
+}
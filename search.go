@@ -0,0 +1,77 @@
+package query
+
+import "strings"
+
+type searchOptions struct {
+	caseSensitive bool
+	allWords      bool
+}
+
+// SearchOption configures Search
+type SearchOption func(*searchOptions)
+
+// CaseSensitive matches with LIKE instead of ILIKE
+func CaseSensitive() SearchOption {
+	return func(o *searchOptions) {
+		o.caseSensitive = true
+	}
+}
+
+// MatchAllWords splits the term on whitespace and requires every word to
+// match at least one of the columns
+func MatchAllWords() SearchOption {
+	return func(o *searchOptions) {
+		o.allWords = true
+	}
+}
+
+// Search adds a parenthesized group matching term anywhere in any of
+// columns. LIKE wildcards in term are escaped. MySQL uses LIKE, which is
+// case-insensitive under its default collations; other dialects use ILIKE.
+func (b *QueryBuilder) Search(columns []string, term string, opts ...SearchOption) *QueryBuilder {
+	var o searchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	operator := "ilike"
+	if o.caseSensitive || b.target() == MySQL {
+		operator = "like"
+	}
+
+	words := []string{term}
+	if o.allWords {
+		words = strings.Fields(term)
+	}
+	if len(columns) == 0 || len(words) == 0 {
+		return b
+	}
+
+	matchWord := func(q *QueryBuilder, word string) {
+		pattern := "%" + EscapeLike(word) + "%"
+		for _, column := range columns {
+			q.OrWhere(column, operator, pattern)
+		}
+	}
+
+	if len(words) == 1 {
+		return b.WhereGroup(func(q *QueryBuilder) {
+			matchWord(q, words[0])
+		})
+	}
+	return b.WhereGroup(func(q *QueryBuilder) {
+		for _, word := range words {
+			q.WhereGroup(func(q *QueryBuilder) {
+				matchWord(q, word)
+			})
+		}
+	})
+}
+
+// EscapeLike escapes the LIKE wildcards % and _ and the escape character
+// itself, so term matches literally
+func EscapeLike(term string) string {
+	return likeEscaper.Replace(term)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,51 @@
+package query
+
+import "testing"
+
+func TestSearch(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		Where("active", "=", true).
+		Search([]string{"name", "email", "phone"}, "50%_off").
+		Build()
+
+	expectedSQL := "select * from users where active = $1 and (name ilike $2 or email ilike $3 or phone ilike $4)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 4 || query.Params[1] != `%50\%\_off%` || query.Params[3] != `%50\%\_off%` {
+		t.Errorf("Expected escaped patterns, got: %v", query.Params)
+	}
+}
+
+func TestSearchAllWords(t *testing.T) {
+	query := NewQueryBuilder().
+		ParameterPlaceholder(QuestionMark).
+		Table("users").
+		Search([]string{"name", "email"}, "  jane   doe ", MatchAllWords()).
+		Build()
+
+	expectedSQL := "select * from users where ((name like ? or email like ?) and (name like ? or email like ?))"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	expectedParams := []interface{}{"%jane%", "%jane%", "%doe%", "%doe%"}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+}
+
+func TestSearchEmptyTerm(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		Search([]string{"name"}, "   ", MatchAllWords(), CaseSensitive()).
+		Build()
+
+	if query.SQL != "select * from users" {
+		t.Errorf("Expected no search condition, got: %s", query.SQL)
+	}
+}
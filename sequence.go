@@ -0,0 +1,54 @@
+package query
+
+import "strings"
+
+// NextVal returns the expression fetching the next value of a sequence:
+// nextval('seq') on Postgres and DuckDB. The name must be a plain,
+// optionally schema-qualified identifier, or TryBuild fails with
+// ErrUnsafeIdentifier; on databases without sequences it fails with
+// ErrUnsupportedFeature.
+func NextVal(sequence string) Expr {
+	return Expr{SQL: "nextval(" + quoteString(sequence) + ")", nextVal: true, sequence: sequence}
+}
+
+// nextVal writes the nextval call for sequence
+func (w *sqlWriter) nextVal(sequence string) {
+	w.write("nextval(")
+	w.raw(quoteString(sequence))
+	w.write(")")
+}
+
+// validateSequences checks the name of every NextVal the statement binds
+// and that the database has sequences
+func (b *QueryBuilder) validateSequences() error {
+	var sequences []string
+	collect := func(value interface{}) {
+		if expr, ok := value.(Expr); ok && expr.nextVal {
+			sequences = append(sequences, expr.sequence)
+		}
+	}
+	for _, value := range b.insertValues {
+		collect(value)
+	}
+	for _, row := range b.insertRows {
+		for _, value := range row {
+			collect(value)
+		}
+	}
+	for _, value := range b.updateValues {
+		collect(value)
+	}
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		collect(where.Value)
+	})
+
+	for _, sequence := range sequences {
+		if !isIdentifier(sequence) || strings.HasSuffix(sequence, "*") {
+			return unsafeIdentifier(sequence)
+		}
+	}
+	if len(sequences) > 0 {
+		return b.require(Sequences)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNextValDialects(t *testing.T) {
+	query, err := NewQueryBuilder().Dialect(DuckDB).
+		Table("orders").
+		InsertColumns("id", "total").
+		Values(NextVal("sales.order_seq"), 10).
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := "insert into orders (id, total) values (nextval('sales.order_seq'), ?)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	for _, dialect := range []Dialect{MySQL, CQL} {
+		_, err := NewQueryBuilder().Dialect(dialect).Table("orders").
+			InsertColumns("id").Values(NextVal("order_seq")).TryBuild()
+		if !errors.Is(err, ErrUnsupportedFeature) {
+			t.Errorf("Expected ErrUnsupportedFeature on %s, got: %v", dialect, err)
+		}
+	}
+}
+
+func TestNextValRejectsUnsafeNames(t *testing.T) {
+	for _, name := range []string{"x'); drop table t; --", "seq?", "", "s.*"} {
+		_, err := NewQueryBuilder().Table("orders").
+			InsertColumns("id", "total").Values(NextVal(name), 1).TryBuild()
+		if !errors.Is(err, ErrUnsafeIdentifier) {
+			t.Errorf("Expected ErrUnsafeIdentifier for %q, got: %v", name, err)
+		}
+	}
+
+	_, err := NewQueryBuilder().Table("orders").Set("id", NextVal("a b")).Where("id", "=", 1).TryBuild()
+	if !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+}
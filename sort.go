@@ -0,0 +1,51 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownSortField is returned by TryBuild when OrderByAllowed receives
+// a field that is not in its allow-list
+var ErrUnknownSortField = errors.New("unknown sort field")
+
+// OrderByAllowed sets ORDER BY from untrusted input such as "-created_at,name".
+// Each comma-separated field is looked up in allowed, which maps the public
+// name to the real column; a leading "-" sorts descending and an optional
+// "+" ascending. Blank input leaves the current ordering untouched. Fields
+// missing from allowed are reported by TryBuild as ErrUnknownSortField, so
+// user input never reaches the SQL text.
+func (b *QueryBuilder) OrderByAllowed(userInput string, allowed map[string]string) *QueryBuilder {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(userInput, ",") {
+		field = strings.TrimSpace(field)
+		direction := "asc"
+		switch {
+		case strings.HasPrefix(field, "-"):
+			direction = "desc"
+			field = field[1:]
+		case strings.HasPrefix(field, "+"):
+			field = field[1:]
+		}
+		if field == "" {
+			continue
+		}
+
+		column, ok := allowed[field]
+		if !ok {
+			return b.fail(fmt.Errorf("%w: %q", ErrUnknownSortField, field))
+		}
+		if seen[column] {
+			continue
+		}
+		seen[column] = true
+		terms = append(terms, column+" "+direction)
+	}
+
+	if len(terms) == 0 {
+		return b
+	}
+	return b.OrderBy(strings.Join(terms, ", "))
+}
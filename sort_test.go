@@ -0,0 +1,46 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+var sortable = map[string]string{
+	"created_at": "u.created_at",
+	"name":       "u.name",
+}
+
+func TestOrderByAllowed(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users u").
+		OrderByAllowed(" -created_at, +name,name ", sortable).
+		Build()
+
+	expectedSQL := "select * from users u order by u.created_at desc, u.name asc"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestOrderByAllowedBlankKeepsDefault(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		OrderBy("id").
+		OrderByAllowed("", sortable).
+		Build()
+
+	expectedSQL := "select * from users order by id"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestOrderByAllowedRejectsUnknown(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("users").
+		OrderByAllowed("name,password; drop table users", sortable).
+		TryBuild()
+	if !errors.Is(err, ErrUnknownSortField) {
+		t.Errorf("Expected ErrUnknownSortField, got: %v", err)
+	}
+}
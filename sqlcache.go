@@ -0,0 +1,92 @@
+package query
+
+import (
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	sqlCacheOn   atomic.Bool
+	sqlCacheMu   sync.RWMutex
+	sqlCache     map[uint64]string
+	sqlCacheSize int
+	sqlCacheSeed = maphash.MakeSeed()
+
+	sqlCacheHits   atomic.Uint64
+	sqlCacheMisses atomic.Uint64
+)
+
+// SQLCacheStats reports how the SQL cache has been used since CacheSQL
+type SQLCacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+// CacheSQL turns on a global cache of up to size statements. Builders
+// producing the same structure, even from different instances, then share
+// one SQL string instead of allocating a new one per build: the statement
+// is rendered into the builder's reused buffer and looked up by its hash,
+// so only the params of the Query are new. When the cache is full it is
+// emptied and starts over. A size of 0 or less turns it off.
+//
+// Statements embedding values, such as BuildInterpolated output, are never
+// cached; keep per-request text out of Comment to make the cache useful.
+func CacheSQL(size int) {
+	sqlCacheMu.Lock()
+	defer sqlCacheMu.Unlock()
+	sqlCache = nil
+	sqlCacheSize = size
+	if size > 0 {
+		sqlCache = make(map[uint64]string, size)
+	}
+	sqlCacheHits.Store(0)
+	sqlCacheMisses.Store(0)
+	sqlCacheOn.Store(size > 0)
+}
+
+// GetSQLCacheStats returns the hits, misses and size of the SQL cache
+func GetSQLCacheStats() SQLCacheStats {
+	sqlCacheMu.RLock()
+	defer sqlCacheMu.RUnlock()
+	return SQLCacheStats{
+		Hits:    sqlCacheHits.Load(),
+		Misses:  sqlCacheMisses.Load(),
+		Entries: len(sqlCache),
+	}
+}
+
+// cachedSQL returns the statement in buf as a string, shared with earlier
+// builds of the same statement while the cache is on
+func cachedSQL(buf []byte) string {
+	if !sqlCacheOn.Load() {
+		return string(buf)
+	}
+	key := maphash.Bytes(sqlCacheSeed, buf)
+
+	sqlCacheMu.RLock()
+	sql, ok := sqlCache[key]
+	sqlCacheMu.RUnlock()
+	if ok && sql == string(buf) {
+		sqlCacheHits.Add(1)
+		return sql
+	}
+
+	sqlCacheMisses.Add(1)
+	sql = string(buf)
+	if ok {
+		// a hash collision keeps the entry already cached
+		return sql
+	}
+	sqlCacheMu.Lock()
+	defer sqlCacheMu.Unlock()
+	if sqlCache == nil {
+		return sql
+	}
+	if len(sqlCache) >= sqlCacheSize {
+		clear(sqlCache)
+	}
+	sqlCache[key] = sql
+	return sql
+}
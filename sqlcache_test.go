@@ -0,0 +1,47 @@
+package query
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestCacheSQL(t *testing.T) {
+	CacheSQL(2)
+	defer CacheSQL(0)
+
+	build := func(id int) Query {
+		return NewQueryBuilder().Table("users").Where("id", "=", id).Build()
+	}
+	first, second := build(1), build(2)
+	if unsafe.StringData(first.SQL) != unsafe.StringData(second.SQL) {
+		t.Error("Expected builders of the same structure to share the SQL string")
+	}
+	if second.Params[0] != 2 {
+		t.Errorf("Expected param 2, got: %v", second.Params[0])
+	}
+
+	interpolated, err := NewQueryBuilder().Table("users").Where("id", "=", 1).BuildInterpolated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if interpolated != "select * from users where id = 1" {
+		t.Errorf("Unexpected SQL: %s", interpolated)
+	}
+
+	stats := GetSQLCacheStats()
+	// BuildInterpolated renders twice, once with placeholders
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("Expected 2 hits, 1 miss and 1 entry, got: %+v", stats)
+	}
+
+	NewQueryBuilder().Table("orders").Build()
+	NewQueryBuilder().Table("teams").Build()
+	if stats := GetSQLCacheStats(); stats.Entries != 1 {
+		t.Errorf("Expected the full cache to start over, got: %+v", stats)
+	}
+
+	CacheSQL(0)
+	if first, second := build(1), build(2); unsafe.StringData(first.SQL) == unsafe.StringData(second.SQL) {
+		t.Error("Expected no sharing with the cache off")
+	}
+}
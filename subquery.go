@@ -0,0 +1,110 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownAlias is returned by TryBuild when a correlated subquery, or a
+// qualified column checked by ValidateAliases, refers to a table or alias
+// the query does not declare
+var ErrUnknownAlias = errors.New("unknown alias")
+
+// WhereExists adds "exists (sub)", joined with AND
+func (b *QueryBuilder) WhereExists(sub *QueryBuilder) *QueryBuilder {
+	return b.whereSubquery("", "exists", sub)
+}
+
+// WhereNotExists adds "not exists (sub)", joined with AND
+func (b *QueryBuilder) WhereNotExists(sub *QueryBuilder) *QueryBuilder {
+	return b.whereSubquery("", "not exists", sub)
+}
+
+// WhereInQuery adds "column in (sub)", joined with AND. sub should select
+// a single column.
+func (b *QueryBuilder) WhereInQuery(column string, sub *QueryBuilder) *QueryBuilder {
+	return b.whereSubquery(column, "in", sub)
+}
+
+func (b *QueryBuilder) whereSubquery(column, operator string, sub *QueryBuilder) *QueryBuilder {
+	b.whereClauses = append(b.whereClauses, &WhereClause{
+		Column:   column,
+		Operator: operator,
+		Subquery: sub,
+		JoinType: "and",
+	})
+	return b
+}
+
+// CorrelateOn ties a subquery to its outer query with "inner = outer",
+// as in sub.CorrelateOn("posts.user_id", "users.id"). outer must be
+// qualified by a table or alias of an enclosing query; TryBuild reports
+// ErrUnknownAlias when none has it, or when the subquery is built on its
+// own.
+func (b *QueryBuilder) CorrelateOn(inner, outer string) *QueryBuilder {
+	if !isIdentifier(inner) {
+		return b.fail(unsafeIdentifier(inner))
+	}
+	if !isIdentifier(outer) {
+		return b.fail(unsafeIdentifier(outer))
+	}
+	if !strings.Contains(outer, ".") {
+		return b.fail(fmt.Errorf("%w: %q is not qualified by a table or alias", ErrUnknownAlias, outer))
+	}
+	b.outerRefs = append(b.outerRefs, outer)
+	b.whereClauses = append(b.whereClauses, &WhereClause{
+		Column:   inner,
+		Operator: "=",
+		Value:    outerColumn(outer),
+		JoinType: "and",
+	})
+	return b
+}
+
+// outerColumn is a column of an enclosing query, written as an identifier
+// instead of bound
+type outerColumn string
+
+// validateScope checks the subqueries of b, resolving their outer
+// references against b and the queries enclosing it
+func (b *QueryBuilder) validateScope(scopes []*QueryBuilder) error {
+	for _, ref := range b.outerRefs {
+		qualifier := ref[:strings.LastIndex(ref, ".")]
+		if !slices.ContainsFunc(scopes, func(scope *QueryBuilder) bool { return scope.hasRelation(qualifier) }) {
+			return fmt.Errorf("%w: %s", ErrUnknownAlias, ref)
+		}
+	}
+
+	var err error
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		if where.Subquery == nil || err != nil {
+			return
+		}
+		sub := where.Subquery
+		if sub.queryType != SelectQuery {
+			err = fmt.Errorf("%w: subquery is a %s statement", ErrNotSelect, queryTypeName(sub.queryType))
+			return
+		}
+		if err = sub.check(); err == nil {
+			err = sub.validateScope(append(slices.Clone(scopes), b))
+		}
+	})
+	for _, sel := range b.selectSubs {
+		if err != nil {
+			break
+		}
+		if err = sel.sub.check(); err == nil {
+			err = sel.sub.validateScope(append(slices.Clone(scopes), b))
+		}
+	}
+	if err != nil || b.fromSub == nil {
+		return err
+	}
+	// A derived table cannot see the query it is the FROM of
+	if err := b.fromSub.check(); err != nil {
+		return err
+	}
+	return b.fromSub.validateScope(scopes)
+}
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCorrelateOn(t *testing.T) {
+	posts := NewQueryBuilder().
+		Table("posts").
+		Select("1").
+		CorrelateOn("posts.user_id", "users.id").
+		Where("posts.published", "=", true)
+
+	query := NewQueryBuilder().
+		Table("users").
+		Where("users.active", "=", true).
+		WhereExists(posts).
+		Build()
+
+	expectedSQL := "select * from users where users.active = $1 and exists (select 1 from posts where posts.user_id = users.id and posts.published = $2)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[1] != true {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestCorrelateOnAlias(t *testing.T) {
+	comments := NewQueryBuilder().
+		Table("comments").
+		Select("comments.post_id").
+		CorrelateOn("comments.author_id", "u.id")
+
+	query := NewQueryBuilder().
+		Table("users").
+		As("u").
+		QuoteStyle(DoubleQuote).
+		WhereNotExists(NewQueryBuilder().Table("bans").Select("1").CorrelateOn("bans.user_id", "u.id")).
+		WhereInQuery("u.last_post_id", comments).
+		Build()
+
+	expectedSQL := `select * from "users" as "u" where not exists (select 1 from "bans" where "bans"."user_id" = "u"."id") and "u"."last_post_id" in (select "comments"."post_id" from "comments" where "comments"."author_id" = "u"."id")`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestCorrelateOnNested(t *testing.T) {
+	likes := NewQueryBuilder().Table("likes").Select("1").
+		CorrelateOn("likes.post_id", "posts.id").
+		CorrelateOn("likes.user_id", "users.id")
+	posts := NewQueryBuilder().Table("posts").Select("1").
+		CorrelateOn("posts.user_id", "users.id").
+		WhereExists(likes)
+
+	if _, err := NewQueryBuilder().Table("users").WhereExists(posts).TryBuild(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCorrelateOnUnknownAlias(t *testing.T) {
+	sub := NewQueryBuilder().Table("posts").Select("1").CorrelateOn("posts.user_id", "accounts.id")
+
+	_, err := NewQueryBuilder().Table("users").WhereExists(sub).TryBuild()
+	if !errors.Is(err, ErrUnknownAlias) {
+		t.Errorf("Expected ErrUnknownAlias, got: %v", err)
+	}
+
+	if _, err := sub.TryBuild(); !errors.Is(err, ErrUnknownAlias) {
+		t.Errorf("Expected ErrUnknownAlias for a standalone subquery, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().Table("posts").CorrelateOn("posts.user_id", "id").TryBuild()
+	if !errors.Is(err, ErrUnknownAlias) {
+		t.Errorf("Expected ErrUnknownAlias for an unqualified column, got: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package query
+
+import (
+	"slices"
+	"sync"
+)
+
+type tableConfig struct {
+	defaultOrder string
+	baseFilters  []func(q *QueryBuilder)
+}
+
+// TableOption configures the defaults of a table, see ConfigureTable
+type TableOption func(*tableConfig)
+
+var (
+	tableConfigsMu sync.RWMutex
+	tableConfigs   = map[string]*tableConfig{}
+)
+
+// ConfigureTable sets defaults every builder gets from Table(table).
+// Configuring a table again replaces its defaults; no options removes
+// them. Builders already created are not affected.
+func ConfigureTable(table string, opts ...TableOption) {
+	tableConfigsMu.Lock()
+	defer tableConfigsMu.Unlock()
+	if len(opts) == 0 {
+		delete(tableConfigs, table)
+		return
+	}
+	config := &tableConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	tableConfigs[table] = config
+}
+
+// WithDefaultOrder orders selects that set no ordering of their own.
+// OrderBy, OrderByRaw and OrderByRandom override it.
+func WithDefaultOrder(order string) TableOption {
+	return func(c *tableConfig) {
+		c.defaultOrder = order
+	}
+}
+
+// WithBaseFilter adds the conditions fn adds, as one group, ahead of a
+// builder's own where clauses - for soft deletes or tenant scoping. The
+// builder's own clauses are grouped behind them (base and (own...)), so an
+// OrWhere cannot widen the scope. They apply to selects, updates and
+// deletes; Unscoped removes them.
+func WithBaseFilter(fn func(q *QueryBuilder)) TableOption {
+	return func(c *tableConfig) {
+		c.baseFilters = append(c.baseFilters, fn)
+	}
+}
+
+// Unscoped removes the base filters ConfigureTable added to the builder
+func (b *QueryBuilder) Unscoped() *QueryBuilder {
+	b.whereClauses = slices.Delete(b.whereClauses, 0, b.baseFilters)
+	b.baseFilters = 0
+	return b
+}
+
+// applyTableConfig replaces the builder's table defaults with those of
+// its current table, followed by its Factory scopes
+func (b *QueryBuilder) applyTableConfig() {
+	b.Unscoped()
+	b.defaultOrder = ""
+
+	tableConfigsMu.RLock()
+	config, ok := tableConfigs[b.table]
+	tableConfigsMu.RUnlock()
+	if ok {
+		b.defaultOrder = config.defaultOrder
+		b.addBaseFilters(config.baseFilters)
+	}
+	b.addBaseFilters(b.scopes)
+}
+
+// addBaseFilters puts the conditions each fn adds, as one group per fn,
+// ahead of the builder's where clauses, counted as base filters
+func (b *QueryBuilder) addBaseFilters(fns []func(q *QueryBuilder)) {
+	var base []*WhereClause
+	for _, fn := range fns {
+		group := NewQueryBuilder()
+		fn(group)
+		if group.err != nil {
+			b.fail(group.err)
+		}
+		if len(group.whereClauses) > 0 {
+			base = append(base, documentGroup(group.whereClauses, "and"))
+		}
+	}
+	b.whereClauses = append(base, b.whereClauses...)
+	b.baseFilters += len(base)
+}
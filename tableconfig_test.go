@@ -0,0 +1,79 @@
+package query
+
+import "testing"
+
+func configureEvents(t *testing.T) {
+	t.Helper()
+	ConfigureTable("events",
+		WithDefaultOrder("created_at desc"),
+		WithBaseFilter(func(q *QueryBuilder) {
+			q.WhereRaw("deleted_at is null")
+		}),
+	)
+	t.Cleanup(func() { ConfigureTable("events") })
+}
+
+func TestConfigureTable(t *testing.T) {
+	configureEvents(t)
+
+	query := NewQueryBuilder().Table("events").Where("kind", "=", "click").OrWhere("kind", "=", "view").Build()
+
+	expectedSQL := "select * from events where deleted_at is null and (kind = $1 or kind = $2) order by created_at desc"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestConfigureTableOverrides(t *testing.T) {
+	configureEvents(t)
+
+	query := NewQueryBuilder().Table("events").OrderBy("id").Unscoped().Build()
+	expectedSQL := "select * from events order by id"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().Table("events").Set("kind", "x").Where("id", "=", 1).Build()
+	expectedSQL = "update events set kind = $1 where deleted_at is null and id = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestConfigureTableOrWhere(t *testing.T) {
+	ConfigureTable("posts", WithBaseFilter(func(q *QueryBuilder) {
+		q.Where("deleted_at", "is", nil)
+	}))
+	t.Cleanup(func() { ConfigureTable("posts") })
+
+	query := NewQueryBuilder().Table("posts").Delete().Where("a", "=", 1).OrWhere("b", "=", 2).Build()
+	expectedSQL := "delete from posts where deleted_at is $1 and (a = $2 or b = $3)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().Table("posts").OrWhere("b", "=", 2).Build()
+	expectedSQL = "select * from posts where deleted_at is $1 and b = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestConfigureTableGroupsBaseFilters(t *testing.T) {
+	ConfigureTable("docs", WithBaseFilter(func(q *QueryBuilder) {
+		q.Where("tenant_id", "=", 7).OrWhere("public", "=", true)
+	}))
+	t.Cleanup(func() { ConfigureTable("docs") })
+
+	query := NewQueryBuilder().Where("id", "=", 1).Table("docs").Build()
+
+	expectedSQL := "select * from docs where (tenant_id = $1 or public = $2) and id = $3"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().Table("docs").Table("pages").Build()
+	if query.SQL != "select * from pages" {
+		t.Errorf("Expected defaults to follow the table, got: %s", query.SQL)
+	}
+}
@@ -0,0 +1,69 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Slot marks a parameter whose value is supplied later through
+// Template.Bind
+type Slot string
+
+// Template is a built query whose SQL is fixed and whose Slot parameters
+// are filled in on each Bind, skipping the full build on hot paths
+type Template struct {
+	sql      string
+	params   []interface{}
+	slots    map[string][]int
+	settings paramSettings
+}
+
+// Template builds the query once, recording the position of every Slot
+// parameter
+func (b *QueryBuilder) Template() (*Template, error) {
+	q, err := b.TryBuild()
+	if err != nil {
+		return nil, err
+	}
+
+	tpl := &Template{
+		sql:      q.SQL,
+		params:   q.Params,
+		slots:    map[string][]int{},
+		settings: b.paramSettings(),
+	}
+	for i, param := range q.Params {
+		if slot, ok := param.(Slot); ok {
+			tpl.slots[string(slot)] = append(tpl.slots[string(slot)], i)
+		}
+	}
+	return tpl, nil
+}
+
+// SQL returns the frozen SQL text
+func (t *Template) SQL() string {
+	return t.sql
+}
+
+// Bind fills every slot from values and returns the resulting Query. Each
+// slot must be given a value; values for unknown slots are an error.
+func (t *Template) Bind(values map[string]interface{}) (Query, error) {
+	for name := range values {
+		if _, ok := t.slots[name]; !ok {
+			return Query{}, fmt.Errorf("query: template has no slot %q", name)
+		}
+	}
+
+	params := slices.Clone(t.params)
+	for name, positions := range t.slots {
+		value, ok := values[name]
+		if !ok {
+			return Query{}, fmt.Errorf("query: no value bound for slot %q", name)
+		}
+		for _, i := range positions {
+			params[i] = value
+		}
+	}
+
+	return t.settings.finishParams(Query{SQL: t.sql, Params: params})
+}
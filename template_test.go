@@ -0,0 +1,62 @@
+package query
+
+import "testing"
+
+func TestTemplateBind(t *testing.T) {
+	tpl, err := NewQueryBuilder().
+		Table("users").
+		Select("id", "name").
+		Where("active", "=", true).
+		Where("age", ">", Slot("min_age")).
+		OrWhere("referrer_id", "=", Slot("user_id")).
+		OrWhere("id", "=", Slot("user_id")).
+		Template()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	query, err := tpl.Bind(map[string]interface{}{"min_age": 18, "user_id": 7})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := "select id, name from users where active = $1 and age > $2 or referrer_id = $3 or id = $4"
+	if query.SQL != expectedSQL || tpl.SQL() != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	expectedParams := []interface{}{true, 18, 7, 7}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+
+	again, err := tpl.Bind(map[string]interface{}{"min_age": 21, "user_id": 8})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if again.Params[1] != 21 || query.Params[1] != 18 {
+		t.Errorf("Expected independent params per Bind, got: %v and %v", query.Params, again.Params)
+	}
+}
+
+func TestTemplateBindErrors(t *testing.T) {
+	tpl, err := NewQueryBuilder().
+		Table("users").
+		Where("id", "=", Slot("id")).
+		Template()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := tpl.Bind(map[string]interface{}{}); err == nil {
+		t.Error("Expected error for missing slot value")
+	}
+	if _, err := tpl.Bind(map[string]interface{}{"id": 1, "other": 2}); err == nil {
+		t.Error("Expected error for unknown slot")
+	}
+	if _, err := tpl.Bind(map[string]interface{}{"id": make(chan int)}); err == nil {
+		t.Error("Expected error for unsupported value")
+	}
+}
@@ -0,0 +1,36 @@
+package query
+
+import "time"
+
+// TimeZone converts every time.Time parameter to loc before binding. Rows
+// scanned by Runner.ExportCSV and Runner.ExportJSON are converted to loc as
+// well, so mixed-timezone values are handled in one place.
+func (b *QueryBuilder) TimeZone(loc *time.Location) *QueryBuilder {
+	b.timeLocation = loc
+	return b
+}
+
+// UTC is shorthand for TimeZone(time.UTC)
+func (b *QueryBuilder) UTC() *QueryBuilder {
+	return b.TimeZone(time.UTC)
+}
+
+func (b *QueryBuilder) convertTimes(values []interface{}) {
+	convertTimes(values, b.timeLocation)
+}
+
+func convertTimes(values []interface{}, loc *time.Location) {
+	if loc == nil {
+		return
+	}
+	for i, value := range values {
+		switch v := value.(type) {
+		case time.Time:
+			values[i] = v.In(loc)
+		case *time.Time:
+			if v != nil {
+				values[i] = v.In(loc)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestTimeZoneConvertsParams(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	createdAt := time.Date(2024, 3, 1, 9, 0, 0, 0, tokyo)
+
+	query := NewQueryBuilder().
+		Table("events").
+		UTC().
+		Where("created_at", ">", createdAt).
+		Where("updated_at", "<", &createdAt).
+		Build()
+
+	for i, param := range query.Params {
+		var got time.Time
+		switch v := param.(type) {
+		case time.Time:
+			got = v
+		case *time.Time:
+			got = *v
+		}
+		if got.Location() != time.UTC || !got.Equal(createdAt) {
+			t.Errorf("Expected param %d as UTC %v, got: %v", i+1, createdAt.UTC(), param)
+		}
+	}
+
+	if createdAt.Location() != tokyo {
+		t.Errorf("Expected original value to be untouched, got: %v", createdAt)
+	}
+}
+
+func TestTimeZoneWithoutPolicyKeepsParams(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	createdAt := time.Date(2024, 3, 1, 9, 0, 0, 0, tokyo)
+
+	query := NewQueryBuilder().
+		Table("events").
+		Where("created_at", ">", createdAt).
+		Build()
+
+	if got := query.Params[0].(time.Time); got.Location() != tokyo {
+		t.Errorf("Expected param in original location, got: %v", got)
+	}
+}
+
+func TestTimeZoneConvertsScannedValues(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select created_at from events",
+		[]string{"created_at"},
+		[]driver.Value{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	)
+
+	var buf bytes.Buffer
+	qb := NewQueryBuilder().
+		Table("events").
+		Select("created_at").
+		TimeZone(time.FixedZone("JST", 9*60*60))
+	err := NewRunner(db).ExportCSV(context.Background(), qb, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "2024-03-01T09:00:00+09:00\n"
+	if buf.String() != expected {
+		t.Errorf("Expected CSV: %q, got: %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,143 @@
+package query
+
+import "errors"
+
+// TreeBuilder builds a recursive CTE walking an adjacency-list table from
+// its root rows down to every descendant. Table and column names are quoted
+// for the dialect. Oracle's CONNECT BY form is not generated; there is no
+// Oracle dialect, and every other database takes the recursive CTE.
+type TreeBuilder struct {
+	table       string
+	start       *Expr
+	parentCol   string
+	idCol       string
+	depthColumn string
+	paramStyle  ParameterStyle
+	dialect     Dialect
+}
+
+// Tree starts a hierarchy query over table
+func Tree(table string) *TreeBuilder {
+	style, dialect := defaults()
+	return &TreeBuilder{
+		table:      table,
+		paramStyle: style,
+		dialect:    dialect,
+	}
+}
+
+func (t *TreeBuilder) ParameterPlaceholder(style ParameterStyle) *TreeBuilder {
+	t.paramStyle = style
+	return t
+}
+
+// Dialect targets a specific database and switches to its usual
+// placeholder style, as QueryBuilder.Dialect does
+func (t *TreeBuilder) Dialect(dialect Dialect) *TreeBuilder {
+	t.dialect = dialect
+	switch dialect {
+	case Postgres:
+		t.paramStyle = DollarNumber
+	case MySQL, DuckDB, CQL:
+		t.paramStyle = QuestionMark
+	}
+	return t
+}
+
+// StartWith sets the condition selecting the root rows
+func (t *TreeBuilder) StartWith(condition string, args ...interface{}) *TreeBuilder {
+	expr := Raw(condition, args...)
+	t.start = &expr
+	return t
+}
+
+// ConnectBy links each child's parentColumn to its parent's idColumn. It
+// is required.
+func (t *TreeBuilder) ConnectBy(parentColumn, idColumn string) *TreeBuilder {
+	t.parentCol = parentColumn
+	t.idCol = idColumn
+	return t
+}
+
+// Depth adds a column holding each row's depth, starting at 1 for roots
+func (t *TreeBuilder) Depth(column string) *TreeBuilder {
+	t.depthColumn = column
+	return t
+}
+
+func (t *TreeBuilder) Build() Query {
+	q, err := t.TryBuild()
+	if err != nil {
+		return Query{}
+	}
+	return q
+}
+
+// TryBuild generates the SQL and parameters. It fails without ConnectBy,
+// with ErrUnsafeIdentifier for a name that cannot be quoted, and with
+// ErrUnsupportedFeature on databases without recursive CTEs.
+func (t *TreeBuilder) TryBuild() (Query, error) {
+	if t.parentCol == "" || t.idCol == "" {
+		return Query{}, errors.New("query: tree needs ConnectBy")
+	}
+	dialect := resolveDialect(t.dialect, t.paramStyle)
+	if !dialect.Supports(RecursiveCTE) {
+		return Query{}, &UnsupportedFeatureError{Feature: RecursiveCTE, Dialect: dialect}
+	}
+
+	table, err := dialect.QuoteIdentifier(t.table)
+	if err != nil {
+		return Query{}, err
+	}
+	parentCol, err := dialect.QuoteIdentifier(t.parentCol)
+	if err != nil {
+		return Query{}, err
+	}
+	idCol, err := dialect.QuoteIdentifier(t.idCol)
+	if err != nil {
+		return Query{}, err
+	}
+	var depthColumn string
+	if t.depthColumn != "" {
+		if depthColumn, err = dialect.QuoteIdentifier(t.depthColumn); err != nil {
+			return Query{}, err
+		}
+	}
+
+	w := &sqlWriter{style: t.paramStyle, dialect: dialect}
+
+	w.write("with recursive tree as (select ")
+	w.write(table)
+	w.write(".*")
+	if depthColumn != "" {
+		w.write(", 1 as ")
+		w.write(depthColumn)
+	}
+	w.write(" from ")
+	w.write(table)
+	if t.start != nil {
+		w.write(" where ")
+		w.expr(*t.start)
+	}
+
+	w.write(" union all select ")
+	w.write(table)
+	w.write(".*")
+	if depthColumn != "" {
+		w.write(", tree.")
+		w.write(depthColumn)
+		w.write(" + 1")
+	}
+	w.write(" from ")
+	w.write(table)
+	w.write(" join tree on ")
+	w.write(table + "." + parentCol)
+	w.write(" = tree.")
+	w.write(idCol)
+	w.write(") select * from tree")
+
+	return Query{
+		SQL:    string(w.buf),
+		Params: w.params,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTreeQuery(t *testing.T) {
+	query := Tree("categories").
+		StartWith("parent_id is null").
+		ConnectBy("parent_id", "id").
+		Depth("level").
+		Build()
+
+	expectedSQL := `with recursive tree as (select "categories".*, 1 as "level" from "categories" where parent_id is null` +
+		` union all select "categories".*, tree."level" + 1 from "categories" join tree on "categories"."parent_id" = tree."id")` +
+		` select * from tree`
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 0 {
+		t.Errorf("Expected no params, got: %v", query.Params)
+	}
+}
+
+func TestTreeQueryWithBoundRoot(t *testing.T) {
+	query := Tree("employees").
+		ParameterPlaceholder(QuestionMark).
+		StartWith("id = ?", 42).
+		ConnectBy("manager_id", "id").
+		Build()
+
+	expectedSQL := "with recursive tree as (select `employees`.* from `employees` where id = ?" +
+		" union all select `employees`.* from `employees` join tree on `employees`.`manager_id` = tree.`id`)" +
+		" select * from tree"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 1 || query.Params[0] != 42 {
+		t.Errorf("Expected params: [42], got: %v", query.Params)
+	}
+}
+
+func TestTreeQueryErrors(t *testing.T) {
+	if _, err := Tree("categories").StartWith("parent_id is null").TryBuild(); err == nil {
+		t.Error("Expected an error without ConnectBy")
+	}
+	if _, err := Tree("categories; drop table users").ConnectBy("parent_id", "id").TryBuild(); !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+	if _, err := Tree("categories").ConnectBy("parent_id", "id").Depth("level)").TryBuild(); !errors.Is(err, ErrUnsafeIdentifier) {
+		t.Errorf("Expected ErrUnsafeIdentifier, got: %v", err)
+	}
+	if _, err := Tree("categories").Dialect(CQL).ConnectBy("parent_id", "id").TryBuild(); !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+	}
+}
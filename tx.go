@@ -0,0 +1,71 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Isolation levels for TxOptions
+const (
+	DefaultIsolation = sql.LevelDefault
+	ReadUncommitted  = sql.LevelReadUncommitted
+	ReadCommitted    = sql.LevelReadCommitted
+	RepeatableRead   = sql.LevelRepeatableRead
+	Serializable     = sql.LevelSerializable
+)
+
+// TxOptions configures a transaction run with RunInTx
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+
+	// Deferrable makes a Postgres serializable read-only transaction wait
+	// for a snapshot that cannot fail with a serialization error, by
+	// running "set transaction deferrable" first. Postgres only; it has
+	// no effect unless Isolation is Serializable and ReadOnly is set.
+	Deferrable bool
+}
+
+// RunInTx begins a transaction with opts and calls fn with a Runner bound
+// to it, which keeps this Runner's options except tag routing: every
+// statement runs in the transaction. The transaction commits when fn
+// returns nil and rolls back when it returns an error or panics. The
+// Runner's DB must be a *sql.DB or *sql.Conn.
+func (r *Runner) RunInTx(ctx context.Context, opts TxOptions, fn func(tx *Runner) error) (err error) {
+	beginner, ok := r.db.(interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	})
+	if !ok {
+		return errors.New("query: RunInTx needs a Runner on a *sql.DB or *sql.Conn")
+	}
+	tx, err := beginner.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r.inFlight != nil {
+			r.inFlight.forget(tx)
+		}
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if opts.Deferrable {
+		if _, err := tx.ExecContext(ctx, "set transaction deferrable"); err != nil {
+			return err
+		}
+	}
+
+	txRunner := *r
+	txRunner.db = tx
+	txRunner.routes = nil
+	return fn(&txRunner)
+}
@@ -0,0 +1,81 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestRunInTx(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("select balance from accounts where id = $1", []string{"balance"}, []driver.Value{int64(10)})
+	runner := NewRunner(db, RouteTag("reports", nil))
+
+	var balance int64
+	err := runner.RunInTx(context.Background(), TxOptions{Isolation: Serializable, ReadOnly: true, Deferrable: true}, func(tx *Runner) error {
+		if _, ok := tx.db.(*sql.Tx); !ok {
+			t.Errorf("Expected a Runner bound to a *sql.Tx, got %T", tx.db)
+		}
+		row, err := tx.QueryRow(context.Background(), NewQueryBuilder().Table("accounts").Select("balance").Where("id", "=", 1).Tag("reports"))
+		if err != nil {
+			return err
+		}
+		return row.Scan(&balance)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if balance != 10 {
+		t.Errorf("Expected balance 10, got %d", balance)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0].SQL != "set transaction deferrable" {
+		t.Errorf("Expected set transaction deferrable first, got: %v", calls)
+	}
+	txs := fake.Txs()
+	if len(txs) != 1 || !txs[0].ReadOnly || txs[0].Isolation != driver.IsolationLevel(Serializable) || !txs[0].Committed {
+		t.Errorf("Unexpected transaction: %+v", txs)
+	}
+}
+
+func TestRunInTxRollback(t *testing.T) {
+	db, fake := newFakeDB(t)
+	runner := NewRunner(db)
+
+	failure := errors.New("boom")
+	err := runner.RunInTx(context.Background(), TxOptions{}, func(tx *Runner) error {
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the callback error, got: %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to propagate")
+			}
+		}()
+		runner.RunInTx(context.Background(), TxOptions{}, func(tx *Runner) error {
+			panic("boom")
+		})
+	}()
+
+	txs := fake.Txs()
+	if len(txs) != 2 || !txs[0].RolledBack || !txs[1].RolledBack || txs[0].Committed {
+		t.Errorf("Expected both transactions rolled back, got: %+v", txs)
+	}
+}
+
+func TestRunInTxNested(t *testing.T) {
+	db, _ := newFakeDB(t)
+	err := NewRunner(db).RunInTx(context.Background(), TxOptions{}, func(tx *Runner) error {
+		return tx.RunInTx(context.Background(), TxOptions{}, func(*Runner) error { return nil })
+	})
+	if err == nil {
+		t.Error("Expected an error for a Runner already in a transaction")
+	}
+}
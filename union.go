@@ -0,0 +1,125 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidUnion is returned by Compound.TryBuild when the branches
+// cannot be combined
+var ErrInvalidUnion = errors.New("invalid union")
+
+// Compound combines selects with UNION or UNION ALL. Build it with Union
+// or UnionAll.
+type Compound struct {
+	operator string
+	branches []*QueryBuilder
+	order    string
+	limit    int
+	offset   int
+}
+
+var _ Builder = (*Compound)(nil)
+
+// Union combines the builders with UNION, removing duplicate rows. A
+// branch keeps its own OrderBy, Limit, Offset and locking: it is wrapped
+// in parentheses, which Postgres, MySQL and DuckDB require for those
+// clauses to apply to the branch rather than the whole union. Other
+// branches are written bare. The first builder decides the placeholder
+// style, dialect and keyword casing.
+func Union(builders ...*QueryBuilder) *Compound {
+	return &Compound{operator: " union ", branches: builders}
+}
+
+// UnionAll is Union keeping duplicate rows
+func UnionAll(builders ...*QueryBuilder) *Compound {
+	return &Compound{operator: " union all ", branches: builders}
+}
+
+// OrderBy orders the combined rows by output column names
+func (c *Compound) OrderBy(order string) *Compound {
+	c.order = order
+	return c
+}
+
+// Limit limits the combined rows
+func (c *Compound) Limit(limit int) *Compound {
+	c.limit = limit
+	return c
+}
+
+// Offset skips the first combined rows
+func (c *Compound) Offset(offset int) *Compound {
+	c.offset = offset
+	return c
+}
+
+// Build generates the SQL and parameters, or an empty Query if the
+// branches are invalid (see TryBuild)
+func (c *Compound) Build() Query {
+	query, err := c.TryBuild()
+	if err != nil {
+		return Query{}
+	}
+	return query
+}
+
+// TryBuild validates every branch and generates the SQL and parameters
+func (c *Compound) TryBuild() (Query, error) {
+	if len(c.branches) < 2 {
+		return Query{}, fmt.Errorf("%w: need at least two branches, got %d", ErrInvalidUnion, len(c.branches))
+	}
+	branches, err := hookBranches(c.branches)
+	if err != nil {
+		return Query{}, err
+	}
+	first := branches[0]
+	if err := first.require(SetOperations); err != nil {
+		return Query{}, err
+	}
+	for i, branch := range branches {
+		if branch.queryType != SelectQuery || branch.exists {
+			return Query{}, fmt.Errorf("%w: branch %d is not a plain select", ErrInvalidUnion, i)
+		}
+		if err := branch.validate(); err != nil {
+			return Query{}, err
+		}
+	}
+
+	w := first.newWriter()
+	for i, branch := range branches {
+		if i > 0 {
+			w.write(c.operator)
+		}
+		w.types, w.casts = branch.columnTypes(), branch.castsParams()
+		if branch.branchClauses() {
+			w.write("(")
+			branch.writeSelect(&w)
+			w.write(")")
+		} else {
+			branch.writeSelect(&w)
+		}
+	}
+
+	if c.order != "" {
+		w.write(" order by ")
+		w.orderBy(c.order)
+	}
+	if c.limit > 0 {
+		w.write(" limit ")
+		w.writeInt(c.limit)
+	}
+	if c.offset > 0 {
+		w.write(" offset ")
+		w.writeInt(c.offset)
+	}
+
+	return first.paramSettings().finishParams(w.finish())
+}
+
+// branchClauses reports whether b has clauses that would otherwise bind to
+// the whole union
+func (b *QueryBuilder) branchClauses() bool {
+	return b.order != "" || len(b.orderExprs) > 0 || b.randomOrder || b.sampleFilter() ||
+		b.limit > 0 || b.offset > 0 || b.lockStrength != ""
+}
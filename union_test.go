@@ -0,0 +1,55 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnion(t *testing.T) {
+	query := Union(
+		NewQueryBuilder().Table("admins").Select("email").Where("active", "=", true),
+		NewQueryBuilder().Table("users").Select("email").Where("verified", "=", true),
+	).OrderBy("email").Limit(10).Build()
+
+	expectedSQL := "select email from admins where active = $1 union select email from users where verified = $2 order by email limit 10"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 {
+		t.Errorf("Unexpected params: %v", query.Params)
+	}
+}
+
+func TestUnionBranchOrdering(t *testing.T) {
+	query := UnionAll(
+		NewQueryBuilder().Table("posts").Select("id", "created_at").OrderBy("created_at desc").Limit(5),
+		NewQueryBuilder().Table("comments").Select("id", "created_at").Where("spam", "=", false),
+	).Build()
+
+	expectedSQL := "(select id, created_at from posts order by created_at desc limit 5) union all select id, created_at from comments where spam = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestUnionMySQL(t *testing.T) {
+	query := Union(
+		NewQueryBuilder().Dialect(MySQL).Table("a").Select("id").Where("x", "=", 1).Limit(1),
+		NewQueryBuilder().Dialect(MySQL).Table("b").Select("id").Where("y", "=", 2),
+	).Build()
+
+	expectedSQL := "(select id from a where x = ? limit 1) union select id from b where y = ?"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestUnionErrors(t *testing.T) {
+	if _, err := Union(NewQueryBuilder().Table("a")).TryBuild(); !errors.Is(err, ErrInvalidUnion) {
+		t.Errorf("Expected ErrInvalidUnion, got: %v", err)
+	}
+	_, err := Union(NewQueryBuilder().Table("a"), NewQueryBuilder().Table("b").Delete()).TryBuild()
+	if !errors.Is(err, ErrInvalidUnion) {
+		t.Errorf("Expected ErrInvalidUnion, got: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unnestAlias names the rows of an UpdateFromUnnest
+const unnestAlias = "u"
+
+// WhereInUnnest adds "column in (select unnest($1::bigint[]))", binding
+// the whole slice as one Postgres array instead of one param per value.
+// values is a slice of integers, floats, strings, bools or times.
+// Postgres only.
+func (b *QueryBuilder) WhereInUnnest(column string, values interface{}) *QueryBuilder {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice {
+		return b.fail(fmt.Errorf("%w: WhereInUnnest needs a slice, got %T", ErrUnsupportedParam, values))
+	}
+	elemType, ok := arrayType(v.Type().Elem())
+	if !ok {
+		return b.fail(fmt.Errorf("%w: no Postgres array type for %T", ErrUnsupportedParam, values))
+	}
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	array, err := arrayText(items)
+	if err != nil {
+		return b.fail(err)
+	}
+	b.unnest = true
+	return b.WhereRaw(column+" in (select unnest(?::"+elemType+"[]))", array)
+}
+
+// UpdateFromUnnest updates many rows, each to its own values, in one
+// statement with one param per column:
+//
+//	update t set x = u.x from unnest($1::bigint[], $2::text[]) as u(id, x)
+//	where t.id = u.id
+//
+// Each row holds the key followed by the columns' values. Array types come
+// from RegisterColumnTypes, or else from the Go values. Postgres only.
+func (b *QueryBuilder) UpdateFromUnnest(key string, columns []string, rows [][]interface{}) *QueryBuilder {
+	b.queryType = UpdateQuery
+	b.unnest = true
+	names := append([]string{key}, columns...)
+
+	arrays := make([]interface{}, len(names))
+	types := make([]string, len(names))
+	for c, name := range names {
+		values := make([]interface{}, len(rows))
+		for r, row := range rows {
+			if len(row) != len(names) {
+				return b.fail(fmt.Errorf("%w: row %d has %d values for %d columns", ErrColumnCount, r+1, len(row), len(names)))
+			}
+			values[r] = row[c]
+		}
+		if columnType, ok := LookupColumnType(b.table, name); ok {
+			types[c] = string(columnType)
+		} else {
+			types[c] = valuesArrayType(values)
+		}
+		if types[c] == "" {
+			return b.fail(fmt.Errorf("%w: no Postgres array type for column %s", ErrUnsupportedParam, name))
+		}
+		array, err := arrayText(values)
+		if err != nil {
+			return b.fail(err)
+		}
+		arrays[c] = array
+	}
+
+	var from strings.Builder
+	from.WriteString("unnest(")
+	for c := range names {
+		if c > 0 {
+			from.WriteString(", ")
+		}
+		from.WriteString("?::" + types[c] + "[]")
+	}
+	from.WriteString(") as " + unnestAlias + "(" + strings.Join(names, ", ") + ")")
+	expr := Raw(from.String(), arrays...)
+	b.updateFrom = &expr
+
+	b.updateColumns = b.updateColumns[:0]
+	b.updateValues = b.updateValues[:0]
+	for _, column := range columns {
+		b.updateColumns = append(b.updateColumns, column)
+		b.updateValues = append(b.updateValues, Raw(unnestAlias+"."+column))
+	}
+	return b.WhereRaw(b.table + "." + key + " = " + unnestAlias + "." + key)
+}
+
+// validateUnnest keeps the unnest helpers to Postgres
+func (b *QueryBuilder) validateUnnest() error {
+	if !b.unnest {
+		return nil
+	}
+	return b.require(Arrays)
+}
+
+// arrayType returns the Postgres element type for a Go type
+func arrayType(t reflect.Type) (string, bool) {
+	if t == timeType {
+		return "timestamptz", true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "bigint", true
+	case reflect.Float32, reflect.Float64:
+		return "double precision", true
+	case reflect.String:
+		return "text", true
+	case reflect.Bool:
+		return "boolean", true
+	case reflect.Ptr:
+		return arrayType(t.Elem())
+	}
+	return "", false
+}
+
+// valuesArrayType returns the element type of the first non-nil value,
+// text when all are nil, and "" when it has no array type
+func valuesArrayType(values []interface{}) string {
+	for _, value := range values {
+		if value != nil {
+			elemType, _ := arrayType(reflect.TypeOf(value))
+			return elemType
+		}
+	}
+	return "text"
+}
+
+// arrayText formats values as a Postgres array literal such as
+// {1,NULL,"a \"b\""}, which the driver binds as a plain string
+func arrayText(values []interface{}) (string, error) {
+	var text strings.Builder
+	text.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			text.WriteByte(',')
+		}
+		v := reflect.ValueOf(value)
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if !v.IsValid() || v.Kind() == reflect.Ptr {
+			text.WriteString("NULL")
+			continue
+		}
+		if t, ok := v.Interface().(time.Time); ok {
+			writeArrayString(&text, t.Format(time.RFC3339Nano))
+			continue
+		}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			text.WriteString(strconv.FormatInt(v.Int(), 10))
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			text.WriteString(strconv.FormatUint(v.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			text.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+		case reflect.Bool:
+			if v.Bool() {
+				text.WriteString("t")
+			} else {
+				text.WriteString("f")
+			}
+		case reflect.String:
+			writeArrayString(&text, v.String())
+		default:
+			return "", fmt.Errorf("%w: %T in a Postgres array", ErrUnsupportedParam, value)
+		}
+	}
+	text.WriteByte('}')
+	return text.String(), nil
+}
+
+func writeArrayString(text *strings.Builder, s string) {
+	text.WriteByte('"')
+	for _, c := range []byte(s) {
+		if c == '"' || c == '\\' {
+			text.WriteByte('\\')
+		}
+		text.WriteByte(c)
+	}
+	text.WriteByte('"')
+}
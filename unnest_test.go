@@ -0,0 +1,96 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWhereInUnnest(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		WhereInUnnest("id", []int64{3, 1, 42}).
+		Where("active", "=", true).
+		Build()
+
+	expectedSQL := "select * from users where id in (select unnest($1::bigint[])) and active = $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if query.Params[0] != "{3,1,42}" {
+		t.Errorf("Expected array param, got: %v", query.Params[0])
+	}
+
+	query = NewQueryBuilder().Table("users").WhereInUnnest("email", []string{`a"b`, `c\d`}).Build()
+	if query.Params[0] != `{"a\"b","c\\d"}` {
+		t.Errorf("Expected escaped text array, got: %v", query.Params[0])
+	}
+
+	query = NewQueryBuilder().Table("users").WhereInUnnest("id", []int(nil)).Build()
+	if query.Params[0] != "{}" {
+		t.Errorf("Expected empty array, got: %v", query.Params[0])
+	}
+}
+
+func TestUpdateFromUnnest(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("products").
+		UpdateFromUnnest("id", []string{"price", "name"}, [][]interface{}{
+			{1, 9.5, "Pen"},
+			{2, 12.0, nil},
+		}).
+		Build()
+
+	expectedSQL := "update products set price = u.price, name = u.name" +
+		" from unnest($1::bigint[], $2::double precision[], $3::text[]) as u(id, price, name)" +
+		" where products.id = u.id"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	expectedParams := []interface{}{"{1,2}", "{9.5,12}", `{"Pen",NULL}`}
+	for i, param := range query.Params {
+		if param != expectedParams[i] {
+			t.Errorf("Expected param %d: %v, got: %v", i+1, expectedParams[i], param)
+		}
+	}
+}
+
+func TestUpdateFromUnnestRegisteredTypes(t *testing.T) {
+	RegisterColumnTypes("unnest_accounts", map[string]ColumnType{"id": UUID})
+	defer RegisterColumnTypes("unnest_accounts", nil)
+
+	query := NewQueryBuilder().
+		Table("unnest_accounts").
+		UpdateFromUnnest("id", []string{"balance"}, [][]interface{}{
+			{"0f8fad5b-d9cb-469f-a165-70867728950e", 10},
+		}).
+		Where("frozen", "=", false).
+		ValidateAliases().
+		Build()
+
+	expectedSQL := "update unnest_accounts set balance = u.balance" +
+		" from unnest($1::uuid[], $2::bigint[]) as u(id, balance)" +
+		" where unnest_accounts.id = u.id and frozen = $3"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestUnnestErrors(t *testing.T) {
+	_, err := NewQueryBuilder().Dialect(MySQL).Table("users").WhereInUnnest("id", []int{1}).TryBuild()
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().Table("users").WhereInUnnest("id", 1).TryBuild()
+	if !errors.Is(err, ErrUnsupportedParam) {
+		t.Errorf("Expected ErrUnsupportedParam, got: %v", err)
+	}
+
+	_, err = NewQueryBuilder().
+		Table("users").
+		UpdateFromUnnest("id", []string{"name"}, [][]interface{}{{1}}).
+		TryBuild()
+	if !errors.Is(err, ErrColumnCount) {
+		t.Errorf("Expected ErrColumnCount, got: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidUpsert is returned by TryBuild when Upsert or ReturningChanged
+// is used on anything but an insert, or combined with IdempotencyKey
+var ErrInvalidUpsert = errors.New("invalid upsert")
+
+// Upsert turns an insert into an insert-or-update on the unique key
+// columns: every other inserted column is overwritten on conflict.
+// "on conflict (id) do update set name = excluded.name" on Postgres and
+// DuckDB, "on duplicate key update name = values(name)" on MySQL, where
+// the key comes from the table's unique indexes and the columns are only
+// used to pick what to update.
+func (b *QueryBuilder) Upsert(key ...string) *QueryBuilder {
+	b.upsertKey = key
+	return b
+}
+
+// ReturningChanged reports whether an upsert inserted or updated its row.
+// Postgres adds "returning (xmax = 0) as inserted"; MySQL already says so
+// in the affected row count (1 inserted, 2 updated), so the SQL is
+// unchanged. Read the answer with Runner.Upsert.
+func (b *QueryBuilder) ReturningChanged() *QueryBuilder {
+	b.returningChanged = true
+	return b
+}
+
+func (b *QueryBuilder) validateUpsert() error {
+	if len(b.upsertKey) == 0 && !b.returningChanged {
+		return nil
+	}
+	if b.queryType != InsertQuery {
+		return fmt.Errorf("%w: %s statement", ErrInvalidUpsert, queryTypeName(b.queryType))
+	}
+	if len(b.upsertKey) == 0 {
+		return fmt.Errorf("%w: ReturningChanged without Upsert", ErrInvalidUpsert)
+	}
+	if b.idempotencyKey != "" {
+		return fmt.Errorf("%w: combined with IdempotencyKey", ErrInvalidUpsert)
+	}
+	if err := b.require(Upsert); err != nil {
+		return err
+	}
+	if b.returningChanged && b.target() == DuckDB {
+		return fmt.Errorf("%w: ReturningChanged on %s", ErrUnsupportedFeature, b.target())
+	}
+	return nil
+}
+
+// upsertColumns returns the inserted columns that are not part of the key
+func (b *QueryBuilder) upsertColumns() []string {
+	var columns []string
+	for _, column := range b.insertColumns {
+		if !slices.Contains(b.upsertKey, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+func (b *QueryBuilder) writeUpsert(w *sqlWriter) {
+	if len(b.upsertKey) == 0 {
+		return
+	}
+	columns := b.upsertColumns()
+
+	if b.target() == MySQL {
+		w.write(" on duplicate key update ")
+		if len(columns) == 0 {
+			// Nothing to overwrite, but the clause needs one assignment
+			columns = b.upsertKey[:1]
+		}
+		for i, column := range columns {
+			if i > 0 {
+				w.write(", ")
+			}
+			w.ident(column)
+			w.write(" = values(")
+			w.ident(column)
+			w.write(")")
+		}
+		return
+	}
+
+	w.write(" on conflict (")
+	for i, column := range b.upsertKey {
+		if i > 0 {
+			w.write(", ")
+		}
+		w.ident(column)
+	}
+	if len(columns) == 0 {
+		w.write(") do nothing")
+	} else {
+		w.write(") do update set ")
+		for i, column := range columns {
+			if i > 0 {
+				w.write(", ")
+			}
+			w.ident(column)
+			w.write(" = excluded.")
+			w.ident(column)
+		}
+	}
+	if b.returningChanged && b.target() == Postgres {
+		w.write(" returning (xmax = 0) as inserted")
+	}
+}
+
+// Upsert runs an insert built with Upsert and ReturningChanged and reports
+// whether the row was inserted (true) or an existing row updated (false).
+// A key-only upsert that hit an existing row reports false.
+func (r *Runner) Upsert(ctx context.Context, qb *QueryBuilder) (bool, error) {
+	if !qb.returningChanged {
+		return false, fmt.Errorf("%w: Runner.Upsert needs ReturningChanged", ErrInvalidUpsert)
+	}
+	if qb.target() == MySQL {
+		result, err := r.Exec(ctx, qb)
+		if err != nil {
+			return false, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return false, err
+		}
+		return affected == 1, nil
+	}
+
+	row, err := r.QueryRow(ctx, qb)
+	if err != nil {
+		return false, err
+	}
+	var inserted bool
+	if err := row.Scan(&inserted); errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return inserted, nil
+}
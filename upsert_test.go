@@ -0,0 +1,100 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestUpsert(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("counters").
+		InsertColumns("name", "hits").
+		Values("home", 1).
+		Upsert("name").
+		ReturningChanged().
+		Build()
+
+	expectedSQL := "insert into counters (name, hits) values ($1, $2) on conflict (name) do update set hits = excluded.hits returning (xmax = 0) as inserted"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestUpsertMySQL(t *testing.T) {
+	query := NewQueryBuilder().
+		Dialect(MySQL).
+		Table("counters").
+		InsertColumns("name", "hits").
+		Values("home", 1).
+		Upsert("name").
+		ReturningChanged().
+		Build()
+
+	expectedSQL := "insert into counters (name, hits) values (?, ?) on duplicate key update hits = values(hits)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestUpsertErrors(t *testing.T) {
+	builders := map[string]*QueryBuilder{
+		"no upsert":   NewQueryBuilder().Table("counters").InsertColumns("name").Values("home").ReturningChanged(),
+		"idempotency": NewQueryBuilder().Table("counters").InsertColumns("name").Values("home").Upsert("name").IdempotencyKey("name"),
+		"delete":      NewQueryBuilder().Table("counters").Delete().Upsert("name"),
+	}
+	for name, qb := range builders {
+		if _, err := qb.TryBuild(); !errors.Is(err, ErrInvalidUpsert) {
+			t.Errorf("%s: expected ErrInvalidUpsert, got: %v", name, err)
+		}
+	}
+
+	_, err := NewQueryBuilder().Dialect(DuckDB).Table("counters").
+		InsertColumns("name").Values("home").Upsert("name").ReturningChanged().TryBuild()
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got: %v", err)
+	}
+}
+
+func TestRunnerUpsert(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.On("insert into counters (name, hits) values ($1, $2) on conflict (name) do update set hits = excluded.hits returning (xmax = 0) as inserted",
+		[]string{"inserted"}, []driver.Value{false})
+
+	qb := NewQueryBuilder().
+		Table("counters").
+		InsertColumns("name", "hits").
+		Values("home", 1).
+		Upsert("name").
+		ReturningChanged()
+
+	inserted, err := NewRunner(db).Upsert(context.Background(), qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if inserted {
+		t.Error("Expected an update, got an insert")
+	}
+}
+
+func TestRunnerUpsertMySQL(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.OnExec("insert into counters (name, hits) values (?, ?) on duplicate key update hits = values(hits)", 1)
+
+	qb := NewQueryBuilder().
+		Dialect(MySQL).
+		Table("counters").
+		InsertColumns("name", "hits").
+		Values("home", 1).
+		Upsert("name").
+		ReturningChanged()
+
+	inserted, err := NewRunner(db).Upsert(context.Background(), qb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !inserted {
+		t.Error("Expected an insert, got an update")
+	}
+}
@@ -0,0 +1,89 @@
+package query
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidUUID is returned by TryBuild when WhereUUID is given a string
+// that is not a UUID
+var ErrInvalidUUID = errors.New("invalid UUID")
+
+// uuidFunc is the SQL of NewUUID, rewritten per dialect when written
+const uuidFunc = "gen_random_uuid()"
+
+// NewUUID is a value expression generating a random UUID on the server:
+// gen_random_uuid() on Postgres and DuckDB, uuid() on MySQL. Use it in
+// Values, Insert or Set.
+func NewUUID() Expr {
+	return Raw(uuidFunc)
+}
+
+// WhereUUID adds "column = id" after checking id is a UUID, in the
+// canonical 8-4-4-4-12 form, as 32 hex digits, or wrapped in braces. The
+// value is bound like any other UUID param: in canonical lowercase form, or
+// as 16 bytes with BinaryUUIDs. Anything else is reported by TryBuild as
+// ErrInvalidUUID.
+func (b *QueryBuilder) WhereUUID(column, id string) *QueryBuilder {
+	uuid, ok := parseUUID(id)
+	if !ok {
+		return b.fail(fmt.Errorf("%w: %q", ErrInvalidUUID, id))
+	}
+	return b.Where(column, "=", uuid)
+}
+
+// BinaryUUIDs binds UUID params as their 16 raw bytes, for MySQL
+// BINARY(16) columns, instead of the canonical string
+func (b *QueryBuilder) BinaryUUIDs() *QueryBuilder {
+	b.binaryUUIDs = true
+	return b
+}
+
+func parseUUID(s string) ([16]byte, bool) {
+	var uuid [16]byte
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return uuid, false
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return uuid, false
+	}
+	if _, err := hex.Decode(uuid[:], []byte(s)); err != nil {
+		return uuid, false
+	}
+	return uuid, true
+}
+
+func formatUUID(uuid [16]byte) string {
+	s := hex.EncodeToString(uuid[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// convertUUIDs replaces UUID params - any [16]byte array type, such as
+// github.com/google/uuid.UUID - with the canonical string, or with the raw
+// bytes when binary is set. Drivers disagree on how to bind such arrays,
+// and some send the Valuer string where a binary column expects bytes.
+func convertUUIDs(params []interface{}, binary bool) {
+	for i, param := range params {
+		if param == nil {
+			continue
+		}
+		v := reflect.ValueOf(param)
+		if v.Kind() != reflect.Array || v.Len() != 16 || v.Type().Elem().Kind() != reflect.Uint8 {
+			continue
+		}
+		var uuid [16]byte
+		reflect.Copy(reflect.ValueOf(&uuid).Elem(), v)
+		if binary {
+			params[i] = uuid[:]
+		} else {
+			params[i] = formatUUID(uuid)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package query
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type testUUID [16]byte
+
+func TestNewUUID(t *testing.T) {
+	query := NewQueryBuilder().
+		Table("users").
+		InsertColumns("id", "name").
+		Values(NewUUID(), "Ann").
+		Build()
+
+	expectedSQL := "insert into users (id, name) values (gen_random_uuid(), $1)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().
+		Dialect(MySQL).
+		Table("users").
+		InsertColumns("id", "name").
+		Values(NewUUID(), "Ann").
+		Build()
+
+	expectedSQL = "insert into users (id, name) values (uuid(), ?)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestWhereUUID(t *testing.T) {
+	for _, id := range []string{
+		"0F8FAD5B-D9CB-469F-A165-70867728950E",
+		"0f8fad5bd9cb469fa16570867728950e",
+		"{0f8fad5b-d9cb-469f-a165-70867728950e}",
+	} {
+		query, err := NewQueryBuilder().Table("users").WhereUUID("id", id).TryBuild()
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", id, err)
+		}
+		expectedSQL := "select * from users where id = $1"
+		if query.SQL != expectedSQL {
+			t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+		}
+		if query.Params[0] != "0f8fad5b-d9cb-469f-a165-70867728950e" {
+			t.Errorf("Expected canonical UUID for %s, got: %v", id, query.Params[0])
+		}
+	}
+}
+
+func TestWhereUUIDInvalid(t *testing.T) {
+	for _, id := range []string{"", "42", "0f8fad5b-d9cb-469f-a165-70867728950", "0f8fad5bxd9cb-469f-a165-70867728950e", "zf8fad5bd9cb469fa16570867728950e"} {
+		_, err := NewQueryBuilder().Table("users").WhereUUID("id", id).TryBuild()
+		if !errors.Is(err, ErrInvalidUUID) {
+			t.Errorf("Expected ErrInvalidUUID for %q, got: %v", id, err)
+		}
+	}
+}
+
+func TestUUIDParams(t *testing.T) {
+	id := testUUID{0x0f, 0x8f, 0xad, 0x5b, 0xd9, 0xcb, 0x46, 0x9f, 0xa1, 0x65, 0x70, 0x86, 0x77, 0x28, 0x95, 0x0e}
+
+	query := NewQueryBuilder().Table("users").Where("id", "=", id).Build()
+	if query.Params[0] != "0f8fad5b-d9cb-469f-a165-70867728950e" {
+		t.Errorf("Expected UUID bound as string, got: %#v", query.Params[0])
+	}
+
+	query = NewQueryBuilder().Dialect(MySQL).BinaryUUIDs().Table("users").Where("id", "=", id).Build()
+	param, ok := query.Params[0].([]byte)
+	if !ok || !bytes.Equal(param, id[:]) {
+		t.Errorf("Expected UUID bound as bytes, got: %#v", query.Params[0])
+	}
+}
+
+func TestWhereUUIDBinary(t *testing.T) {
+	query, err := NewQueryBuilder().Dialect(MySQL).BinaryUUIDs().Table("users").
+		WhereUUID("id", "0f8fad5b-d9cb-469f-a165-70867728950e").
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []byte{0x0f, 0x8f, 0xad, 0x5b, 0xd9, 0xcb, 0x46, 0x9f, 0xa1, 0x65, 0x70, 0x86, 0x77, 0x28, 0x95, 0x0e}
+	if param, ok := query.Params[0].([]byte); !ok || !bytes.Equal(param, expected) {
+		t.Errorf("Expected UUID bound as bytes, got: %#v", query.Params[0])
+	}
+}
+
+func TestTemplateBinaryUUIDs(t *testing.T) {
+	id := testUUID{0x0f, 0x8f, 0xad, 0x5b, 0xd9, 0xcb, 0x46, 0x9f, 0xa1, 0x65, 0x70, 0x86, 0x77, 0x28, 0x95, 0x0e}
+
+	tpl, err := NewQueryBuilder().Dialect(MySQL).BinaryUUIDs().Table("users").Where("id", "=", Slot("id")).Template()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	query, err := tpl.Bind(map[string]interface{}{"id": id})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if param, ok := query.Params[0].([]byte); !ok || !bytes.Equal(param, id[:]) {
+		t.Errorf("Expected UUID bound as bytes, got: %#v", query.Params[0])
+	}
+}
+
+func TestNewUUIDCompound(t *testing.T) {
+	branch := func(table string) *QueryBuilder {
+		return NewQueryBuilder().Dialect(MySQL).Table(table).Select("id").Where("id", "<>", NewUUID())
+	}
+
+	query, err := Union(branch("users"), branch("admins")).TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedSQL := "select id from users where id <> uuid() union select id from admins where id <> uuid()"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
@@ -0,0 +1,269 @@
+package query
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrUnsafeIdentifier is returned by TryBuild in strict identifier mode
+// when a table, alias or column name is not a plain SQL identifier
+var ErrUnsafeIdentifier = errors.New("unsafe identifier")
+
+// ErrUnsupportedParam is returned by TryBuild when a parameter value cannot
+// be bound by a database/sql driver
+var ErrUnsupportedParam = errors.New("unsupported parameter type")
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*(\.\*)?$`)
+
+// StrictIdentifiers makes TryBuild reject any table, alias or column name
+// that is not a plain (optionally qualified) identifier or is longer than
+// the dialect allows (ErrIdentifierTooLong, see MaxIdentifierLength), and
+// any where or having operator the dialect does not know
+// (ErrUnknownOperator, see SupportsOperator). The offending value is
+// reported unchanged; the query is never rewritten.
+func (b *QueryBuilder) StrictIdentifiers() *QueryBuilder {
+	b.strictIdentifiers = true
+	return b
+}
+
+func (b *QueryBuilder) validate() error {
+	b, err := b.withRelationPaths()
+	if err != nil {
+		return err
+	}
+	if err := b.check(); err != nil {
+		return err
+	}
+	if err := b.validateInsertSelect(); err != nil {
+		return err
+	}
+	return b.validateScope(nil)
+}
+
+// check runs every validation that does not depend on enclosing queries
+func (b *QueryBuilder) check() error {
+	if b.err != nil {
+		return b.err
+	}
+	if resolved, err := b.withRelationPaths(); err != nil {
+		return err
+	} else if resolved != b {
+		return resolved.check()
+	}
+	if err := b.validateJoins(); err != nil {
+		return err
+	}
+	if b.readOnly && b.queryType != SelectQuery {
+		return fmt.Errorf("%w: %s statement", ErrReadOnly, queryTypeName(b.queryType))
+	}
+	if b.exists && b.queryType != SelectQuery {
+		return fmt.Errorf("%w: AsExists on %s statement", ErrNotSelect, queryTypeName(b.queryType))
+	}
+	if len(b.partitions) > 0 {
+		if err := b.require(Partitions); err != nil {
+			return err
+		}
+	}
+	if err := b.validateSequences(); err != nil {
+		return err
+	}
+	if err := b.validateRows(); err != nil {
+		return err
+	}
+	if err := b.validateIdempotency(); err != nil {
+		return err
+	}
+	if err := b.validateUpsert(); err != nil {
+		return err
+	}
+	if err := b.validateLock(); err != nil {
+		return err
+	}
+	if err := b.validateCQL(); err != nil {
+		return err
+	}
+	if err := b.validateDuckDB(); err != nil {
+		return err
+	}
+	if err := b.validateUnnest(); err != nil {
+		return err
+	}
+	if err := b.validateCapture(); err != nil {
+		return err
+	}
+	if err := b.validateColumns(); err != nil {
+		return err
+	}
+	if err := b.validateAliases(); err != nil {
+		return err
+	}
+	if err := b.validateRewrites(); err != nil {
+		return err
+	}
+	if b.strictIdentifiers {
+		if err := b.validateIdentifiers(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *QueryBuilder) validateIdentifiers() error {
+	var identifiers []string
+	if b.fromExpr == nil && b.fromSub == nil {
+		identifiers = append(identifiers, b.table)
+	}
+	identifiers = append(identifiers, b.starExclude...)
+	for _, replace := range b.starReplace {
+		identifiers = append(identifiers, replace.Column)
+	}
+	identifiers = append(identifiers, b.partitions...)
+	identifiers = append(identifiers, b.lockOf...)
+	if b.tableAlias != "" {
+		identifiers = append(identifiers, aliasName(b.tableAlias))
+	}
+	for _, join := range b.joinClauses {
+		if join.Source == nil {
+			identifiers = append(identifiers, join.Table)
+		}
+		if join.Alias != "" {
+			identifiers = append(identifiers, aliasName(join.Alias))
+		}
+	}
+	walkWheres(b.whereClauses, func(where *WhereClause) {
+		if where.Column != "" {
+			identifiers = append(identifiers, where.Column)
+		}
+	})
+	identifiers = append(identifiers, b.groupBy...)
+	walkWheres(b.having, func(where *WhereClause) {
+		if arg := aggregateArgument(where.Column); arg != "" && arg != "*" {
+			identifiers = append(identifiers, arg)
+		}
+	})
+	identifiers = append(identifiers, b.insertColumns...)
+	identifiers = append(identifiers, b.updateColumns...)
+
+	for _, identifier := range identifiers {
+		if !isIdentifier(identifier) {
+			return unsafeIdentifier(identifier)
+		}
+	}
+
+	if b.queryType == SelectQuery {
+		for _, column := range b.columns {
+			if !isSelectColumn(column) {
+				return unsafeIdentifier(column)
+			}
+		}
+	}
+
+	if b.order != "" {
+		for _, term := range strings.Split(b.order, ",") {
+			if !isOrderTerm(term) {
+				return unsafeIdentifier(strings.TrimSpace(term))
+			}
+		}
+	}
+
+	if err := b.validateIdentifierLength(); err != nil {
+		return err
+	}
+	return b.validateOperators()
+}
+
+func unsafeIdentifier(identifier string) error {
+	return fmt.Errorf("%w: %q", ErrUnsafeIdentifier, identifier)
+}
+
+func isIdentifier(s string) bool {
+	return identifierPattern.MatchString(s)
+}
+
+// isSelectColumn accepts "*", an identifier, or "identifier as alias"
+func isSelectColumn(s string) bool {
+	if s == "*" {
+		return true
+	}
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		return isIdentifier(fields[0])
+	case 3:
+		return isIdentifier(fields[0]) && strings.EqualFold(fields[1], "as") && isIdentifier(fields[2])
+	default:
+		return false
+	}
+}
+
+// isOrderTerm accepts "identifier" optionally followed by asc or desc
+func isOrderTerm(s string) bool {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		return isIdentifier(fields[0])
+	case 2:
+		direction := strings.ToLower(fields[1])
+		return isIdentifier(fields[0]) && (direction == "asc" || direction == "desc")
+	default:
+		return false
+	}
+}
+
+// validateParams checks that every parameter is a type database/sql can
+// bind, and that Enum values are in range, so mistakes surface at build
+// time instead of as driver errors
+func validateParams(params []interface{}) error {
+	for i, param := range params {
+		if !isSupportedParam(param) {
+			return fmt.Errorf("%w: param %d (%T) is not a supported SQL type", ErrUnsupportedParam, i+1, param)
+		}
+		if err := checkEnum(param); err != nil {
+			return fmt.Errorf("param %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func isSupportedParam(param interface{}) bool {
+	if param == nil {
+		return true
+	}
+	if _, ok := param.(driver.Valuer); ok {
+		return true
+	}
+
+	v := reflect.ValueOf(param)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return true
+		}
+		if _, ok := v.Interface().(driver.Valuer); ok {
+			return true
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return v.Type().Elem().Kind() == reflect.Uint8
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStrictIdentifiersAcceptsPlainIdentifiers(t *testing.T) {
+	query, err := NewQueryBuilder().
+		StrictIdentifiers().
+		Table("users").
+		As("u").
+		Select("u.id", "u.created_at", "a.name as account_name").
+		LeftJoinAs("accounts", "a", "a.id = u.account_id").
+		Where("u.created_at", ">", "2024-01-01").
+		OrderBy("u.created_at desc, u.id").
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := "select u.id, u.created_at, a.name as account_name from users as u LEFT JOIN accounts as a on a.id = u.account_id where u.created_at > $1 order by u.created_at desc, u.id"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestStrictIdentifiersRejectsUnsafeIdentifiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *QueryBuilder
+		value   string
+	}{
+		{"table", NewQueryBuilder().Table("users; drop table users"), "users; drop table users"},
+		{"column", NewQueryBuilder().Table("users").Select("id", "name from secrets --"), "name from secrets --"},
+		{"where", NewQueryBuilder().Table("users").Where("1=1 or id", "=", 1), "1=1 or id"},
+		{"order", NewQueryBuilder().Table("users").OrderBy("name; delete from users"), "name; delete from users"},
+		{"insert", NewQueryBuilder().Table("users").InsertColumns("name)").Values("x"), "name)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := tt.builder.StrictIdentifiers().TryBuild()
+			if !errors.Is(err, ErrUnsafeIdentifier) {
+				t.Fatalf("Expected ErrUnsafeIdentifier, got: %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.value) {
+				t.Errorf("Expected error to name %q, got: %v", tt.value, err)
+			}
+			if query.SQL != "" {
+				t.Errorf("Expected no SQL, got: %s", query.SQL)
+			}
+		})
+	}
+}
+
+func TestBuildReturnsEmptyQueryOnValidationError(t *testing.T) {
+	query := NewQueryBuilder().
+		StrictIdentifiers().
+		Table("users where 1=1").
+		Build()
+
+	if query.SQL != "" || query.Params != nil {
+		t.Errorf("Expected empty query, got: %+v", query)
+	}
+}
+
+func TestNonStrictModeLeavesIdentifiersUntouched(t *testing.T) {
+	query, err := NewQueryBuilder().
+		Table("users").
+		Select("count(*) as total").
+		TryBuild()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSQL := "select count(*) as total from users"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestTryBuildAcceptsSupportedParams(t *testing.T) {
+	name := "John"
+	var missing *string
+	_, err := NewQueryBuilder().
+		Table("users").
+		Where("id", "=", 1).
+		Where("score", ">", 1.5).
+		Where("active", "=", true).
+		Where("name", "=", &name).
+		Where("nickname", "=", missing).
+		Where("avatar", "=", []byte{0x1}).
+		Where("created_at", "<", time.Now()).
+		Where("deleted_at", "is", sql.NullTime{}).
+		Where("manager_id", "is", nil).
+		TryBuild()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestTryBuildRejectsUnsupportedParams(t *testing.T) {
+	_, err := NewQueryBuilder().
+		Table("users").
+		Where("id", "=", 1).
+		Where("name", "=", "John").
+		Where("events", "=", make(chan int)).
+		TryBuild()
+	if !errors.Is(err, ErrUnsupportedParam) {
+		t.Fatalf("Expected ErrUnsupportedParam, got: %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "param 3 (chan int) is not a supported SQL type") {
+		t.Errorf("Expected descriptive error, got: %v", err)
+	}
+}
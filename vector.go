@@ -0,0 +1,52 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VectorMetric selects the pgvector distance operator
+type VectorMetric int
+
+const (
+	L2Distance     VectorMetric = iota // <->
+	InnerProduct                       // <#> (negative inner product)
+	CosineDistance                     // <=>
+)
+
+func (m VectorMetric) operator() string {
+	switch m {
+	case InnerProduct:
+		return "<#>"
+	case CosineDistance:
+		return "<=>"
+	default:
+		return "<->"
+	}
+}
+
+// VectorText formats a vector in pgvector's text format, e.g. [1,2.5,3]
+func VectorText(vector []float32) string {
+	var text strings.Builder
+	text.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			text.WriteByte(',')
+		}
+		text.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	}
+	text.WriteByte(']')
+	return text.String()
+}
+
+// OrderBySimilarity orders rows nearest first by the distance between
+// column and vector under metric
+func (b *QueryBuilder) OrderBySimilarity(column string, vector []float32, metric VectorMetric) *QueryBuilder {
+	return b.OrderByRaw(column+" "+metric.operator()+" ?", VectorText(vector))
+}
+
+// WhereSimilarityBelow keeps rows whose distance to vector under metric is
+// below threshold
+func (b *QueryBuilder) WhereSimilarityBelow(column string, vector []float32, metric VectorMetric, threshold float64) *QueryBuilder {
+	return b.WhereRaw(column+" "+metric.operator()+" ? < ?", VectorText(vector), threshold)
+}
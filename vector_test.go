@@ -0,0 +1,42 @@
+package query
+
+import "testing"
+
+func TestVectorSimilarity(t *testing.T) {
+	embedding := []float32{0.1, 0.25, -3}
+
+	query := NewQueryBuilder().
+		Table("documents").
+		Select("id", "title").
+		WhereSimilarityBelow("embedding", embedding, CosineDistance, 0.3).
+		OrderBySimilarity("embedding", embedding, CosineDistance).
+		Limit(5).
+		Build()
+
+	expectedSQL := "select id, title from documents where embedding <=> $1 < $2 order by embedding <=> $3 limit 5"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	if len(query.Params) != 3 || query.Params[0] != "[0.1,0.25,-3]" || query.Params[1] != 0.3 || query.Params[2] != "[0.1,0.25,-3]" {
+		t.Errorf("Expected params: [[0.1,0.25,-3], 0.3, [0.1,0.25,-3]], got: %v", query.Params)
+	}
+}
+
+func TestVectorMetricOperators(t *testing.T) {
+	tests := map[VectorMetric]string{
+		L2Distance:     "select * from items order by embedding <-> $1",
+		InnerProduct:   "select * from items order by embedding <#> $1",
+		CosineDistance: "select * from items order by embedding <=> $1",
+	}
+
+	for metric, expectedSQL := range tests {
+		query := NewQueryBuilder().
+			Table("items").
+			OrderBySimilarity("embedding", []float32{1, 2}, metric).
+			Build()
+		if query.SQL != expectedSQL {
+			t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package query
+
+import "slices"
+
+// Node is one element of a query's structure, visited by Walk. It is one
+// of *TableNode, *ColumnNode, *Expr, *JoinClause, *WhereClause or *OrderNode.
+type Node interface {
+	node()
+}
+
+// TableNode is the query's table and alias
+type TableNode struct {
+	Name  string
+	Alias string
+}
+
+// ColumnNode is a plain column in a select list, insert, update or group
+// by. Clause is "select", "insert", "update" or "group by".
+type ColumnNode struct {
+	Name   string
+	Clause string
+}
+
+// OrderNode is the ORDER BY text set with OrderBy
+type OrderNode struct {
+	Order string
+}
+
+func (*TableNode) node()   {}
+func (*ColumnNode) node()  {}
+func (*Expr) node()        {}
+func (*JoinClause) node()  {}
+func (*WhereClause) node() {}
+func (*OrderNode) node()   {}
+
+// Walk calls visitor for every node of the query in clause order: table,
+// columns and select expressions, joins, where clauses, grouping and having
+// clauses, then ordering. A where group is followed by its conditions, and
+// a raw where or a where compared to an expression by its *Expr; returning
+// false from visitor skips a node's children.
+//
+// Nodes may be modified in place and the changes are kept, so tools such as
+// tenancy enforcement or column rewriting can work on any query generically.
+// Walk is not safe for concurrent use with other methods on b.
+func (b *QueryBuilder) Walk(visitor func(node Node) bool) {
+	table := &TableNode{Name: b.table, Alias: b.tableAlias}
+	visitor(table)
+	b.table, b.tableAlias = table.Name, table.Alias
+
+	b.columns = walkColumns(b.columns, "select", visitor)
+	for i := range b.selectExprs {
+		visitor(&b.selectExprs[i])
+	}
+	b.insertColumns = walkColumns(b.insertColumns, "insert", visitor)
+	b.updateColumns = walkColumns(b.updateColumns, "update", visitor)
+
+	for _, join := range b.joinClauses {
+		visitor(join)
+	}
+
+	walkWhereNodes(b.whereClauses, visitor)
+	b.groupBy = walkColumns(b.groupBy, "group by", visitor)
+	walkWhereNodes(b.having, visitor)
+
+	if b.order != "" {
+		order := &OrderNode{Order: b.order}
+		visitor(order)
+		b.order = order.Order
+	}
+	for i := range b.orderExprs {
+		visitor(&b.orderExprs[i])
+	}
+}
+
+// walkColumns visits columns and returns them with the visitor's changes,
+// copying the slice before the first change since it may be shared with
+// the caller of Select or with other builders
+func walkColumns(columns []string, clause string, visitor func(node Node) bool) []string {
+	copied := false
+	for i, name := range columns {
+		column := &ColumnNode{Name: name, Clause: clause}
+		visitor(column)
+		if column.Name == name {
+			continue
+		}
+		if !copied {
+			columns = slices.Clone(columns)
+			copied = true
+		}
+		columns[i] = column.Name
+	}
+	return columns
+}
+
+func walkWhereNodes(clauses []*WhereClause, visitor func(node Node) bool) {
+	for _, where := range clauses {
+		if !visitor(where) {
+			continue
+		}
+		if where.Expr != nil {
+			visitor(where.Expr)
+		}
+		if expr, ok := where.Value.(Expr); ok {
+			visitor(&expr)
+			where.Value = expr
+		}
+		walkWhereNodes(where.Group, visitor)
+	}
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWalkVisitsInClauseOrder(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("orders").
+		Select("id").
+		SelectRaw("count(*)").
+		Join("customers", "customers.id = orders.customer_id").
+		Where("status", "=", "paid").
+		WhereGroup(func(g *QueryBuilder) { g.WhereRaw("total > ?", 10) }).
+		OrderBy("id desc")
+
+	var visited []string
+	qb.Walk(func(node Node) bool {
+		switch n := node.(type) {
+		case *TableNode:
+			visited = append(visited, "table:"+n.Name)
+		case *ColumnNode:
+			visited = append(visited, n.Clause+":"+n.Name)
+		case *Expr:
+			visited = append(visited, "expr:"+n.SQL)
+		case *JoinClause:
+			visited = append(visited, "join:"+n.Table)
+		case *WhereClause:
+			visited = append(visited, "where:"+n.Column)
+		case *OrderNode:
+			visited = append(visited, "order:"+n.Order)
+		}
+		return true
+	})
+
+	expected := "table:orders select:id expr:count(*) join:customers where:status where: where: expr:total > ? order:id desc"
+	if got := strings.Join(visited, " "); got != expected {
+		t.Errorf("Expected: %s, got: %s", expected, got)
+	}
+}
+
+func TestWalkPrunesChildren(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		WhereGroup(func(g *QueryBuilder) { g.Where("a", "=", 1).Where("b", "=", 2) })
+
+	count := 0
+	qb.Walk(func(node Node) bool {
+		if _, ok := node.(*WhereClause); ok {
+			count++
+			return false
+		}
+		return true
+	})
+	if count != 1 {
+		t.Errorf("Expected only the group to be visited, got %d where nodes", count)
+	}
+}
+
+func TestWalkRewrites(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("users").
+		Select("id", "email").
+		Where("email", "=", "a@example.com")
+
+	qb.Walk(func(node Node) bool {
+		switch n := node.(type) {
+		case *TableNode:
+			n.Name = "tenant_7.users"
+		case *ColumnNode:
+			if n.Name == "email" {
+				n.Name = "lower_email"
+			}
+		case *WhereClause:
+			if n.Column == "email" {
+				n.Column = "lower_email"
+			}
+		}
+		return true
+	})
+
+	expectedSQL := "select id, lower_email from tenant_7.users where lower_email = $1"
+	if query := qb.Build(); query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestWalkExpressionValue(t *testing.T) {
+	qb := NewQueryBuilder().
+		Table("sessions").
+		Where("expires_at", "<", Raw("now()")).
+		Where("id", ">", 3)
+
+	qb.Walk(func(node Node) bool {
+		if expr, ok := node.(*Expr); ok {
+			expr.SQL = "now() - ?::interval"
+			expr.Args = []interface{}{"7 days"}
+		}
+		return true
+	})
+
+	query := qb.Build()
+	expectedSQL := "select * from sessions where expires_at < now() - $1::interval and id > $2"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 || query.Params[0] != "7 days" || query.Params[1] != 3 {
+		t.Errorf("Expected params: [7 days, 3], got: %v", query.Params)
+	}
+}
+
+func TestWalkKeepsCallerColumns(t *testing.T) {
+	columns := []string{"id", "email"}
+	qb := NewQueryBuilder().Table("users").Select(columns...)
+	qb.Walk(func(node Node) bool {
+		if column, ok := node.(*ColumnNode); ok && column.Name == "email" {
+			column.Name = "lower_email"
+		}
+		return true
+	})
+
+	if columns[1] != "email" {
+		t.Errorf("Expected the caller's slice to be unchanged, got: %v", columns)
+	}
+	expectedSQL := "select id, lower_email from users"
+	if query := qb.Build(); query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
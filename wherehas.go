@@ -0,0 +1,88 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WhereHas keeps rows with at least one related row of the relation
+// registered for the table with DefineRelations, rendered as a correlated
+// "exists (select 1 from ...)". fn, which may be nil, adds conditions on
+// the related rows:
+//
+//	qb.WhereHas("posts", func(q *QueryBuilder) { q.Where("published", "=", true) })
+//
+// MorphTo relations have no single related table and are not supported.
+func (b *QueryBuilder) WhereHas(relation string, fn func(q *QueryBuilder)) *QueryBuilder {
+	return b.whereHas("exists", relation, fn)
+}
+
+// WhereDoesntHave keeps rows without any related row matching fn, see
+// WhereHas
+func (b *QueryBuilder) WhereDoesntHave(relation string, fn func(q *QueryBuilder)) *QueryBuilder {
+	return b.whereHas("not exists", relation, fn)
+}
+
+func (b *QueryBuilder) whereHas(operator, relation string, fn func(q *QueryBuilder)) *QueryBuilder {
+	sub, _, err := b.relationSubquery(relation, fn)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.whereSubquery("", operator, sub.Select("1"))
+}
+
+// relationSubquery builds a select from the related table of relation,
+// correlated with b's rows and restricted by fn. It also returns the name
+// the related table is known by in the subquery.
+func (b *QueryBuilder) relationSubquery(relation string, fn func(q *QueryBuilder)) (*QueryBuilder, string, error) {
+	table := strings.Fields(b.table)
+	if len(table) == 0 {
+		return nil, "", fmt.Errorf("relation %q needs the table set first", relation)
+	}
+	r, err := LookupRelation(table[0], relation)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// A relation back to the same table is aliased by its name
+	outer, inner := b.relationNames()[0], r.Related
+	sub := NewQueryBuilder().Table(r.Related)
+	if inner == outer {
+		inner = r.Name
+		if inner == outer {
+			inner = "related_" + r.Name
+		}
+		sub.As(inner)
+	}
+	if fn != nil {
+		fn(sub)
+	}
+	conditions := sub.scopedWheres()
+	sub.whereClauses, sub.baseFilters = nil, 0
+
+	switch r.Kind {
+	case HasOneRelation, HasManyRelation:
+		sub.CorrelateOn(inner+"."+r.ForeignKey, outer+"."+r.LocalKey)
+	case BelongsToRelation:
+		sub.CorrelateOn(inner+"."+r.LocalKey, outer+"."+r.ForeignKey)
+	case MorphManyRelation:
+		sub.Where(inner+"."+r.MorphType, "=", table[0]).
+			CorrelateOn(inner+"."+r.MorphID, outer+"."+r.LocalKey)
+	case BelongsToManyRelation:
+		sub.Join(r.Pivot, r.Pivot+"."+r.RelatedPivotKey+" = "+inner+"."+r.LocalKey).
+			CorrelateOn(r.Pivot+"."+r.ForeignKey, outer+"."+r.LocalKey)
+		for _, where := range r.PivotWheres {
+			sub.Where(r.Pivot+"."+where.Column, "=", where.Value)
+		}
+	default:
+		return nil, "", fmt.Errorf("relation %s.%s is polymorphic and has no related table", table[0], relation)
+	}
+
+	// OR conditions from fn must not loosen the correlation
+	if slices.ContainsFunc(conditions, func(where *WhereClause) bool { return where.JoinType == "or" }) {
+		conditions = []*WhereClause{{Group: conditions, JoinType: "and"}}
+	}
+	sub.whereClauses = append(sub.whereClauses, conditions...)
+	return sub, inner, nil
+}
@@ -0,0 +1,86 @@
+package query
+
+import "testing"
+
+func defineAuthorRelations(t *testing.T) {
+	defineTestRelations(t, "authors",
+		HasMany("articles", "author_id", "id"),
+		BelongsTo("agencies", "agency_id", "id").As("agency"),
+		BelongsToMany("awards", "author_awards", "author_id", "award_id").WherePivot("confirmed", true),
+		BelongsTo("authors", "mentor_id", "id").As("mentor"))
+}
+
+func TestWhereHas(t *testing.T) {
+	defineAuthorRelations(t)
+
+	query := NewQueryBuilder().
+		Table("authors").
+		WhereHas("articles", func(q *QueryBuilder) {
+			q.Where("published", "=", true).OrWhere("featured", "=", true)
+		}).
+		WhereDoesntHave("agency", nil).
+		Build()
+
+	expectedSQL := "select * from authors where exists (select 1 from articles where articles.author_id = authors.id and (published = $1 or featured = $2))" +
+		" and not exists (select 1 from agencies where agencies.id = authors.agency_id)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+	if len(query.Params) != 2 {
+		t.Errorf("Expected 2 params, got: %v", query.Params)
+	}
+}
+
+func TestWhereHasPivotAndSelf(t *testing.T) {
+	defineAuthorRelations(t)
+
+	query := NewQueryBuilder().
+		Table("authors").
+		As("a").
+		WhereHas("awards", func(q *QueryBuilder) { q.Where("year", ">", 2020) }).
+		Build()
+
+	expectedSQL := "select * from authors as a where exists (select 1 from awards JOIN author_awards on author_awards.award_id = awards.id" +
+		" where author_awards.author_id = a.id and author_awards.confirmed = $1 and year > $2)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().Table("authors").WhereHas("mentor", nil).Build()
+	expectedSQL = "select * from authors where exists (select 1 from authors as mentor where mentor.id = authors.mentor_id)"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestWhereHasUnknownRelation(t *testing.T) {
+	defineAuthorRelations(t)
+
+	if _, err := NewQueryBuilder().Table("authors").WhereHas("fans", nil).TryBuild(); err == nil {
+		t.Error("Expected an error for an unknown relation")
+	}
+	if _, err := NewQueryBuilder().WhereHas("articles", nil).TryBuild(); err == nil {
+		t.Error("Expected an error without a table")
+	}
+}
+
+func TestWhereHasBaseFilter(t *testing.T) {
+	defineAuthorRelations(t)
+	ConfigureTable("articles", WithBaseFilter(func(q *QueryBuilder) {
+		q.WhereRaw("articles.deleted_at is null")
+	}))
+	t.Cleanup(func() { ConfigureTable("articles") })
+
+	query := NewQueryBuilder().
+		Table("authors").
+		WhereHas("articles", func(q *QueryBuilder) {
+			q.Where("published", "=", true).OrWhere("featured", "=", true)
+		}).
+		Build()
+
+	expectedSQL := "select * from authors where exists (select 1 from articles where articles.author_id = authors.id" +
+		" and articles.deleted_at is null and (published = $1 or featured = $2))"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// selectSubquery is a correlated subquery in the select list, see
+// WithCount
+type selectSubquery struct {
+	sub   *QueryBuilder
+	alias string
+}
+
+var aggregateFuncs = map[string]bool{"count": true, "sum": true, "avg": true, "min": true, "max": true}
+
+// WithCount adds the number of related rows of a relation registered with
+// DefineRelations as a "<relation>_count" column, computed by a correlated
+// subquery in the same select instead of one count query per row:
+//
+//	select *, (select count(*) from posts where posts.user_id = users.id) as posts_count from users
+func (b *QueryBuilder) WithCount(relation string) *QueryBuilder {
+	return b.WithAggregate(relation, "count", "*")
+}
+
+// WithAggregate adds fn - count, sum, avg, min or max - over column of the
+// related rows as a "<relation>_<fn>_<column>" column, e.g.
+// WithAggregate("orders", "sum", "total") selects orders_sum_total. Only
+// count takes "*". Rows without related rows get null, except for count,
+// which is 0.
+func (b *QueryBuilder) WithAggregate(relation, fn, column string) *QueryBuilder {
+	fn = strings.ToLower(fn)
+	if !aggregateFuncs[fn] || column == "*" && fn != "count" {
+		return b.fail(fmt.Errorf("unsupported aggregate %s(%s) for relation %q", fn, column, relation))
+	}
+	if column != "*" && !isIdentifier(column) {
+		return b.fail(unsafeIdentifier(column))
+	}
+	sub, inner, err := b.relationSubquery(relation, nil)
+	if err != nil {
+		return b.fail(err)
+	}
+
+	alias := relation + "_" + fn
+	if column == "*" {
+		sub.SelectRaw(fn + "(*)")
+	} else {
+		sub.SelectRaw(fn + "(" + inner + "." + column + ")")
+		alias += "_" + column
+	}
+
+	b.queryType = SelectQuery
+	b.selectSubs = append(b.selectSubs, selectSubquery{sub: sub, alias: alias})
+	return b
+}
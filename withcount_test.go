@@ -0,0 +1,65 @@
+package query
+
+import "testing"
+
+func defineShopRelations(t *testing.T) {
+	defineTestRelations(t, "shops",
+		HasMany("products", "shop_id", "id"),
+		HasMany("sales", "shop_id", "id"))
+}
+
+func TestWithCount(t *testing.T) {
+	defineShopRelations(t)
+
+	query := NewQueryBuilder().
+		Table("shops").
+		WithCount("products").
+		WithAggregate("sales", "SUM", "amount").
+		Where("active", "=", true).
+		Build()
+
+	expectedSQL := "select *, (select count(*) from products where products.shop_id = shops.id) as products_count," +
+		" (select sum(sales.amount) from sales where sales.shop_id = shops.id) as sales_sum_amount" +
+		" from shops where active = $1"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+
+	query = NewQueryBuilder().
+		Table("shops").
+		As("s").
+		Select("s.id", "s.name").
+		WithAggregate("sales", "max", "created_at").
+		Build()
+	expectedSQL = "select s.id, s.name, (select max(sales.created_at) from sales where sales.shop_id = s.id) as sales_max_created_at from shops as s"
+	if query.SQL != expectedSQL {
+		t.Errorf("Expected SQL: %s, got: %s", expectedSQL, query.SQL)
+	}
+}
+
+func TestWithAggregateErrors(t *testing.T) {
+	defineShopRelations(t)
+
+	tests := map[string]*QueryBuilder{
+		"unknown function": NewQueryBuilder().Table("shops").WithAggregate("sales", "median", "amount"),
+		"sum of star":      NewQueryBuilder().Table("shops").WithAggregate("sales", "sum", "*"),
+		"unsafe column":    NewQueryBuilder().Table("shops").WithAggregate("sales", "sum", "amount); drop"),
+		"unknown relation": NewQueryBuilder().Table("shops").WithCount("owners"),
+	}
+	for name, qb := range tests {
+		if _, err := qb.TryBuild(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWithCountClone(t *testing.T) {
+	defineShopRelations(t)
+
+	base := NewQueryBuilder().Table("shops").WithCount("products")
+	clone := base.Clone()
+	clone.selectSubs[0].sub.Where("visible", "=", true)
+	if len(base.selectSubs[0].sub.whereClauses) != 1 {
+		t.Error("Expected Clone to copy select subqueries")
+	}
+}